@@ -4,6 +4,7 @@ import (
 	"context"
 	"github.com/1URose/marketplace/internal/app"
 	"github.com/1URose/marketplace/internal/common/logger"
+	"os"
 
 	"log"
 )
@@ -12,6 +13,13 @@ func main() {
 	ctx := context.Background()
 	logger.Init()
 
+	if len(os.Args) > 1 && os.Args[1] == "create-admin" {
+		if err := app.CreateAdmin(ctx, os.Args[2:]); err != nil {
+			log.Fatalf("[cmd] create-admin failed: %v", err)
+		}
+		return
+	}
+
 	log.Println("[cmd] Starting marketplace-service...")
 
 	if err := app.Run(ctx); err != nil {
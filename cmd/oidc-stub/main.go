@@ -0,0 +1,267 @@
+// oidc-stub — локальный OpenID Connect провайдер для ручной и сквозной проверки входа через /auth/oidc.
+// Поддерживает только то, что нужно маркетплейсу: discovery, authorization code с PKCE S256,
+// client_secret_basic на /token и подписанный RS256 id_token. Ключ генерируется при каждом запуске.
+//
+// Пример:
+//
+//	go run ./cmd/oidc-stub -addr :9000 -issuer http://localhost:9000 \
+//	    -redirect-url http://localhost:8000/auth/oidc/stub/callback
+//
+// Страница /authorize показывает форму с email; параметр email в запросе входа подтверждает вход сразу,
+// а email_verified=false позволяет проверить отказ в привязке неподтверждённого адреса.
+package main
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"html/template"
+	"log"
+	"math/big"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+const (
+	keyID   = "stub"
+	codeTTL = time.Minute
+	idTTL   = 5 * time.Minute
+)
+
+type grant struct {
+	redirectURI   string
+	challenge     string
+	nonce         string
+	email         string
+	emailVerified bool
+	expiresAt     time.Time
+}
+
+type stub struct {
+	issuer       string
+	clientID     string
+	clientSecret string
+	redirectURL  string
+	key          *rsa.PrivateKey
+
+	mu     sync.Mutex
+	grants map[string]grant
+}
+
+var authorizeForm = template.Must(template.New("authorize").Parse(`<!doctype html>
+<html><body>
+<h1>OIDC stub</h1>
+<form method="get" action="/authorize">
+{{range $k, $v := .}}{{range $v}}<input type="hidden" name="{{$k}}" value="{{.}}">{{end}}{{end}}
+<label>Email <input type="email" name="email" required></label>
+<label><input type="checkbox" name="email_verified" value="false"> email не подтверждён</label>
+<button type="submit">Войти</button>
+</form>
+</body></html>`))
+
+func main() {
+	addr := flag.String("addr", ":9000", "адрес HTTP-сервера")
+	issuer := flag.String("issuer", "http://localhost:9000", "issuer, под которым заглушку видит маркетплейс")
+	clientID := flag.String("client-id", "marketplace", "client_id маркетплейса")
+	clientSecret := flag.String("client-secret", "marketplace-secret", "client_secret маркетплейса")
+	redirectURL := flag.String("redirect-url", "http://localhost:8000/auth/oidc/stub/callback", "разрешённый redirect_uri")
+	flag.Parse()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		log.Fatalf("[oidc-stub] generate key: %v", err)
+	}
+
+	s := newStub(*issuer, *clientID, *clientSecret, *redirectURL, key)
+
+	log.Printf("[oidc-stub] listening on %s, issuer=%s client_id=%s", *addr, *issuer, *clientID)
+	log.Fatal(http.ListenAndServe(*addr, s.routes()))
+}
+
+func newStub(issuer, clientID, clientSecret, redirectURL string, key *rsa.PrivateKey) *stub {
+	return &stub{
+		issuer:       issuer,
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		redirectURL:  redirectURL,
+		key:          key,
+		grants:       make(map[string]grant),
+	}
+}
+
+func (s *stub) routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", s.discovery)
+	mux.HandleFunc("/authorize", s.authorize)
+	mux.HandleFunc("/token", s.token)
+	mux.HandleFunc("/jwks", s.jwks)
+	return mux
+}
+
+func (s *stub) discovery(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"issuer":                                s.issuer,
+		"authorization_endpoint":                s.issuer + "/authorize",
+		"token_endpoint":                        s.issuer + "/token",
+		"jwks_uri":                              s.issuer + "/jwks",
+		"response_types_supported":              []string{"code"},
+		"subject_types_supported":               []string{"public"},
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+		"code_challenge_methods_supported":      []string{"S256"},
+		"token_endpoint_auth_methods_supported": []string{"client_secret_basic"},
+	})
+}
+
+func (s *stub) authorize(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	switch {
+	case q.Get("client_id") != s.clientID:
+		http.Error(w, "unknown client_id", http.StatusBadRequest)
+		return
+	case q.Get("redirect_uri") != s.redirectURL:
+		http.Error(w, "redirect_uri is not registered", http.StatusBadRequest)
+		return
+	case q.Get("response_type") != "code":
+		http.Error(w, "unsupported response_type", http.StatusBadRequest)
+		return
+	case q.Get("code_challenge_method") != "S256" || q.Get("code_challenge") == "":
+		http.Error(w, "PKCE S256 is required", http.StatusBadRequest)
+		return
+	}
+
+	email := q.Get("email")
+	if email == "" {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		if err := authorizeForm.Execute(w, q); err != nil {
+			log.Printf("[oidc-stub][ERROR] render form: %v", err)
+		}
+		return
+	}
+
+	code := randomHex(16)
+
+	s.mu.Lock()
+	s.grants[code] = grant{
+		redirectURI:   q.Get("redirect_uri"),
+		challenge:     q.Get("code_challenge"),
+		nonce:         q.Get("nonce"),
+		email:         email,
+		emailVerified: q.Get("email_verified") != "false",
+		expiresAt:     time.Now().Add(codeTTL),
+	}
+	s.mu.Unlock()
+
+	log.Printf("[oidc-stub] code issued for %s", email)
+
+	back, _ := url.Parse(q.Get("redirect_uri"))
+	params := back.Query()
+	params.Set("code", code)
+	params.Set("state", q.Get("state"))
+	back.RawQuery = params.Encode()
+
+	http.Redirect(w, r, back.String(), http.StatusFound)
+}
+
+func (s *stub) token(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, secret, ok := r.BasicAuth()
+	if !ok || id != s.clientID || subtle.ConstantTimeCompare([]byte(secret), []byte(s.clientSecret)) != 1 {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_client"})
+		return
+	}
+	if err := r.ParseForm(); err != nil || r.PostForm.Get("grant_type") != "authorization_code" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "unsupported_grant_type"})
+		return
+	}
+
+	code := r.PostForm.Get("code")
+
+	s.mu.Lock()
+	g, found := s.grants[code]
+	delete(s.grants, code)
+	s.mu.Unlock()
+
+	sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	challenge := base64.RawURLEncoding.EncodeToString(sum[:])
+
+	if !found || time.Now().After(g.expiresAt) ||
+		r.PostForm.Get("redirect_uri") != g.redirectURI || challenge != g.challenge {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	now := time.Now()
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":            s.issuer,
+		"aud":            s.clientID,
+		"sub":            "stub|" + g.email,
+		"iat":            now.Unix(),
+		"exp":            now.Add(idTTL).Unix(),
+		"nonce":          g.nonce,
+		"email":          g.email,
+		"email_verified": g.emailVerified,
+	})
+	token.Header["kid"] = keyID
+
+	idToken, err := token.SignedString(s.key)
+	if err != nil {
+		log.Printf("[oidc-stub][ERROR] sign id_token: %v", err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "server_error"})
+		return
+	}
+
+	log.Printf("[oidc-stub] id_token issued for %s", g.email)
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"access_token": randomHex(16),
+		"token_type":   "Bearer",
+		"expires_in":   int(idTTL.Seconds()),
+		"id_token":     idToken,
+	})
+}
+
+func (s *stub) jwks(w http.ResponseWriter, _ *http.Request) {
+	enc := base64.RawURLEncoding
+	pub := s.key.PublicKey
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"use": "sig",
+			"alg": "RS256",
+			"kid": keyID,
+			"n":   enc.EncodeToString(pub.N.Bytes()),
+			"e":   enc.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+		}},
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		log.Printf("[oidc-stub][ERROR] encode response: %v", err)
+	}
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		log.Fatalf("[oidc-stub] random: %v", err)
+	}
+	return hex.EncodeToString(b)
+}
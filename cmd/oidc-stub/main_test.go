@@ -0,0 +1,305 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	oidcEntity "github.com/1URose/marketplace/internal/auth_signup/domain/oidc/entity"
+	oidcRepo "github.com/1URose/marketplace/internal/auth_signup/domain/oidc/repository"
+	"github.com/1URose/marketplace/internal/auth_signup/infrastructure/oidc"
+	"github.com/1URose/marketplace/internal/auth_signup/use_cases"
+	oidcConfig "github.com/1URose/marketplace/internal/common/config/oidc"
+	"github.com/1URose/marketplace/internal/user_profile/domain/user/entity"
+)
+
+const (
+	testProvider    = "stub"
+	testRedirectURL = "http://marketplace.test/auth/oidc/stub/callback"
+)
+
+// loginFlow поднимает заглушку на httptest-сервере и OIDCService с настоящим oidc.Provider
+// и хранилищами в памяти.
+type loginFlow struct {
+	service *use_cases.OIDCService
+	users   *memoryUsers
+	client  *http.Client
+}
+
+func newLoginFlow(t *testing.T) *loginFlow {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+
+	srv := httptest.NewUnstartedServer(nil)
+	issuer := "http://" + srv.Listener.Addr().String()
+	srv.Config.Handler = newStub(issuer, "marketplace", "secret", testRedirectURL, key).routes()
+	srv.Start()
+	t.Cleanup(srv.Close)
+
+	provider := oidc.NewProvider(oidcConfig.Provider{
+		Name:         testProvider,
+		Issuer:       issuer,
+		ClientID:     "marketplace",
+		ClientSecret: "secret",
+		RedirectURL:  testRedirectURL,
+		Scopes:       []string{"openid", "email"},
+	})
+
+	users := &memoryUsers{byID: make(map[int]*entity.User)}
+	service := use_cases.NewOIDCService(
+		[]oidcRepo.IdentityProvider{provider},
+		&memoryRequests{byState: make(map[string]*oidcEntity.AuthRequest)},
+		&memoryIdentities{users: users, bySubject: make(map[string]int)},
+		users,
+		time.Minute,
+	)
+
+	return &loginFlow{
+		service: service,
+		users:   users,
+		// callback маркетплейса в тесте не поднят: редирект заглушки разбирается вручную
+		client: &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		}},
+	}
+}
+
+// authorize начинает вход и проходит страницу заглушки; override подменяет параметры запроса входа.
+// Возвращает code и state из редиректа на callback.
+func (f *loginFlow) authorize(t *testing.T, override url.Values) (string, string) {
+	t.Helper()
+
+	authURL, err := f.service.BeginLogin(context.Background(), testProvider, "test device")
+	if err != nil {
+		t.Fatalf("BeginLogin: %v", err)
+	}
+
+	u, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatalf("parse auth url: %v", err)
+	}
+	q := u.Query()
+	for k, v := range override {
+		q[k] = v
+	}
+	u.RawQuery = q.Encode()
+
+	resp, err := f.client.Get(u.String())
+	if err != nil {
+		t.Fatalf("authorize: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusFound {
+		t.Fatalf("authorize: status %d, want %d", resp.StatusCode, http.StatusFound)
+	}
+
+	back, err := url.Parse(resp.Header.Get("Location"))
+	if err != nil {
+		t.Fatalf("parse callback url: %v", err)
+	}
+	return back.Query().Get("code"), back.Query().Get("state")
+}
+
+func TestCompleteLoginCreatesUser(t *testing.T) {
+	f := newLoginFlow(t)
+
+	code, state := f.authorize(t, url.Values{"email": {"new@example.com"}})
+	user, device, err := f.service.CompleteLogin(context.Background(), testProvider, code, state)
+	if err != nil {
+		t.Fatalf("CompleteLogin: %v", err)
+	}
+	if user.Email != "new@example.com" || device != "test device" {
+		t.Fatalf("got user %q device %q", user.Email, device)
+	}
+
+	// повторный вход той же учётной записью приходит к тому же пользователю
+	code, state = f.authorize(t, url.Values{"email": {"new@example.com"}})
+	again, _, err := f.service.CompleteLogin(context.Background(), testProvider, code, state)
+	if err != nil {
+		t.Fatalf("second CompleteLogin: %v", err)
+	}
+	if again.ID != user.ID {
+		t.Fatalf("second login resolved to user %d, want %d", again.ID, user.ID)
+	}
+}
+
+func TestCompleteLoginRejectsReusedState(t *testing.T) {
+	f := newLoginFlow(t)
+
+	code, state := f.authorize(t, url.Values{"email": {"user@example.com"}})
+	if _, _, err := f.service.CompleteLogin(context.Background(), testProvider, code, state); err != nil {
+		t.Fatalf("CompleteLogin: %v", err)
+	}
+
+	_, _, err := f.service.CompleteLogin(context.Background(), testProvider, code, state)
+	if !errors.Is(err, oidcEntity.ErrInvalidState) {
+		t.Fatalf("reused state: got %v, want %v", err, oidcEntity.ErrInvalidState)
+	}
+}
+
+func TestCompleteLoginRejectsNonceMismatch(t *testing.T) {
+	f := newLoginFlow(t)
+
+	code, state := f.authorize(t, url.Values{"email": {"user@example.com"}, "nonce": {"forged"}})
+	_, _, err := f.service.CompleteLogin(context.Background(), testProvider, code, state)
+	if !errors.Is(err, oidcEntity.ErrNonceMismatch) {
+		t.Fatalf("nonce mismatch: got %v, want %v", err, oidcEntity.ErrNonceMismatch)
+	}
+}
+
+func TestCompleteLoginRejectsUnverifiedProviderEmail(t *testing.T) {
+	f := newLoginFlow(t)
+
+	code, state := f.authorize(t, url.Values{"email": {"user@example.com"}, "email_verified": {"false"}})
+	_, _, err := f.service.CompleteLogin(context.Background(), testProvider, code, state)
+	if !errors.Is(err, oidcEntity.ErrEmailNotVerified) {
+		t.Fatalf("email_verified=false: got %v, want %v", err, oidcEntity.ErrEmailNotVerified)
+	}
+	if len(f.users.byID) != 0 {
+		t.Fatalf("user created for unverified email")
+	}
+}
+
+func TestCompleteLoginLinksOnlyVerifiedLocalAccount(t *testing.T) {
+	f := newLoginFlow(t)
+
+	verifiedAt := time.Now()
+	verified := f.users.add(&entity.User{Email: "verified@example.com", PasswordHash: "hash", EmailVerifiedAt: &verifiedAt})
+	unverified := f.users.add(&entity.User{Email: "unverified@example.com", PasswordHash: "hash"})
+
+	code, state := f.authorize(t, url.Values{"email": {verified.Email}})
+	user, _, err := f.service.CompleteLogin(context.Background(), testProvider, code, state)
+	if err != nil {
+		t.Fatalf("CompleteLogin: %v", err)
+	}
+	if user.ID != verified.ID {
+		t.Fatalf("linked to user %d, want %d", user.ID, verified.ID)
+	}
+
+	code, state = f.authorize(t, url.Values{"email": {unverified.Email}})
+	_, _, err = f.service.CompleteLogin(context.Background(), testProvider, code, state)
+	if !errors.Is(err, oidcEntity.ErrLocalAccountNotVerified) {
+		t.Fatalf("unverified local account: got %v, want %v", err, oidcEntity.ErrLocalAccountNotVerified)
+	}
+}
+
+type memoryRequests struct {
+	mu      sync.Mutex
+	byState map[string]*oidcEntity.AuthRequest
+}
+
+func (r *memoryRequests) Save(_ context.Context, req *oidcEntity.AuthRequest, _ time.Duration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.byState[req.State] = req
+	return nil
+}
+
+func (r *memoryRequests) Take(_ context.Context, state string) (*oidcEntity.AuthRequest, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	req := r.byState[state]
+	delete(r.byState, state)
+	return req, nil
+}
+
+type memoryIdentities struct {
+	mu        sync.Mutex
+	users     *memoryUsers
+	bySubject map[string]int
+}
+
+func (r *memoryIdentities) GetUserID(_ context.Context, provider, subject string) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.bySubject[provider+"|"+subject], nil
+}
+
+func (r *memoryIdentities) Link(_ context.Context, identity *oidcEntity.Identity) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := identity.Provider + "|" + identity.Subject
+	if _, ok := r.bySubject[key]; ok {
+		return oidcEntity.ErrIdentityExists
+	}
+	r.bySubject[key] = identity.UserID
+	return nil
+}
+
+func (r *memoryIdentities) CreateUserWithIdentity(ctx context.Context, email string, identity *oidcEntity.Identity) (int, error) {
+	user := r.users.add(&entity.User{Email: email, Role: entity.RoleUser})
+	identity.UserID = user.ID
+	if err := r.Link(ctx, identity); err != nil {
+		return 0, err
+	}
+	return user.ID, nil
+}
+
+func (r *memoryIdentities) TouchLogin(context.Context, string, string) error {
+	return nil
+}
+
+type memoryUsers struct {
+	mu   sync.Mutex
+	byID map[int]*entity.User
+}
+
+func (r *memoryUsers) add(user *entity.User) *entity.User {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	user.ID = len(r.byID) + 1
+	r.byID[user.ID] = user
+	return user
+}
+
+func (r *memoryUsers) CreateUser(_ context.Context, user *entity.User) (*entity.User, error) {
+	return r.add(user), nil
+}
+
+func (r *memoryUsers) GetUserByEmail(_ context.Context, email string) (*entity.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, u := range r.byID {
+		if u.Email == email {
+			return u, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memoryUsers) GetUserByID(_ context.Context, id int) (*entity.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.byID[id], nil
+}
+
+func (r *memoryUsers) GetAllUsers(context.Context) ([]entity.User, error) {
+	return nil, nil
+}
+
+func (r *memoryUsers) SetRole(context.Context, int, string) error {
+	return nil
+}
+
+func (r *memoryUsers) UpdatePassword(context.Context, int, string) error {
+	return nil
+}
+
+func (r *memoryUsers) MarkEmailVerified(context.Context, int, string, time.Time) (bool, error) {
+	return false, nil
+}
+
+func (r *memoryUsers) UpdateEmail(context.Context, int, string, time.Time) error {
+	return nil
+}
@@ -0,0 +1,4 @@
+ALTER TABLE ads
+    ADD COLUMN category VARCHAR(32) NOT NULL DEFAULT 'other';
+
+CREATE INDEX idx_ads_category ON ads (category) WHERE status = 'published';
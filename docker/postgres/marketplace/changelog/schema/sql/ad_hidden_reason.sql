@@ -0,0 +1,4 @@
+-- кто скрыл объявление: автоматически по порогу жалоб или модератор. Объявления, скрытые до появления колонки,
+-- остаются с NULL и считаются скрытыми модератором: отклонение жалоб их в ленту не вернёт
+ALTER TABLE ads
+    ADD COLUMN hidden_reason VARCHAR(16) CHECK (hidden_reason IN ('reports', 'moderator'));
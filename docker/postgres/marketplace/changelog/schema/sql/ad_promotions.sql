@@ -0,0 +1,15 @@
+CREATE TABLE ad_promotions
+(
+    id         SERIAL PRIMARY KEY,
+    ad_id      INT         NOT NULL REFERENCES ads (id) ON DELETE CASCADE,
+    type       VARCHAR(16) NOT NULL CHECK (type IN ('top', 'highlight')),
+    starts_at  TIMESTAMPTZ NOT NULL,
+    ends_at    TIMESTAMPTZ NOT NULL,
+    created_by INT         REFERENCES users (id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CHECK (ends_at > starts_at)
+);
+
+CREATE INDEX idx_ad_promotions_period ON ad_promotions (starts_at, ends_at);
+
+CREATE INDEX idx_ad_promotions_ad ON ad_promotions (ad_id);
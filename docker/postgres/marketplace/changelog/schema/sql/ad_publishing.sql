@@ -0,0 +1,16 @@
+ALTER TABLE ads
+    ADD COLUMN status       VARCHAR(16) NOT NULL DEFAULT 'published' CHECK (status IN ('draft', 'scheduled', 'published')),
+    ADD COLUMN publish_at   TIMESTAMPTZ,
+    ADD COLUMN published_at TIMESTAMPTZ;
+
+UPDATE ads
+SET published_at = created_at;
+
+ALTER TABLE ads
+    ADD CONSTRAINT chk_ads_scheduled_publish_at CHECK (status <> 'scheduled' OR publish_at IS NOT NULL);
+
+CREATE INDEX idx_ads_published_at ON ads (published_at DESC) WHERE status = 'published';
+
+CREATE INDEX idx_ads_scheduled_publish_at ON ads (publish_at) WHERE status = 'scheduled';
+
+CREATE INDEX idx_ads_author ON ads (author_id);
@@ -0,0 +1,24 @@
+ALTER TABLE users
+    ADD COLUMN suspended_at TIMESTAMPTZ;
+
+ALTER TABLE ads
+    ADD COLUMN hidden_at TIMESTAMPTZ;
+
+CREATE TABLE ad_reports
+(
+    id          SERIAL PRIMARY KEY,
+    ad_id       INT         NOT NULL REFERENCES ads (id) ON DELETE CASCADE,
+    reporter_id INT         NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    reason      VARCHAR(32) NOT NULL,
+    comment     TEXT        NOT NULL DEFAULT '',
+    status      VARCHAR(16) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'dismissed', 'actioned')),
+    resolution  VARCHAR(32),
+    resolved_by INT         REFERENCES users (id) ON DELETE SET NULL,
+    resolved_at TIMESTAMPTZ,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- не больше одной открытой жалобы от пользователя на объявление
+CREATE UNIQUE INDEX uq_ad_reports_open_reporter ON ad_reports (ad_id, reporter_id) WHERE status = 'open';
+
+CREATE INDEX idx_ad_reports_status_ad ON ad_reports (status, ad_id);
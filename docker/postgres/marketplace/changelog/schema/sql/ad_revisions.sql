@@ -0,0 +1,31 @@
+CREATE TABLE ad_revisions
+(
+    id          SERIAL PRIMARY KEY,
+    ad_id       INTEGER      NOT NULL REFERENCES ads (id),
+    revision    INTEGER      NOT NULL CHECK (revision > 0),
+    title       VARCHAR(100) NOT NULL,
+    description TEXT         NOT NULL,
+    image_url   TEXT         NOT NULL,
+    price       BIGINT       NOT NULL,
+    edited_by   INTEGER      NOT NULL REFERENCES users (id),
+    created_at  TIMESTAMPTZ  NOT NULL DEFAULT now(),
+    UNIQUE (ad_id, revision)
+);
+
+INSERT INTO ad_revisions (ad_id, revision, title, description, image_url, price, edited_by, created_at)
+SELECT id, 1, title, description, image_url, price, author_id, created_at
+FROM ads;
+
+-- история правок используется при разборе споров, поэтому записи в ней нельзя менять или удалять
+CREATE FUNCTION ad_revisions_append_only() RETURNS trigger AS
+$$
+BEGIN
+    RAISE EXCEPTION 'ad_revisions is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_ad_revisions_append_only
+    BEFORE UPDATE OR DELETE
+    ON ad_revisions
+    FOR EACH ROW
+EXECUTE FUNCTION ad_revisions_append_only();
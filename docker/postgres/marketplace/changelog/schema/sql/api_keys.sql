@@ -0,0 +1,16 @@
+CREATE TABLE api_keys
+(
+    id           SERIAL PRIMARY KEY,
+    user_id      INT          NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    name         VARCHAR(100) NOT NULL,
+    -- открытая часть ключа (mk_<12 hex>): по ней ищется ключ, её же видит владелец в списке
+    prefix       VARCHAR(32)  NOT NULL UNIQUE,
+    -- SHA-256 от ключа целиком; сам ключ показывается только при создании
+    key_hash     CHAR(64)     NOT NULL,
+    scopes       TEXT[]       NOT NULL,
+    expires_at   TIMESTAMPTZ,
+    last_used_at TIMESTAMPTZ,
+    created_at   TIMESTAMPTZ  NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_api_keys_user_id ON api_keys (user_id);
@@ -0,0 +1,6 @@
+ALTER TABLE users
+    ADD COLUMN email_verified_at TIMESTAMPTZ;
+
+-- учётные записи, созданные до появления подтверждения, не лишаются возможности публиковать объявления
+UPDATE users
+SET email_verified_at = created_at;
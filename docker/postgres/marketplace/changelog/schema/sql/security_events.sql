@@ -0,0 +1,34 @@
+-- журнал событий безопасности; заменяет список последних событий в Redis.
+-- user_id без внешнего ключа: записи журнала должны пережить удаление пользователя
+CREATE TABLE security_events
+(
+    id         BIGSERIAL PRIMARY KEY,
+    user_id    INTEGER     NOT NULL,
+    type       VARCHAR(32) NOT NULL,
+    session_id VARCHAR(64) NOT NULL DEFAULT '',
+    ip         VARCHAR(45) NOT NULL DEFAULT '',
+    user_agent TEXT        NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_security_events_user_id_created_at ON security_events (user_id, created_at DESC);
+CREATE INDEX idx_security_events_created_at ON security_events (created_at);
+CREATE INDEX idx_security_events_ip ON security_events (ip);
+
+-- события нельзя менять; удалять их может только очистка по сроку хранения,
+-- которая выставляет в своей транзакции SET LOCAL security_events.purge = 'on'
+CREATE FUNCTION security_events_append_only() RETURNS trigger AS
+$$
+BEGIN
+    IF TG_OP = 'DELETE' AND current_setting('security_events.purge', true) = 'on' THEN
+        RETURN OLD;
+    END IF;
+    RAISE EXCEPTION 'security_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_security_events_append_only
+    BEFORE UPDATE OR DELETE
+    ON security_events
+    FOR EACH ROW
+EXECUTE FUNCTION security_events_append_only();
@@ -0,0 +1,14 @@
+CREATE TABLE user_identities
+(
+    id            SERIAL PRIMARY KEY,
+    user_id       INT          NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    provider      VARCHAR(32)  NOT NULL,
+    -- sub из ID-токена: постоянный идентификатор пользователя у провайдера, в отличие от email
+    subject       VARCHAR(255) NOT NULL,
+    email         VARCHAR(255) NOT NULL DEFAULT '',
+    created_at    TIMESTAMPTZ  NOT NULL DEFAULT now(),
+    last_login_at TIMESTAMPTZ  NOT NULL DEFAULT now(),
+    UNIQUE (provider, subject)
+);
+
+CREATE INDEX idx_user_identities_user ON user_identities (user_id);
@@ -0,0 +1,21 @@
+CREATE TABLE user_totp
+(
+    user_id        INT         PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
+    secret         VARCHAR(64) NOT NULL,
+    -- NULL, пока пользователь не подтвердил подключение кодом из приложения
+    enabled_at     TIMESTAMPTZ,
+    -- последний принятый 30-секундный шаг: код того же шага повторно не принимается
+    last_used_step BIGINT      NOT NULL DEFAULT 0,
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE user_recovery_codes
+(
+    id         SERIAL PRIMARY KEY,
+    user_id    INT          NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    code_hash  VARCHAR(255) NOT NULL,
+    used_at    TIMESTAMPTZ,
+    created_at TIMESTAMPTZ  NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_user_recovery_codes_unused ON user_recovery_codes (user_id) WHERE used_at IS NULL;
@@ -0,0 +1,4 @@
+-- роль заменяет список ADMIN_EMAILS; первого администратора создаёт команда marketplace create-admin
+ALTER TABLE users
+    ADD COLUMN role VARCHAR(16) NOT NULL DEFAULT 'user'
+        CONSTRAINT users_role_check CHECK (role IN ('user', 'moderator', 'admin'));
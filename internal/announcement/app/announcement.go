@@ -1,8 +1,11 @@
 package app
 
 import (
+	"github.com/1URose/marketplace/internal/announcement/infrastructure/repository/postgresql"
 	"github.com/1URose/marketplace/internal/announcement/transport/rest"
+	"github.com/1URose/marketplace/internal/announcement/use_cases"
 	"github.com/1URose/marketplace/internal/common/app"
+	"github.com/1URose/marketplace/internal/common/clock"
 	"log"
 )
 
@@ -11,4 +14,12 @@ func Run(deps *app.Deps) {
 
 	rest.RegisterRoutes(deps)
 	log.Println("[announcement] routers registered successfully")
+
+	scheduler := use_cases.NewPublishScheduler(
+		postgresql.NewAdRepository(deps.DB.PostgresConn),
+		deps.GeneralConfig.AdConfig.PublishSchedulerInterval,
+		clock.NewSystem(),
+	)
+	go scheduler.Run(deps.Ctx)
+	log.Println("[announcement] publish scheduler started")
 }
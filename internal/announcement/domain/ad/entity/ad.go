@@ -2,23 +2,50 @@ package entity
 
 import "time"
 
+const (
+	StatusDraft     = "draft"
+	StatusScheduled = "scheduled"
+	StatusPublished = "published"
+
+	DefaultCategory = "other"
+)
+
 type Ad struct {
 	ID          int
 	Title       string
 	Description string
 	ImageURL    string
 	Price       int
+	Category    string
 	AuthorID    int
 	AuthorEmail string
 	CreatedAt   time.Time
+	HiddenAt    *time.Time
+	IsPromoted  bool
+	Status      string
+	PublishAt   *time.Time
+	PublishedAt *time.Time
 }
 
-func NewAd(title, description, imageURL string, price int, authorID int) *Ad {
+func NewAd(title, description, imageURL string, price int, category string, authorID int) *Ad {
+	if category == "" {
+		category = DefaultCategory
+	}
 	return &Ad{
 		Title:       title,
 		Description: description,
 		ImageURL:    imageURL,
 		Price:       price,
+		Category:    category,
 		AuthorID:    authorID,
+		Status:      StatusPublished,
 	}
 }
+
+func (a *Ad) IsHidden() bool {
+	return a.HiddenAt != nil
+}
+
+func (a *Ad) IsPublished() bool {
+	return a.Status == StatusPublished
+}
@@ -0,0 +1,13 @@
+package entity
+
+import "github.com/1URose/marketplace/internal/common/apperror"
+
+var (
+	ErrAdNotFound       = apperror.NotFound("ad.not_found")
+	ErrNotAdOwner       = apperror.Forbidden("ad.not_owner")
+	ErrAlreadyPublished = apperror.Conflict("ad.already_published")
+	ErrNoChanges        = apperror.Conflict("ad.no_changes")
+	ErrRevisionNotFound = apperror.NotFound("ad.revision_not_found")
+	// ErrPageOutOfRange — запрошена страница за последней.
+	ErrPageOutOfRange = apperror.Validation("request.page_out_of_range")
+)
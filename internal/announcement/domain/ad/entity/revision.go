@@ -0,0 +1,57 @@
+package entity
+
+import "time"
+
+const (
+	FieldTitle       = "title"
+	FieldDescription = "description"
+	FieldImageURL    = "image_url"
+	FieldPrice       = "price"
+)
+
+// Revision — снимок содержимого объявления после очередной правки.
+// Первая ревизия фиксирует объявление в момент создания.
+type Revision struct {
+	ID          int
+	AdID        int
+	Number      int
+	Title       string
+	Description string
+	ImageURL    string
+	Price       int
+	EditorID    int
+	EditorEmail string
+	CreatedAt   time.Time
+}
+
+type FieldChange struct {
+	Field string
+	From  interface{}
+	To    interface{}
+}
+
+// AdChanges описывает правку объявления: nil-поля остаются без изменений.
+type AdChanges struct {
+	Title       *string
+	Description *string
+	ImageURL    *string
+	Price       *int
+}
+
+// Diff возвращает поля, значения которых различаются между двумя ревизиями.
+func Diff(from, to *Revision) []FieldChange {
+	changes := make([]FieldChange, 0)
+	if from.Title != to.Title {
+		changes = append(changes, FieldChange{Field: FieldTitle, From: from.Title, To: to.Title})
+	}
+	if from.Description != to.Description {
+		changes = append(changes, FieldChange{Field: FieldDescription, From: from.Description, To: to.Description})
+	}
+	if from.ImageURL != to.ImageURL {
+		changes = append(changes, FieldChange{Field: FieldImageURL, From: from.ImageURL, To: to.ImageURL})
+	}
+	if from.Price != to.Price {
+		changes = append(changes, FieldChange{Field: FieldPrice, From: from.Price, To: to.Price})
+	}
+	return changes
+}
@@ -4,10 +4,19 @@ import (
 	"context"
 	"github.com/1URose/marketplace/internal/announcement/domain/ad/entity"
 	entityAF "github.com/1URose/marketplace/internal/announcement/domain/ad_filter/entity"
+	"time"
 )
 
 type AdRepository interface {
 	CreateAd(ctx context.Context, ad *entity.Ad) (*entity.Ad, error)
 	GetAllAds(ctx context.Context, filter *entityAF.AdFilter) ([]*entity.Ad, error)
-	CountAds(ctx context.Context) (int, error)
+	CountAds(ctx context.Context, filter *entityAF.AdFilter) (int, error)
+	CountFacets(ctx context.Context, filter *entityAF.AdFilter, facets []string, priceEdges []int) (*entityAF.Facets, error)
+	GetAdByID(ctx context.Context, id int) (*entity.Ad, error)
+	GetAdsByAuthor(ctx context.Context, authorID int) ([]*entity.Ad, error)
+	PublishAd(ctx context.Context, adID, authorID int, now time.Time) (bool, error)
+	PublishDue(ctx context.Context, now time.Time) ([]int, error)
+	UpdateAd(ctx context.Context, adID, editorID int, changes *entity.AdChanges) (*entity.Revision, error)
+	GetRevisions(ctx context.Context, adID int) ([]*entity.Revision, error)
+	GetRevision(ctx context.Context, adID, number int) (*entity.Revision, error)
 }
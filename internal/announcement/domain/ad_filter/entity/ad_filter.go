@@ -1,5 +1,31 @@
 package entity
 
+import "time"
+
+const (
+	PeriodDay   = "day"
+	PeriodWeek  = "week"
+	PeriodMonth = "month"
+)
+
+// Periods — периоды свежести в порядке от самого короткого.
+var Periods = []string{PeriodDay, PeriodWeek, PeriodMonth}
+
+var periodDurations = map[string]time.Duration{
+	PeriodDay:   24 * time.Hour,
+	PeriodWeek:  7 * 24 * time.Hour,
+	PeriodMonth: 30 * 24 * time.Hour,
+}
+
+// PeriodSince возвращает начало периода, отсчитанного назад от now.
+func PeriodSince(period string, now time.Time) (time.Time, bool) {
+	d, ok := periodDurations[period]
+	if !ok {
+		return time.Time{}, false
+	}
+	return now.Add(-d), true
+}
+
 type AdFilter struct {
 	Page      int
 	PageSize  int
@@ -7,15 +33,26 @@ type AdFilter struct {
 	SortOrder string
 	MinPrice  *int
 	MaxPrice  *int
+	Category  *string
+	// Period ограничивает выдачу объявлениями, опубликованными за последний день, неделю или месяц.
+	Period string
+
+	// Now — момент, на который определяются активные продвижения.
+	Now           time.Time
+	PromotedSlots int
 }
 
-func NewAdFilter(page, pageSize int, sortBy, sortOrder string, minPrice, maxPrice *int) *AdFilter {
+func NewAdFilter(page, pageSize int, sortBy, sortOrder string, minPrice, maxPrice *int, category *string, period string, now time.Time, promotedSlots int) *AdFilter {
 	return &AdFilter{
-		Page:      page,
-		PageSize:  pageSize,
-		SortBy:    sortBy,
-		SortOrder: sortOrder,
-		MinPrice:  minPrice,
-		MaxPrice:  maxPrice,
+		Page:          page,
+		PageSize:      pageSize,
+		SortBy:        sortBy,
+		SortOrder:     sortOrder,
+		MinPrice:      minPrice,
+		MaxPrice:      maxPrice,
+		Category:      category,
+		Period:        period,
+		Now:           now,
+		PromotedSlots: promotedSlots,
 	}
 }
@@ -0,0 +1,43 @@
+package entity
+
+const (
+	FacetPrice     = "price"
+	FacetCategory  = "category"
+	FacetCreatedAt = "created_at"
+)
+
+// PriceBucket — диапазон цен [From, To); nil-граница означает открытый край.
+type PriceBucket struct {
+	From  *int
+	To    *int
+	Count int
+}
+
+type ValueCount struct {
+	Value string
+	Count int
+}
+
+// Facets — количество объявлений по значениям каждого запрошенного фасета.
+// Счётчики фасета учитывают все активные фильтры, кроме фильтра по самому фасету.
+type Facets struct {
+	Price     []PriceBucket
+	Category  []ValueCount
+	CreatedAt []ValueCount
+}
+
+// NewPriceBuckets строит пустые диапазоны по границам: до первой, между соседними и от последней.
+func NewPriceBuckets(edges []int) []PriceBucket {
+	buckets := make([]PriceBucket, len(edges)+1)
+	for i := range buckets {
+		if i > 0 {
+			from := edges[i-1]
+			buckets[i].From = &from
+		}
+		if i < len(edges) {
+			to := edges[i]
+			buckets[i].To = &to
+		}
+	}
+	return buckets
+}
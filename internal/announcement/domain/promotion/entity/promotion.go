@@ -0,0 +1,42 @@
+package entity
+
+import (
+	"github.com/1URose/marketplace/internal/common/apperror"
+	"time"
+)
+
+const (
+	TypeTop       = "top"
+	TypeHighlight = "highlight"
+)
+
+var (
+	ErrAdNotFound    = apperror.NotFound("ad.not_found")
+	ErrInvalidPeriod = apperror.Validation("promotion.invalid_period").
+				WithDetailKey("promotion.period_rule")
+)
+
+type Promotion struct {
+	ID        int
+	AdID      int
+	Type      string
+	StartsAt  time.Time
+	EndsAt    time.Time
+	CreatedBy int
+	CreatedAt time.Time
+}
+
+func NewPromotion(adID int, promotionType string, startsAt, endsAt time.Time, createdBy int) *Promotion {
+	return &Promotion{
+		AdID:      adID,
+		Type:      promotionType,
+		StartsAt:  startsAt,
+		EndsAt:    endsAt,
+		CreatedBy: createdBy,
+	}
+}
+
+// IsActive сообщает, действует ли продвижение в момент now: начало включительно, окончание — нет.
+func (p *Promotion) IsActive(now time.Time) bool {
+	return !now.Before(p.StartsAt) && now.Before(p.EndsAt)
+}
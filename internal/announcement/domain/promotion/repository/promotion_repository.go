@@ -0,0 +1,12 @@
+package repository
+
+import (
+	"context"
+	"github.com/1URose/marketplace/internal/announcement/domain/promotion/entity"
+	"time"
+)
+
+type PromotionRepository interface {
+	CreatePromotion(ctx context.Context, promotion *entity.Promotion) (*entity.Promotion, error)
+	GetActivePromotions(ctx context.Context, now time.Time) ([]*entity.Promotion, error)
+}
@@ -0,0 +1,18 @@
+package entity
+
+import "time"
+
+// AdReports — открытые жалобы, сгруппированные по объявлению, для разбора модератором.
+type AdReports struct {
+	AdID              int
+	AdTitle           string
+	AuthorID          int
+	AuthorEmail       string
+	AdHidden          bool
+	ReportsCount      int
+	DistinctReporters int
+	ReasonCounts      map[string]int
+	FirstReportedAt   time.Time
+	LastReportedAt    time.Time
+	Reports           []*Report
+}
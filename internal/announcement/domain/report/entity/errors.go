@@ -0,0 +1,13 @@
+package entity
+
+import "github.com/1URose/marketplace/internal/common/apperror"
+
+var (
+	ErrAdNotFound        = apperror.NotFound("ad.not_found")
+	ErrAlreadyReported   = apperror.Conflict("report.already_reported")
+	ErrOwnAd             = apperror.Validation("report.own_ad")
+	ErrNoOpenReports     = apperror.NotFound("report.no_open_reports")
+	ErrUnsupportedAction = apperror.Validation("report.unsupported_action")
+	// ErrPageOutOfRange — запрошена страница за последней.
+	ErrPageOutOfRange = apperror.Validation("request.page_out_of_range")
+)
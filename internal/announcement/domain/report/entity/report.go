@@ -0,0 +1,54 @@
+package entity
+
+import "time"
+
+const (
+	ReasonScam       = "scam"
+	ReasonSpam       = "spam"
+	ReasonProhibited = "prohibited"
+	ReasonOffensive  = "offensive"
+	ReasonDuplicate  = "duplicate"
+	ReasonWrongPrice = "wrong_price"
+	ReasonOther      = "other"
+)
+
+const (
+	StatusOpen      = "open"
+	StatusDismissed = "dismissed"
+	StatusActioned  = "actioned"
+)
+
+const (
+	ActionDismiss       = "dismiss"
+	ActionHideAd        = "hide_ad"
+	ActionSuspendAuthor = "suspend_author"
+)
+
+// Кто скрыл объявление. Отклонение жалоб возвращает в ленту только объявления, скрытые автоматически.
+const (
+	HiddenByReports   = "reports"
+	HiddenByModerator = "moderator"
+)
+
+type Report struct {
+	ID         int
+	AdID       int
+	ReporterID int
+	Reason     string
+	Comment    string
+	Status     string
+	Resolution *string
+	ResolvedBy *int
+	ResolvedAt *time.Time
+	CreatedAt  time.Time
+}
+
+func NewReport(adID, reporterID int, reason, comment string) *Report {
+	return &Report{
+		AdID:       adID,
+		ReporterID: reporterID,
+		Reason:     reason,
+		Comment:    comment,
+		Status:     StatusOpen,
+	}
+}
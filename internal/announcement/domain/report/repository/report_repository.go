@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+	"github.com/1URose/marketplace/internal/announcement/domain/report/entity"
+)
+
+type ReportRepository interface {
+	CreateReport(ctx context.Context, report *entity.Report) (*entity.Report, error)
+	CountDistinctOpenReporters(ctx context.Context, adID int) (int, error)
+	HideAd(ctx context.Context, adID int) error
+	GetOpenReportsGroupedByAd(ctx context.Context, offset, limit int) ([]*entity.AdReports, error)
+	CountAdsWithOpenReports(ctx context.Context) (int, error)
+	// Resolve закрывает открытые жалобы и применяет решение; ошибка beforeCommit откатывает его.
+	Resolve(ctx context.Context, adID, moderatorID int, action string, beforeCommit func(ctx context.Context) error) (int, error)
+}
@@ -2,12 +2,16 @@ package postgresql
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/1URose/marketplace/internal/announcement/domain/ad/entity"
 	entityAF "github.com/1URose/marketplace/internal/announcement/domain/ad_filter/entity"
 	"github.com/1URose/marketplace/internal/common/db/postgresql"
+	"github.com/jackc/pgx/v5"
 	"log"
+	"strconv"
 	"strings"
+	"time"
 )
 
 type AdRepository struct {
@@ -20,14 +24,24 @@ func NewAdRepository(connection *postgresql.Client) *AdRepository {
 }
 
 func (ar *AdRepository) CreateAd(ctx context.Context, ad *entity.Ad) (*entity.Ad, error) {
-	log.Printf("[repository:ad] CreateAd called: title=%q description=%q imageURL=%q price=%d authorID=%d",
-		ad.Title, ad.Description, ad.ImageURL, ad.Price, ad.AuthorID,
+	log.Printf("[repository:ad] CreateAd called: title=%q description=%q imageURL=%q price=%d authorID=%d status=%s",
+		ad.Title, ad.Description, ad.ImageURL, ad.Price, ad.AuthorID, ad.Status,
 	)
 
+	// первая ревизия пишется тем же запросом, чтобы у каждого объявления была полная история
 	const q = `
-        INSERT INTO ads (title, description, image_url, price, author_id)
-        VALUES ($1, $2, $3, $4, $5)
-        RETURNING id, created_at
+        WITH new_ad AS (
+            INSERT INTO ads (title, description, image_url, price, author_id, status, publish_at, published_at, category)
+            VALUES ($1, $2, $3, $4, $5, $6, $7, CASE WHEN $6 = 'published' THEN now() END, $8)
+            RETURNING id, title, description, image_url, price, author_id, created_at, published_at
+        ),
+        first_revision AS (
+            INSERT INTO ad_revisions (ad_id, revision, title, description, image_url, price, edited_by, created_at)
+            SELECT id, 1, title, description, image_url, price, author_id, created_at
+            FROM new_ad
+        )
+        SELECT id, created_at, published_at
+        FROM new_ad
     `
 	row := ar.Connection.GetPool().QueryRow(ctx, q,
 		ad.Title,
@@ -35,9 +49,12 @@ func (ar *AdRepository) CreateAd(ctx context.Context, ad *entity.Ad) (*entity.Ad
 		ad.ImageURL,
 		ad.Price,
 		ad.AuthorID,
+		ad.Status,
+		ad.PublishAt,
+		ad.Category,
 	)
 
-	if err := row.Scan(&ad.ID, &ad.CreatedAt); err != nil {
+	if err := row.Scan(&ad.ID, &ad.CreatedAt, &ad.PublishedAt); err != nil {
 		log.Printf("[repository:ad][ERROR] CreateAd scan failed: %v", err)
 		return nil, err
 	}
@@ -70,9 +87,13 @@ func (ar *AdRepository) GetAllAds(ctx context.Context, filter *entityAF.AdFilter
 			&a.Description,
 			&a.ImageURL,
 			&a.Price,
+			&a.Category,
 			&a.AuthorID,
 			&a.AuthorEmail,
 			&a.CreatedAt,
+			&a.Status,
+			&a.PublishedAt,
+			&a.IsPromoted,
 		); err != nil {
 			log.Printf("[repository:ad][ERROR] GetAllAds scan failed: %v", err)
 			return nil, fmt.Errorf("GetAllAds scan: %w", err)
@@ -84,48 +105,90 @@ func (ar *AdRepository) GetAllAds(ctx context.Context, filter *entityAF.AdFilter
 	return ads, nil
 }
 
+// buildGetAllAdsQuery собирает страницу ленты из двух частей: сначала PromotedSlots мест под объявления
+// с активным продвижением типа top, затем обычная выдача с сортировкой и пагинацией.
+// Продвигаемые объявления в обычную выдачу не попадают, поэтому количество страниц считается только по ней,
+// а закреплённые места на каждой странице заполняются по кругу, чтобы все продвигаемые объявления получали показы.
 func (ar *AdRepository) buildGetAllAdsQuery(adFilter *entityAF.AdFilter) (string, []interface{}) {
 	log.Printf("[repository:ad] buildGetAllAdsQuery called")
 
-	args := make([]interface{}, 0)
-	filters := make([]string, 0)
+	where, args := ar.buildAdsWhere(adFilter)
 
-	if adFilter.MinPrice != nil {
-		args = append(args, *adFilter.MinPrice)
-		filters = append(filters, fmt.Sprintf("price >= $%d", len(args)))
-		log.Printf("[repository:ad] buildGetAllAdsQuery: minPrice=%v", adFilter.MinPrice)
-	}
-	if adFilter.MaxPrice != nil {
-		args = append(args, *adFilter.MaxPrice)
-		filters = append(filters, fmt.Sprintf("price <= $%d", len(args)))
-	}
+	args = append(args, adFilter.Now)
+	nowArg := len(args)
 
 	sqlBuilder := strings.Builder{}
-	sqlBuilder.WriteString(`
-        SELECT 
-            a.id, a.title, a.description, a.image_url, a.price, 
-            a.author_id, u.email AS author_email, a.created_at
-        FROM ads a
-        JOIN users u ON a.author_id = u.id
-    `)
+	sqlBuilder.WriteString(fmt.Sprintf(`
+        WITH active_promotions AS (
+            SELECT ad_id, bool_or(type = 'top') AS is_top
+            FROM ad_promotions
+            WHERE starts_at <= $%d AND ends_at > $%d
+            GROUP BY ad_id
+        ),
+        feed AS (
+            SELECT 
+                a.id, a.title, a.description, a.image_url, a.price, a.category,
+                a.author_id, u.email AS author_email, a.created_at,
+                a.status, a.published_at,
+                p.ad_id IS NOT NULL AS is_promoted,
+                COALESCE(p.is_top, false) AS is_top
+            FROM ads a
+            JOIN users u ON a.author_id = u.id
+            LEFT JOIN active_promotions p ON p.ad_id = a.id
+    `, nowArg, nowArg))
 
-	if len(filters) > 0 {
-		sqlBuilder.WriteString(" WHERE ")
-		sqlBuilder.WriteString(strings.Join(filters, " AND "))
-	}
+	sqlBuilder.WriteString(where)
 
+	// лента упорядочивается по моменту публикации: отложенное объявление должно оказаться среди свежих,
+	// а не там, где был создан его черновик
 	sortBy := adFilter.SortBy
+	if sortBy == "created_at" {
+		sortBy = "published_at"
+	}
 	sortOrder := strings.ToUpper(adFilter.SortOrder)
 
-	sqlBuilder.WriteString(fmt.Sprintf(" ORDER BY %s %s", sortBy, sortOrder))
-
 	page := adFilter.Page
 
 	size := adFilter.PageSize
 
 	offset := (page - 1) * size
+
+	slots := adFilter.PromotedSlots
+	if slots < 0 {
+		slots = 0
+	}
+	args = append(args, (page-1)*slots, slots)
+	rotationArg, slotsArg := len(args)-1, len(args)
+
 	args = append(args, offset, size)
-	sqlBuilder.WriteString(fmt.Sprintf(" OFFSET $%d LIMIT $%d", len(args)-1, len(args)))
+	offsetArg, limitArg := len(args)-1, len(args)
+
+	sqlBuilder.WriteString(fmt.Sprintf(`
+        ),
+        pinned AS (
+            SELECT f.*,
+                (((row_number() OVER (ORDER BY f.id) - 1 - $%d) %% count(*) OVER ()) + count(*) OVER ()) %% count(*) OVER () AS pos
+            FROM feed f
+            WHERE f.is_top
+        ),
+        organic AS (
+            SELECT f.*, row_number() OVER (ORDER BY %s %s) AS pos
+            FROM feed f
+            WHERE NOT f.is_top
+        )
+        SELECT id, title, description, image_url, price, category, author_id, author_email, created_at, status, published_at, is_promoted
+        FROM (
+            (SELECT 0 AS section, p.* FROM pinned p WHERE p.pos < $%d)
+            UNION ALL
+            (SELECT 1 AS section, o.* FROM organic o ORDER BY o.pos OFFSET $%d LIMIT $%d)
+        ) page
+        ORDER BY section, pos
+    `,
+		rotationArg,
+		sortBy, sortOrder,
+		slotsArg,
+		offsetArg, limitArg,
+	))
 
 	log.Printf("[repository:ad] buildGetAllAdsQuery: sql=%s", sqlBuilder.String())
 
@@ -133,17 +196,507 @@ func (ar *AdRepository) buildGetAllAdsQuery(adFilter *entityAF.AdFilter) (string
 
 }
 
-func (ar *AdRepository) CountAds(ctx context.Context) (int, error) {
+// adsPredicates — условия выборки ленты. Условия по цене, категории и периоду хранятся отдельно,
+// чтобы фасет можно было посчитать без фильтра по самому себе.
+type adsPredicates struct {
+	base     []string
+	price    []string
+	category []string
+	period   []string
+}
+
+// buildAdsPredicates собирает условия выборки, общие для ленты, её подсчёта и фасетов:
+// черновики, ещё не опубликованные отложенные объявления, скрытые модерацией объявления
+// и объявления заблокированных авторов в ленту не попадают.
+func (ar *AdRepository) buildAdsPredicates(adFilter *entityAF.AdFilter, args *[]interface{}) *adsPredicates {
+	p := &adsPredicates{
+		base: []string{
+			"a.status = 'published'",
+			"a.hidden_at IS NULL",
+			"u.suspended_at IS NULL",
+		},
+	}
+
+	if adFilter.MinPrice != nil {
+		*args = append(*args, *adFilter.MinPrice)
+		p.price = append(p.price, fmt.Sprintf("a.price >= $%d", len(*args)))
+		log.Printf("[repository:ad] buildAdsPredicates: minPrice=%v", *adFilter.MinPrice)
+	}
+	if adFilter.MaxPrice != nil {
+		*args = append(*args, *adFilter.MaxPrice)
+		p.price = append(p.price, fmt.Sprintf("a.price <= $%d", len(*args)))
+		log.Printf("[repository:ad] buildAdsPredicates: maxPrice=%v", *adFilter.MaxPrice)
+	}
+	if adFilter.Category != nil {
+		*args = append(*args, *adFilter.Category)
+		p.category = append(p.category, fmt.Sprintf("a.category = $%d", len(*args)))
+		log.Printf("[repository:ad] buildAdsPredicates: category=%s", *adFilter.Category)
+	}
+	if since, ok := entityAF.PeriodSince(adFilter.Period, adFilter.Now); ok {
+		*args = append(*args, since)
+		p.period = append(p.period, fmt.Sprintf("a.published_at >= $%d", len(*args)))
+		log.Printf("[repository:ad] buildAdsPredicates: period=%s since=%s", adFilter.Period, since)
+	}
+
+	return p
+}
+
+// buildAdsWhere собирает WHERE со всеми условиями выборки ленты.
+func (ar *AdRepository) buildAdsWhere(adFilter *entityAF.AdFilter) (string, []interface{}) {
+	args := make([]interface{}, 0)
+	p := ar.buildAdsPredicates(adFilter, &args)
+
+	filters := append([]string{}, p.base...)
+	filters = append(filters, p.price...)
+	filters = append(filters, p.category...)
+	filters = append(filters, p.period...)
+
+	return " WHERE " + strings.Join(filters, " AND "), args
+}
+
+// andAll объединяет условия через AND; пустой список условий истинен.
+func andAll(predicates []string) string {
+	if len(predicates) == 0 {
+		return "TRUE"
+	}
+	return "(" + strings.Join(predicates, " AND ") + ")"
+}
+
+// CountAds считает объявления обычной выдачи: объявления с активным продвижением top
+// показываются в закреплённых местах и на количество страниц не влияют.
+func (ar *AdRepository) CountAds(ctx context.Context, filter *entityAF.AdFilter) (int, error) {
 	log.Printf("[repository:ad] CountAds called")
-	const q = `
-		SELECT count(*) FROM ads
-	`
+
+	where, args := ar.buildAdsWhere(filter)
+
+	args = append(args, filter.Now)
+
+	q := `
+		SELECT count(*)
+		FROM ads a
+		JOIN users u ON a.author_id = u.id
+	` + where + fmt.Sprintf(`
+		AND NOT EXISTS (
+			SELECT 1 FROM ad_promotions p
+			WHERE p.ad_id = a.id AND p.type = 'top' AND p.starts_at <= $%d AND p.ends_at > $%d
+		)
+	`, len(args), len(args))
+
 	var count int
 	err := ar.Connection.GetPool().
-		QueryRow(ctx, q).
+		QueryRow(ctx, q, args...).
 		Scan(&count)
 	if err != nil {
 		return 0, fmt.Errorf("CountAds scan: %w", err)
 	}
 	return count, nil
 }
+
+const adColumns = `
+    a.id, a.title, a.description, a.image_url, a.price, a.category,
+    a.author_id, u.email AS author_email, a.created_at, a.hidden_at,
+    a.status, a.publish_at, a.published_at
+`
+
+func scanAd(row pgx.Row) (*entity.Ad, error) {
+	a := new(entity.Ad)
+	err := row.Scan(
+		&a.ID,
+		&a.Title,
+		&a.Description,
+		&a.ImageURL,
+		&a.Price,
+		&a.Category,
+		&a.AuthorID,
+		&a.AuthorEmail,
+		&a.CreatedAt,
+		&a.HiddenAt,
+		&a.Status,
+		&a.PublishAt,
+		&a.PublishedAt,
+	)
+	return a, err
+}
+
+func (ar *AdRepository) GetAdByID(ctx context.Context, id int) (*entity.Ad, error) {
+	log.Printf("[repository:ad] GetAdByID called: id=%d", id)
+
+	q := `
+        SELECT ` + adColumns + `
+        FROM ads a
+        JOIN users u ON a.author_id = u.id
+        WHERE a.id = $1
+    `
+	a, err := scanAd(ar.Connection.GetPool().QueryRow(ctx, q, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		log.Printf("[repository:ad] GetAdByID: ad id=%d not found", id)
+		return nil, nil
+	}
+	if err != nil {
+		log.Printf("[repository:ad][ERROR] GetAdByID scan failed: %v", err)
+		return nil, fmt.Errorf("GetAdByID scan: %w", err)
+	}
+
+	return a, nil
+}
+
+func (ar *AdRepository) GetAdsByAuthor(ctx context.Context, authorID int) ([]*entity.Ad, error) {
+	log.Printf("[repository:ad] GetAdsByAuthor called: authorID=%d", authorID)
+
+	q := `
+        SELECT ` + adColumns + `
+        FROM ads a
+        JOIN users u ON a.author_id = u.id
+        WHERE a.author_id = $1
+        ORDER BY a.created_at DESC
+    `
+	rows, err := ar.Connection.GetPool().Query(ctx, q, authorID)
+	if err != nil {
+		log.Printf("[repository:ad][ERROR] GetAdsByAuthor query failed: %v", err)
+		return nil, fmt.Errorf("GetAdsByAuthor query: %w", err)
+	}
+	defer rows.Close()
+
+	ads := make([]*entity.Ad, 0)
+	for rows.Next() {
+		a, err := scanAd(rows)
+		if err != nil {
+			log.Printf("[repository:ad][ERROR] GetAdsByAuthor scan failed: %v", err)
+			return nil, fmt.Errorf("GetAdsByAuthor scan: %w", err)
+		}
+		ads = append(ads, a)
+	}
+
+	log.Printf("[repository:ad] GetAdsByAuthor succeeded: returned=%d", len(ads))
+
+	return ads, nil
+}
+
+// PublishAd публикует черновик или отложенное объявление автора немедленно.
+// Возвращает false, если объявления нет, оно чужое или уже опубликовано.
+func (ar *AdRepository) PublishAd(ctx context.Context, adID, authorID int, now time.Time) (bool, error) {
+	log.Printf("[repository:ad] PublishAd called: adID=%d authorID=%d", adID, authorID)
+
+	const q = `
+        UPDATE ads
+        SET status = 'published', published_at = $3
+        WHERE id = $1 AND author_id = $2 AND status <> 'published'
+    `
+	tag, err := ar.Connection.GetPool().Exec(ctx, q, adID, authorID, now)
+	if err != nil {
+		log.Printf("[repository:ad][ERROR] PublishAd failed: %v", err)
+		return false, fmt.Errorf("PublishAd exec: %w", err)
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+// PublishDue переводит в опубликованные все отложенные объявления, время публикации которых наступило.
+// Условие status = 'scheduled' делает операцию идемпотентной: после перезапуска или при нескольких
+// экземплярах сервиса каждое объявление публикуется ровно один раз.
+func (ar *AdRepository) PublishDue(ctx context.Context, now time.Time) ([]int, error) {
+	const q = `
+        UPDATE ads
+        SET status = 'published', published_at = publish_at
+        WHERE status = 'scheduled' AND publish_at <= $1
+        RETURNING id
+    `
+	rows, err := ar.Connection.GetPool().Query(ctx, q, now)
+	if err != nil {
+		log.Printf("[repository:ad][ERROR] PublishDue query failed: %v", err)
+		return nil, fmt.Errorf("PublishDue query: %w", err)
+	}
+	defer rows.Close()
+
+	ids := make([]int, 0)
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			log.Printf("[repository:ad][ERROR] PublishDue scan failed: %v", err)
+			return nil, fmt.Errorf("PublishDue scan: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("PublishDue rows: %w", err)
+	}
+
+	return ids, nil
+}
+
+// UpdateAd применяет правку объявления и в той же транзакции дописывает новую ревизию.
+// Возвращает nil, если правка не меняет ни одного поля.
+func (ar *AdRepository) UpdateAd(ctx context.Context, adID, editorID int, changes *entity.AdChanges) (*entity.Revision, error) {
+	log.Printf("[repository:ad] UpdateAd called: adID=%d editorID=%d", adID, editorID)
+
+	tx, err := ar.Connection.GetPool().Begin(ctx)
+	if err != nil {
+		log.Printf("[repository:ad][ERROR] UpdateAd begin tx failed: %v", err)
+		return nil, fmt.Errorf("UpdateAd begin tx: %w", err)
+	}
+	defer func() {
+		if err := tx.Rollback(ctx); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+			log.Printf("[repository:ad][ERROR] UpdateAd rollback failed: %v", err)
+		}
+	}()
+
+	// UPDATE блокирует строку объявления, поэтому параллельные правки выстраиваются в очередь
+	// и следующий запрос видит уже записанную ревизию
+	const updateQuery = `
+        UPDATE ads
+        SET title       = COALESCE($2, title),
+            description = COALESCE($3, description),
+            image_url   = COALESCE($4, image_url),
+            price       = COALESCE($5, price)
+        WHERE id = $1
+          AND (title, description, image_url, price) IS DISTINCT FROM
+              (COALESCE($2, title), COALESCE($3, description), COALESCE($4, image_url), COALESCE($5, price))
+    `
+	tag, err := tx.Exec(ctx, updateQuery, adID, changes.Title, changes.Description, changes.ImageURL, changes.Price)
+	if err != nil {
+		log.Printf("[repository:ad][ERROR] UpdateAd update failed: %v", err)
+		return nil, fmt.Errorf("UpdateAd update: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		log.Printf("[repository:ad] UpdateAd: nothing changed for adID=%d", adID)
+		return nil, nil
+	}
+
+	const revisionQuery = `
+        INSERT INTO ad_revisions (ad_id, revision, title, description, image_url, price, edited_by)
+        SELECT a.id,
+               (SELECT COALESCE(max(r.revision), 0) + 1 FROM ad_revisions r WHERE r.ad_id = a.id),
+               a.title, a.description, a.image_url, a.price, $2
+        FROM ads a
+        WHERE a.id = $1
+        RETURNING id, ad_id, revision, title, description, image_url, price, edited_by, created_at
+    `
+	rev := new(entity.Revision)
+	if err := tx.QueryRow(ctx, revisionQuery, adID, editorID).Scan(
+		&rev.ID,
+		&rev.AdID,
+		&rev.Number,
+		&rev.Title,
+		&rev.Description,
+		&rev.ImageURL,
+		&rev.Price,
+		&rev.EditorID,
+		&rev.CreatedAt,
+	); err != nil {
+		log.Printf("[repository:ad][ERROR] UpdateAd revision scan failed: %v", err)
+		return nil, fmt.Errorf("UpdateAd revision scan: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		log.Printf("[repository:ad][ERROR] UpdateAd commit failed: %v", err)
+		return nil, fmt.Errorf("UpdateAd commit: %w", err)
+	}
+
+	log.Printf("[repository:ad] UpdateAd succeeded: adID=%d revision=%d", adID, rev.Number)
+
+	return rev, nil
+}
+
+const revisionColumns = `
+    r.id, r.ad_id, r.revision, r.title, r.description, r.image_url, r.price,
+    r.edited_by, u.email, r.created_at
+`
+
+func scanRevision(row pgx.Row) (*entity.Revision, error) {
+	rev := new(entity.Revision)
+	err := row.Scan(
+		&rev.ID,
+		&rev.AdID,
+		&rev.Number,
+		&rev.Title,
+		&rev.Description,
+		&rev.ImageURL,
+		&rev.Price,
+		&rev.EditorID,
+		&rev.EditorEmail,
+		&rev.CreatedAt,
+	)
+	return rev, err
+}
+
+func (ar *AdRepository) GetRevisions(ctx context.Context, adID int) ([]*entity.Revision, error) {
+	log.Printf("[repository:ad] GetRevisions called: adID=%d", adID)
+
+	q := `
+        SELECT ` + revisionColumns + `
+        FROM ad_revisions r
+        JOIN users u ON u.id = r.edited_by
+        WHERE r.ad_id = $1
+        ORDER BY r.revision
+    `
+	rows, err := ar.Connection.GetPool().Query(ctx, q, adID)
+	if err != nil {
+		log.Printf("[repository:ad][ERROR] GetRevisions query failed: %v", err)
+		return nil, fmt.Errorf("GetRevisions query: %w", err)
+	}
+	defer rows.Close()
+
+	revisions := make([]*entity.Revision, 0)
+	for rows.Next() {
+		rev, err := scanRevision(rows)
+		if err != nil {
+			log.Printf("[repository:ad][ERROR] GetRevisions scan failed: %v", err)
+			return nil, fmt.Errorf("GetRevisions scan: %w", err)
+		}
+		revisions = append(revisions, rev)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("GetRevisions rows: %w", err)
+	}
+
+	log.Printf("[repository:ad] GetRevisions succeeded: returned=%d", len(revisions))
+
+	return revisions, nil
+}
+
+func (ar *AdRepository) GetRevision(ctx context.Context, adID, number int) (*entity.Revision, error) {
+	log.Printf("[repository:ad] GetRevision called: adID=%d revision=%d", adID, number)
+
+	q := `
+        SELECT ` + revisionColumns + `
+        FROM ad_revisions r
+        JOIN users u ON u.id = r.edited_by
+        WHERE r.ad_id = $1 AND r.revision = $2
+    `
+	rev, err := scanRevision(ar.Connection.GetPool().QueryRow(ctx, q, adID, number))
+	if errors.Is(err, pgx.ErrNoRows) {
+		log.Printf("[repository:ad] GetRevision: adID=%d revision=%d not found", adID, number)
+		return nil, nil
+	}
+	if err != nil {
+		log.Printf("[repository:ad][ERROR] GetRevision scan failed: %v", err)
+		return nil, fmt.Errorf("GetRevision scan: %w", err)
+	}
+
+	return rev, nil
+}
+
+// CountFacets считает запрошенные фасеты за один проход по ленте: каждое объявление один раз проверяется
+// на все фильтры, а счётчик фасета учитывает все фильтры, кроме своего собственного.
+// Продвигаемые объявления учитываются наравне с остальными — они тоже попадают в выдачу.
+func (ar *AdRepository) CountFacets(ctx context.Context, filter *entityAF.AdFilter, facets []string, priceEdges []int) (*entityAF.Facets, error) {
+	log.Printf("[repository:ad] CountFacets called: facets=%v", facets)
+
+	args := make([]interface{}, 0)
+	p := ar.buildAdsPredicates(filter, &args)
+
+	branches := make([]string, 0, len(facets))
+	for _, facet := range facets {
+		switch facet {
+		case entityAF.FacetPrice:
+			args = append(args, priceEdges)
+			branches = append(branches, fmt.Sprintf(`
+            SELECT 'price' AS facet, width_bucket(b.price, $%d::bigint[])::text AS value, count(*) AS cnt
+            FROM base b
+            WHERE b.category_ok AND b.period_ok
+            GROUP BY 2`, len(args)))
+		case entityAF.FacetCategory:
+			branches = append(branches, `
+            SELECT 'category', b.category, count(*)
+            FROM base b
+            WHERE b.price_ok AND b.period_ok
+            GROUP BY 2`)
+		case entityAF.FacetCreatedAt:
+			values := make([]string, 0, len(entityAF.Periods))
+			for _, period := range entityAF.Periods {
+				since, _ := entityAF.PeriodSince(period, filter.Now)
+				args = append(args, period, since)
+				values = append(values, fmt.Sprintf("($%d::text, $%d::timestamptz)", len(args)-1, len(args)))
+			}
+			branches = append(branches, fmt.Sprintf(`
+            SELECT 'created_at', pr.name, count(*)
+            FROM base b
+            JOIN (VALUES %s) AS pr(name, since) ON b.published_at >= pr.since
+            WHERE b.price_ok AND b.category_ok
+            GROUP BY 2`, strings.Join(values, ", ")))
+		}
+	}
+
+	result := &entityAF.Facets{}
+	if len(branches) == 0 {
+		return result, nil
+	}
+
+	q := fmt.Sprintf(`
+        WITH base AS MATERIALIZED (
+            SELECT a.price, a.category, a.published_at,
+                   %s AS price_ok,
+                   %s AS category_ok,
+                   %s AS period_ok
+            FROM ads a
+            JOIN users u ON a.author_id = u.id
+            WHERE %s
+        )
+        %s
+    `,
+		andAll(p.price),
+		andAll(p.category),
+		andAll(p.period),
+		strings.Join(p.base, " AND "),
+		strings.Join(branches, "\n            UNION ALL"),
+	)
+
+	log.Printf("[repository:ad] CountFacets: sql=%s", q)
+
+	rows, err := ar.Connection.GetPool().Query(ctx, q, args...)
+	if err != nil {
+		log.Printf("[repository:ad][ERROR] CountFacets query failed: %v", err)
+		return nil, fmt.Errorf("CountFacets query: %w", err)
+	}
+	defer rows.Close()
+
+	for _, facet := range facets {
+		switch facet {
+		case entityAF.FacetPrice:
+			result.Price = entityAF.NewPriceBuckets(priceEdges)
+		case entityAF.FacetCategory:
+			result.Category = make([]entityAF.ValueCount, 0)
+		case entityAF.FacetCreatedAt:
+			result.CreatedAt = make([]entityAF.ValueCount, len(entityAF.Periods))
+			for i, period := range entityAF.Periods {
+				result.CreatedAt[i].Value = period
+			}
+		}
+	}
+
+	for rows.Next() {
+		var (
+			facet, value string
+			count        int
+		)
+		if err := rows.Scan(&facet, &value, &count); err != nil {
+			log.Printf("[repository:ad][ERROR] CountFacets scan failed: %v", err)
+			return nil, fmt.Errorf("CountFacets scan: %w", err)
+		}
+
+		switch facet {
+		case entityAF.FacetPrice:
+			// width_bucket нумерует диапазоны с нуля: 0 — ниже первой границы
+			idx, err := strconv.Atoi(value)
+			if err != nil || idx < 0 || idx >= len(result.Price) {
+				return nil, fmt.Errorf("CountFacets: unexpected price bucket %q", value)
+			}
+			result.Price[idx].Count = count
+		case entityAF.FacetCategory:
+			result.Category = append(result.Category, entityAF.ValueCount{Value: value, Count: count})
+		case entityAF.FacetCreatedAt:
+			for i := range result.CreatedAt {
+				if result.CreatedAt[i].Value == value {
+					result.CreatedAt[i].Count = count
+				}
+			}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("CountFacets rows: %w", err)
+	}
+
+	log.Printf("[repository:ad] CountFacets succeeded")
+
+	return result, nil
+}
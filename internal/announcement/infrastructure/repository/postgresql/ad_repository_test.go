@@ -0,0 +1,203 @@
+package postgresql
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/1URose/marketplace/internal/announcement/domain/ad/entity"
+	entityAF "github.com/1URose/marketplace/internal/announcement/domain/ad_filter/entity"
+	promotionEntity "github.com/1URose/marketplace/internal/announcement/domain/promotion/entity"
+	"github.com/1URose/marketplace/internal/announcement/transport/rest/ad/dto"
+	"github.com/1URose/marketplace/internal/announcement/use_cases"
+	"github.com/1URose/marketplace/internal/common/clock"
+	pgConfig "github.com/1URose/marketplace/internal/common/config/postgresql"
+	"github.com/1URose/marketplace/internal/common/db/postgresql"
+)
+
+const (
+	testPageSize      = 3
+	testPromotedSlots = 2
+	testOrganicAds    = 5
+)
+
+// feedFixture — лента из отдельной категории: testOrganicAds обычных объявлений и два объявления
+// с продвижением top, действующим в [start, end).
+type feedFixture struct {
+	repo     *AdRepository
+	category string
+	promoted map[int]bool
+	start    time.Time
+	end      time.Time
+}
+
+// newFeedFixture требует отдельную базу с применёнными миграциями liquibase: PG_TEST_DB и PG_HOST, PG_PORT,
+// PG_USER, PG_PASSWORD. Тестовые объявления не удаляются — история правок append-only, — поэтому каждый запуск
+// пишет в свою категорию и не видит чужих данных.
+func newFeedFixture(t *testing.T) *feedFixture {
+	t.Helper()
+
+	db := os.Getenv("PG_TEST_DB")
+	if db == "" {
+		t.Skip("PG_TEST_DB is not set")
+	}
+
+	client, err := postgresql.NewClient(pgConfig.NewConfig(
+		os.Getenv("PG_HOST"), os.Getenv("PG_PORT"), os.Getenv("PG_USER"), os.Getenv("PG_PASSWORD"), db,
+		2, 1, time.Minute,
+	))
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	t.Cleanup(client.Close)
+
+	ctx := context.Background()
+	suffix := randomSuffix(t)
+
+	var authorID int
+	err = client.GetPool().QueryRow(ctx,
+		`INSERT INTO users (email, password_hash) VALUES ($1, 'x') RETURNING id`,
+		"feed-"+suffix+"@example.com",
+	).Scan(&authorID)
+	if err != nil {
+		t.Fatalf("create author: %v", err)
+	}
+
+	f := &feedFixture{
+		repo:     NewAdRepository(client),
+		category: "feed-" + suffix,
+		promoted: make(map[int]bool),
+		start:    time.Now().Add(-time.Hour).Truncate(time.Second),
+		end:      time.Now().Add(time.Hour).Truncate(time.Second),
+	}
+
+	for i := 0; i < testOrganicAds+2; i++ {
+		ad, err := f.repo.CreateAd(ctx, entity.NewAd("Feed ad", "Feed test ad", "https://example.com/ad.png", 100+i, f.category, authorID))
+		if err != nil {
+			t.Fatalf("create ad: %v", err)
+		}
+		if i < 2 {
+			f.promoted[ad.ID] = true
+		}
+	}
+
+	promotions := NewPromotionRepository(client)
+	for adID := range f.promoted {
+		_, err := promotions.CreatePromotion(ctx, promotionEntity.NewPromotion(adID, promotionEntity.TypeTop, f.start, f.end, authorID))
+		if err != nil {
+			t.Fatalf("create promotion: %v", err)
+		}
+	}
+
+	return f
+}
+
+func (f *feedFixture) service(now time.Time) *use_cases.AdService {
+	return use_cases.NewAdService(f.repo, testPageSize, testPromotedSlots, []string{f.category}, nil,
+		clock.Func(func() time.Time { return now }),
+	)
+}
+
+func (f *feedFixture) page(t *testing.T, now time.Time, page int) ([]*entity.Ad, int) {
+	t.Helper()
+
+	ads, pages, _, err := f.service(now).GetAllAds(context.Background(), &dto.GetAllAdsRequest{
+		Page:      page,
+		SortBy:    "created_at",
+		SortOrder: "desc",
+		Category:  &f.category,
+	})
+	if err != nil {
+		t.Fatalf("GetAllAds page %d: %v", page, err)
+	}
+	return ads, pages
+}
+
+func (f *feedFixture) count(t *testing.T, now time.Time) int {
+	t.Helper()
+
+	total, err := f.repo.CountAds(context.Background(), entityAF.NewAdFilter(
+		1, testPageSize, "created_at", "desc", nil, nil, &f.category, "", now, testPromotedSlots,
+	))
+	if err != nil {
+		t.Fatalf("CountAds: %v", err)
+	}
+	return total
+}
+
+func TestFeedPinsActiveTopPromotionsOnEveryPage(t *testing.T) {
+	f := newFeedFixture(t)
+	now := f.start.Add(time.Minute)
+
+	seen := make(map[int]bool)
+	for page := 1; page <= 2; page++ {
+		ads, pages := f.page(t, now, page)
+		if pages != 2 {
+			t.Fatalf("page %d: countPages=%d, want 2", page, pages)
+		}
+		if len(ads) < testPromotedSlots {
+			t.Fatalf("page %d: got %d ads", page, len(ads))
+		}
+
+		for i, ad := range ads {
+			pinned := i < testPromotedSlots
+			if pinned != f.promoted[ad.ID] || pinned != ad.IsPromoted {
+				t.Fatalf("page %d position %d: ad %d promoted=%t, want pinned=%t", page, i, ad.ID, ad.IsPromoted, pinned)
+			}
+			if !pinned {
+				if seen[ad.ID] {
+					t.Fatalf("page %d: organic ad %d shown twice", page, ad.ID)
+				}
+				seen[ad.ID] = true
+			}
+		}
+	}
+
+	if len(seen) != testOrganicAds {
+		t.Fatalf("organic ads across pages: got %d, want %d", len(seen), testOrganicAds)
+	}
+}
+
+func TestCountAdsExcludesPinnedAds(t *testing.T) {
+	f := newFeedFixture(t)
+
+	if total := f.count(t, f.start.Add(time.Minute)); total != testOrganicAds {
+		t.Fatalf("CountAds during promotion: got %d, want %d", total, testOrganicAds)
+	}
+	if total := f.count(t, f.start.Add(-time.Minute)); total != testOrganicAds+2 {
+		t.Fatalf("CountAds before promotion: got %d, want %d", total, testOrganicAds+2)
+	}
+}
+
+func TestPromotionLeavesPinnedSlotsAfterEnd(t *testing.T) {
+	f := newFeedFixture(t)
+
+	// окончание не входит в период продвижения
+	for _, now := range []time.Time{f.end, f.end.Add(time.Hour)} {
+		ads, pages := f.page(t, now, 1)
+		if pages != 3 {
+			t.Fatalf("now=%s: countPages=%d, want 3", now, pages)
+		}
+		for _, ad := range ads {
+			if ad.IsPromoted {
+				t.Fatalf("now=%s: ad %d still promoted", now, ad.ID)
+			}
+		}
+		if total := f.count(t, now); total != testOrganicAds+2 {
+			t.Fatalf("now=%s: CountAds=%d, want %d", now, total, testOrganicAds+2)
+		}
+	}
+}
+
+func randomSuffix(t *testing.T) string {
+	t.Helper()
+
+	b := make([]byte, 4)
+	if _, err := rand.Read(b); err != nil {
+		t.Fatalf("random: %v", err)
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,73 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+	"github.com/1URose/marketplace/internal/announcement/domain/promotion/entity"
+	"github.com/1URose/marketplace/internal/common/db/postgresql"
+	"log"
+	"time"
+)
+
+type PromotionRepository struct {
+	Connection *postgresql.Client
+}
+
+func NewPromotionRepository(connection *postgresql.Client) *PromotionRepository {
+	log.Printf("[repository:promotion] NewPromotionRepository initialized")
+	return &PromotionRepository{Connection: connection}
+}
+
+func (pr *PromotionRepository) CreatePromotion(ctx context.Context, promotion *entity.Promotion) (*entity.Promotion, error) {
+	log.Printf("[repository:promotion] CreatePromotion called: adID=%d type=%q startsAt=%s endsAt=%s",
+		promotion.AdID, promotion.Type, promotion.StartsAt.Format(time.RFC3339), promotion.EndsAt.Format(time.RFC3339),
+	)
+
+	const q = `
+        INSERT INTO ad_promotions (ad_id, type, starts_at, ends_at, created_by)
+        VALUES ($1, $2, $3, $4, $5)
+        RETURNING id, created_at
+    `
+	err := pr.Connection.GetPool().
+		QueryRow(ctx, q, promotion.AdID, promotion.Type, promotion.StartsAt, promotion.EndsAt, promotion.CreatedBy).
+		Scan(&promotion.ID, &promotion.CreatedAt)
+	if err != nil {
+		log.Printf("[repository:promotion][ERROR] CreatePromotion scan failed: %v", err)
+		return nil, fmt.Errorf("CreatePromotion scan: %w", err)
+	}
+
+	log.Printf("[repository:promotion] CreatePromotion succeeded: promotionID=%d", promotion.ID)
+
+	return promotion, nil
+}
+
+func (pr *PromotionRepository) GetActivePromotions(ctx context.Context, now time.Time) ([]*entity.Promotion, error) {
+	log.Printf("[repository:promotion] GetActivePromotions called: now=%s", now.Format(time.RFC3339))
+
+	const q = `
+        SELECT id, ad_id, type, starts_at, ends_at, COALESCE(created_by, 0), created_at
+        FROM ad_promotions
+        WHERE starts_at <= $1 AND ends_at > $1
+        ORDER BY ends_at
+    `
+	rows, err := pr.Connection.GetPool().Query(ctx, q, now)
+	if err != nil {
+		log.Printf("[repository:promotion][ERROR] GetActivePromotions query failed: %v", err)
+		return nil, fmt.Errorf("GetActivePromotions query: %w", err)
+	}
+	defer rows.Close()
+
+	promotions := make([]*entity.Promotion, 0)
+	for rows.Next() {
+		p := new(entity.Promotion)
+		if err := rows.Scan(&p.ID, &p.AdID, &p.Type, &p.StartsAt, &p.EndsAt, &p.CreatedBy, &p.CreatedAt); err != nil {
+			log.Printf("[repository:promotion][ERROR] GetActivePromotions scan failed: %v", err)
+			return nil, fmt.Errorf("GetActivePromotions scan: %w", err)
+		}
+		promotions = append(promotions, p)
+	}
+
+	log.Printf("[repository:promotion] GetActivePromotions succeeded: returned=%d", len(promotions))
+
+	return promotions, nil
+}
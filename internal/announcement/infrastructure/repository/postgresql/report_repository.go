@@ -0,0 +1,288 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/1URose/marketplace/internal/announcement/domain/report/entity"
+	"github.com/1URose/marketplace/internal/common/db/postgresql"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"log"
+)
+
+const pgUniqueViolation = "23505"
+
+type ReportRepository struct {
+	Connection *postgresql.Client
+}
+
+func NewReportRepository(connection *postgresql.Client) *ReportRepository {
+	log.Printf("[repository:report] NewReportRepository initialized")
+	return &ReportRepository{Connection: connection}
+}
+
+func (rr *ReportRepository) CreateReport(ctx context.Context, report *entity.Report) (*entity.Report, error) {
+	log.Printf("[repository:report] CreateReport called: adID=%d reporterID=%d reason=%q",
+		report.AdID, report.ReporterID, report.Reason,
+	)
+
+	const q = `
+        INSERT INTO ad_reports (ad_id, reporter_id, reason, comment)
+        VALUES ($1, $2, $3, $4)
+        RETURNING id, status, created_at
+    `
+	err := rr.Connection.GetPool().
+		QueryRow(ctx, q, report.AdID, report.ReporterID, report.Reason, report.Comment).
+		Scan(&report.ID, &report.Status, &report.CreatedAt)
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == pgUniqueViolation {
+		log.Printf("[repository:report][ERROR] CreateReport: open report already exists: adID=%d reporterID=%d",
+			report.AdID, report.ReporterID,
+		)
+		return nil, entity.ErrAlreadyReported
+	}
+	if err != nil {
+		log.Printf("[repository:report][ERROR] CreateReport scan failed: %v", err)
+		return nil, fmt.Errorf("CreateReport scan: %w", err)
+	}
+
+	log.Printf("[repository:report] CreateReport succeeded: reportID=%d", report.ID)
+
+	return report, nil
+}
+
+func (rr *ReportRepository) CountDistinctOpenReporters(ctx context.Context, adID int) (int, error) {
+	log.Printf("[repository:report] CountDistinctOpenReporters called: adID=%d", adID)
+
+	const q = `
+        SELECT count(DISTINCT reporter_id)
+        FROM ad_reports
+        WHERE ad_id = $1 AND status = 'open'
+    `
+	var count int
+	if err := rr.Connection.GetPool().QueryRow(ctx, q, adID).Scan(&count); err != nil {
+		log.Printf("[repository:report][ERROR] CountDistinctOpenReporters scan failed: %v", err)
+		return 0, fmt.Errorf("CountDistinctOpenReporters scan: %w", err)
+	}
+
+	return count, nil
+}
+
+func (rr *ReportRepository) HideAd(ctx context.Context, adID int) error {
+	log.Printf("[repository:report] HideAd called: adID=%d", adID)
+
+	const q = `
+        UPDATE ads
+        SET hidden_at = now(), hidden_reason = $2
+        WHERE id = $1 AND hidden_at IS NULL
+    `
+	if _, err := rr.Connection.GetPool().Exec(ctx, q, adID, entity.HiddenByReports); err != nil {
+		log.Printf("[repository:report][ERROR] HideAd failed: %v", err)
+		return fmt.Errorf("HideAd exec: %w", err)
+	}
+
+	return nil
+}
+
+func (rr *ReportRepository) CountAdsWithOpenReports(ctx context.Context) (int, error) {
+	log.Printf("[repository:report] CountAdsWithOpenReports called")
+
+	const q = `
+        SELECT count(DISTINCT ad_id)
+        FROM ad_reports
+        WHERE status = 'open'
+    `
+	var count int
+	if err := rr.Connection.GetPool().QueryRow(ctx, q).Scan(&count); err != nil {
+		log.Printf("[repository:report][ERROR] CountAdsWithOpenReports scan failed: %v", err)
+		return 0, fmt.Errorf("CountAdsWithOpenReports scan: %w", err)
+	}
+
+	return count, nil
+}
+
+func (rr *ReportRepository) GetOpenReportsGroupedByAd(ctx context.Context, offset, limit int) ([]*entity.AdReports, error) {
+	log.Printf("[repository:report] GetOpenReportsGroupedByAd called: offset=%d limit=%d", offset, limit)
+
+	const groupsQuery = `
+        SELECT
+            r.ad_id, a.title, a.author_id, u.email, a.hidden_at IS NOT NULL,
+            count(*), count(DISTINCT r.reporter_id), min(r.created_at), max(r.created_at)
+        FROM ad_reports r
+        JOIN ads a ON a.id = r.ad_id
+        JOIN users u ON u.id = a.author_id
+        WHERE r.status = 'open'
+        GROUP BY r.ad_id, a.title, a.author_id, u.email, a.hidden_at
+        ORDER BY count(DISTINCT r.reporter_id) DESC, max(r.created_at) DESC
+        OFFSET $1 LIMIT $2
+    `
+	rows, err := rr.Connection.GetPool().Query(ctx, groupsQuery, offset, limit)
+	if err != nil {
+		log.Printf("[repository:report][ERROR] GetOpenReportsGroupedByAd query failed: %v", err)
+		return nil, fmt.Errorf("GetOpenReportsGroupedByAd query: %w", err)
+	}
+	defer rows.Close()
+
+	groups := make([]*entity.AdReports, 0)
+	byAd := make(map[int]*entity.AdReports)
+	adIDs := make([]int, 0)
+	for rows.Next() {
+		g := &entity.AdReports{ReasonCounts: make(map[string]int)}
+		if err := rows.Scan(
+			&g.AdID,
+			&g.AdTitle,
+			&g.AuthorID,
+			&g.AuthorEmail,
+			&g.AdHidden,
+			&g.ReportsCount,
+			&g.DistinctReporters,
+			&g.FirstReportedAt,
+			&g.LastReportedAt,
+		); err != nil {
+			log.Printf("[repository:report][ERROR] GetOpenReportsGroupedByAd scan failed: %v", err)
+			return nil, fmt.Errorf("GetOpenReportsGroupedByAd scan: %w", err)
+		}
+		groups = append(groups, g)
+		byAd[g.AdID] = g
+		adIDs = append(adIDs, g.AdID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("GetOpenReportsGroupedByAd rows: %w", err)
+	}
+
+	if len(adIDs) == 0 {
+		return groups, nil
+	}
+
+	const reportsQuery = `
+        SELECT id, ad_id, reporter_id, reason, comment, status, created_at
+        FROM ad_reports
+        WHERE status = 'open' AND ad_id = ANY($1)
+        ORDER BY created_at
+    `
+	reportRows, err := rr.Connection.GetPool().Query(ctx, reportsQuery, adIDs)
+	if err != nil {
+		log.Printf("[repository:report][ERROR] GetOpenReportsGroupedByAd reports query failed: %v", err)
+		return nil, fmt.Errorf("GetOpenReportsGroupedByAd reports query: %w", err)
+	}
+	defer reportRows.Close()
+
+	for reportRows.Next() {
+		r := new(entity.Report)
+		if err := reportRows.Scan(
+			&r.ID,
+			&r.AdID,
+			&r.ReporterID,
+			&r.Reason,
+			&r.Comment,
+			&r.Status,
+			&r.CreatedAt,
+		); err != nil {
+			log.Printf("[repository:report][ERROR] GetOpenReportsGroupedByAd reports scan failed: %v", err)
+			return nil, fmt.Errorf("GetOpenReportsGroupedByAd reports scan: %w", err)
+		}
+		g := byAd[r.AdID]
+		g.Reports = append(g.Reports, r)
+		g.ReasonCounts[r.Reason]++
+	}
+	if err := reportRows.Err(); err != nil {
+		return nil, fmt.Errorf("GetOpenReportsGroupedByAd reports rows: %w", err)
+	}
+
+	log.Printf("[repository:report] GetOpenReportsGroupedByAd succeeded: groups=%d", len(groups))
+
+	return groups, nil
+}
+
+// Resolve закрывает все открытые жалобы на объявление и в той же транзакции применяет решение модератора.
+// Отклонение жалоб возвращает объявление в ленту, только если его скрыл порог жалоб, а автор не заблокирован;
+// скрытие модератором отменяет лишь модератор. beforeCommit, если задан, вызывается после применения решения
+// до фиксации транзакции; его ошибка откатывает решение целиком.
+func (rr *ReportRepository) Resolve(ctx context.Context, adID, moderatorID int, action string, beforeCommit func(ctx context.Context) error) (int, error) {
+	log.Printf("[repository:report] Resolve called: adID=%d moderatorID=%d action=%q", adID, moderatorID, action)
+
+	status := entity.StatusActioned
+	if action == entity.ActionDismiss {
+		status = entity.StatusDismissed
+	}
+
+	tx, err := rr.Connection.GetPool().Begin(ctx)
+	if err != nil {
+		log.Printf("[repository:report][ERROR] Resolve begin tx failed: %v", err)
+		return 0, fmt.Errorf("Resolve begin tx: %w", err)
+	}
+	defer func() {
+		if err := tx.Rollback(ctx); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+			log.Printf("[repository:report][ERROR] Resolve rollback failed: %v", err)
+		}
+	}()
+
+	const resolveQuery = `
+        UPDATE ad_reports
+        SET status = $1, resolution = $2, resolved_by = $3, resolved_at = now()
+        WHERE ad_id = $4 AND status = 'open'
+    `
+	tag, err := tx.Exec(ctx, resolveQuery, status, action, moderatorID, adID)
+	if err != nil {
+		log.Printf("[repository:report][ERROR] Resolve update reports failed: %v", err)
+		return 0, fmt.Errorf("Resolve update reports: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		log.Printf("[repository:report][ERROR] Resolve: no open reports for adID=%d", adID)
+		return 0, entity.ErrNoOpenReports
+	}
+
+	// скрытое автоматически объявление после решения модератора считается скрытым им
+	const hideQuery = `
+        UPDATE ads
+        SET hidden_at = COALESCE(hidden_at, now()), hidden_reason = $2
+        WHERE id = $1
+    `
+
+	switch action {
+	case entity.ActionDismiss:
+		_, err = tx.Exec(ctx, `
+            UPDATE ads
+            SET hidden_at = NULL, hidden_reason = NULL
+            WHERE id = $1
+              AND hidden_reason = $2
+              AND NOT EXISTS (SELECT 1 FROM users u WHERE u.id = ads.author_id AND u.suspended_at IS NOT NULL)
+        `, adID, entity.HiddenByReports)
+	case entity.ActionHideAd:
+		_, err = tx.Exec(ctx, hideQuery, adID, entity.HiddenByModerator)
+	case entity.ActionSuspendAuthor:
+		if _, err = tx.Exec(ctx, hideQuery, adID, entity.HiddenByModerator); err != nil {
+			break
+		}
+		_, err = tx.Exec(ctx, `
+            UPDATE users
+            SET suspended_at = now()
+            WHERE id = (SELECT author_id FROM ads WHERE id = $1) AND suspended_at IS NULL
+        `, adID)
+	default:
+		return 0, entity.ErrUnsupportedAction
+	}
+	if err != nil {
+		log.Printf("[repository:report][ERROR] Resolve apply action %q failed: %v", action, err)
+		return 0, fmt.Errorf("Resolve apply action: %w", err)
+	}
+
+	if beforeCommit != nil {
+		if err := beforeCommit(ctx); err != nil {
+			log.Printf("[repository:report][ERROR] Resolve before commit failed, rolling back: %v", err)
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		log.Printf("[repository:report][ERROR] Resolve commit failed: %v", err)
+		return 0, fmt.Errorf("Resolve commit: %w", err)
+	}
+
+	resolved := int(tag.RowsAffected())
+	log.Printf("[repository:report] Resolve succeeded: adID=%d resolved=%d", adID, resolved)
+
+	return resolved, nil
+}
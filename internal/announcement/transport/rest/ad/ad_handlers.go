@@ -1,29 +1,37 @@
 package ad
 
 import (
+	"errors"
 	"log"
 	"net/http"
+	"strconv"
 
+	"github.com/1URose/marketplace/internal/announcement/domain/ad/entity"
 	"github.com/1URose/marketplace/internal/announcement/transport/rest/ad/dto"
 	"github.com/1URose/marketplace/internal/announcement/use_cases"
-	dtoErr "github.com/1URose/marketplace/internal/common/transport/rest/dto"
+	"github.com/1URose/marketplace/internal/common/apperror"
+	"github.com/1URose/marketplace/internal/common/clock"
+	"github.com/1URose/marketplace/internal/common/transport/rest/bind"
 	"github.com/1URose/marketplace/internal/common/validator"
 	"github.com/gin-gonic/gin"
 )
 
+var errInvalidAdID = apperror.Validation("ad.invalid_id")
+
 type Handler struct {
 	service   *use_cases.AdService
 	validator *validator.AdAllowedValues
+	clock     clock.Clock
 }
 
-func NewHandler(service *use_cases.AdService, validator *validator.AdAllowedValues) *Handler {
+func NewHandler(service *use_cases.AdService, validator *validator.AdAllowedValues, clk clock.Clock) *Handler {
 	log.Println("[handler:ad] NewHandler initialized")
-	return &Handler{service: service, validator: validator}
+	return &Handler{service: service, validator: validator, clock: clk}
 }
 
 // CreateAd godoc
 // @Summary      Создать новое объявление
-// @Description  Создаёт объявление от имени текущего пользователя
+// @Description  Создаёт объявление от имени текущего пользователя. С draft=true объявление сохраняется черновиком, с publish_at — публикуется в указанный момент
 // @Tags         ads
 // @Accept       json
 // @Produce      json
@@ -42,30 +50,21 @@ func (h *Handler) CreateAd(ctx *gin.Context) {
 	emailStr, _ := userEmail.(string)
 
 	var req dto.CreateAdRequest
-	if err := ctx.ShouldBindJSON(&req); err != nil {
-		log.Println("[handler:ad][ERROR] bind body:", err)
-		ctx.AbortWithStatusJSON(http.StatusBadRequest, dtoErr.ErrorResponse{
-			Error:  "Invalid request body",
-			Detail: err.Error(),
-		})
-		return
+	// нарушения тегов binding и ограничений объявлений возвращаются вместе
+	err := bind.JSON(ctx, &req)
+	if bind.Parsed(err) {
+		err = bind.Merge(err, h.validator.ValidateCreateAd(req, h.clock.Now()))
 	}
-	if err := h.validator.ValidateCreateAd(req); err != nil {
+	if err != nil {
 		log.Println("[handler:ad][ERROR] ValidateCreateAd:", err)
-		ctx.AbortWithStatusJSON(http.StatusBadRequest, dtoErr.ErrorResponse{
-			Error:  "Invalid request body",
-			Detail: err.Error(),
-		})
+		ctx.Error(err)
 		return
 	}
 
 	adEntity, err := h.service.CreateAd(ctx, userId, &req)
 	if err != nil {
 		log.Println("[handler:ad][ERROR] CreateAd:", err)
-		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dtoErr.ErrorResponse{
-			Error:  "Failed to create ad",
-			Detail: err.Error(),
-		})
+		ctx.Error(err)
 		return
 	}
 	adEntity.AuthorEmail = emailStr
@@ -77,7 +76,7 @@ func (h *Handler) CreateAd(ctx *gin.Context) {
 
 // GetAllAds godoc
 // @Summary      Получить список объявлений
-// @Description  Возвращает постраничный, сортируемый и фильтруемый список объявлений
+// @Description  Возвращает постраничный, сортируемый и фильтруемый список объявлений. С параметром facets дополнительно возвращает количество объявлений по диапазонам цен, категориям и периодам публикации; счётчики каждого фасета учитывают все фильтры, кроме его собственного
 // @Tags         ads
 // @Accept       json
 // @Produce      json
@@ -87,6 +86,9 @@ func (h *Handler) CreateAd(ctx *gin.Context) {
 // @Param        sort_order    query  string false  "Порядок сортировки"               Enums(desc,asc)         default(desc)
 // @Param        min_price     query  int    false  "Минимальная цена фильтрации"      minimum(0)
 // @Param        max_price     query  int    false  "Максимальная цена фильтрации"     minimum(0)
+// @Param        category      query  string false  "Категория"
+// @Param        created_within query string false  "Опубликовано за последний период" Enums(day,week,month)
+// @Param        facets        query  string false  "Фасеты через запятую: price, category, created_at"
 // @Success      200           {array} dto.GetAllAdsResponse      "Список объявлений и количество страниц"
 // @Failure      400           {object} dto.ErrorResponse          "Неверные параметры запроса"
 // @Failure      500           {object} dto.ErrorResponse          "Внутренняя ошибка сервера"
@@ -95,30 +97,20 @@ func (h *Handler) GetAllAds(ctx *gin.Context) {
 	log.Println("[handler:ad] GetAllAds called")
 
 	var req dto.GetAllAdsRequest
-	if err := ctx.ShouldBindQuery(&req); err != nil {
-		log.Println("[handler:ad][ERROR] bind query:", err)
-		ctx.AbortWithStatusJSON(http.StatusBadRequest, dtoErr.ErrorResponse{
-			Error:  "Invalid request query",
-			Detail: err.Error(),
-		})
-		return
+	err := bind.Query(ctx, &req)
+	if bind.Parsed(err) {
+		err = bind.Merge(err, h.validator.ValidateGetAllAdsRequest(&req))
 	}
-	if err := h.validator.ValidateGetAllAdsRequest(&req); err != nil {
+	if err != nil {
 		log.Println("[handler:ad][ERROR] ValidateGetAllAdsRequest:", err)
-		ctx.AbortWithStatusJSON(http.StatusBadRequest, dtoErr.ErrorResponse{
-			Error:  err.Error(),
-			Detail: "Validation failed",
-		})
+		ctx.Error(err)
 		return
 	}
 
-	ads, countPages, err := h.service.GetAllAds(ctx, &req)
+	ads, countPages, facets, err := h.service.GetAllAds(ctx, &req)
 	if err != nil {
 		log.Println("[handler:ad][ERROR] GetAllAds:", err)
-		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dtoErr.ErrorResponse{
-			Error:  "Failed to get ads",
-			Detail: err.Error(),
-		})
+		ctx.Error(err)
 		return
 	}
 
@@ -130,7 +122,219 @@ func (h *Handler) GetAllAds(ctx *gin.Context) {
 		log.Println("[handler:ad] GetAllAds: guest access")
 	}
 
-	resp := dto.NewGetAllAdsResponse(ads, userId, countPages)
+	resp := dto.NewGetAllAdsResponse(ads, userId, countPages, facets)
 	log.Printf("[handler:ad] GetAllAds succeeded: returned=%d pages=%d", len(ads), countPages)
 	ctx.JSON(http.StatusOK, resp)
 }
+
+// GetMyAds godoc
+// @Summary      Мои объявления
+// @Description  Возвращает все объявления текущего пользователя, включая черновики и отложенные
+// @Tags         ads
+// @Produce      json
+// @Param        Authorization header string true "JWT Access token"
+// @Success      200           {array}  dto.AdBaseResponse "Объявления пользователя"
+// @Failure      401           {object} dto.ErrorResponse  "Неавторизован"
+// @Failure      500           {object} dto.ErrorResponse  "Внутренняя ошибка"
+// @Router       /ad/mine [get]
+func (h *Handler) GetMyAds(ctx *gin.Context) {
+	log.Println("[handler:ad] GetMyAds called")
+
+	userId := ctx.GetInt("userId")
+
+	ads, err := h.service.GetMyAds(ctx, userId)
+	if err != nil {
+		log.Println("[handler:ad][ERROR] GetMyAds:", err)
+		ctx.Error(err)
+		return
+	}
+
+	log.Printf("[handler:ad] GetMyAds succeeded: returned=%d", len(ads))
+	ctx.JSON(http.StatusOK, dto.NewAdsResponse(ads))
+}
+
+// PublishAd godoc
+// @Summary      Опубликовать объявление
+// @Description  Немедленно публикует черновик или отложенное объявление текущего пользователя
+// @Tags         ads
+// @Produce      json
+// @Param        Authorization header string true "JWT Access token"
+// @Param        id            path   int    true "ID объявления"
+// @Success      200           {object} dto.AdBaseResponse "Опубликованное объявление"
+// @Failure      400           {object} dto.ErrorResponse  "Неверный ID"
+// @Failure      401           {object} dto.ErrorResponse  "Неавторизован"
+// @Failure      404           {object} dto.ErrorResponse  "Объявление не найдено"
+// @Failure      409           {object} dto.ErrorResponse  "Объявление уже опубликовано"
+// @Failure      500           {object} dto.ErrorResponse  "Внутренняя ошибка"
+// @Router       /ad/{id}/publish [post]
+func (h *Handler) PublishAd(ctx *gin.Context) {
+	log.Println("[handler:ad] PublishAd called")
+
+	userId := ctx.GetInt("userId")
+
+	adID, ok := parseAdID(ctx)
+	if !ok {
+		return
+	}
+
+	ad, err := h.service.PublishAd(ctx, userId, adID)
+	if errors.Is(err, entity.ErrNotAdOwner) {
+		// чужие черновики не раскрываем
+		err = entity.ErrAdNotFound
+	}
+	if err != nil {
+		log.Println("[handler:ad][ERROR] PublishAd:", err)
+		ctx.Error(err)
+		return
+	}
+
+	resp := dto.NewAdBaseResponse(ad)
+	resp.IsMine = true
+
+	log.Printf("[handler:ad] PublishAd succeeded: adID=%d", adID)
+	ctx.JSON(http.StatusOK, resp)
+}
+
+// parseAdID разбирает ID объявления из пути и отвечает 400, если он некорректен.
+func parseAdID(ctx *gin.Context) (int, bool) {
+	adID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil || adID < 1 {
+		log.Printf("[handler:ad][ERROR] invalid ad id %q", ctx.Param("id"))
+		ctx.Error(errInvalidAdID)
+		return 0, false
+	}
+	return adID, true
+}
+
+// UpdateAd godoc
+// @Summary      Изменить объявление
+// @Description  Частично изменяет заголовок, описание, цену или изображение объявления текущего пользователя. Каждая правка сохраняется в истории ревизий
+// @Tags         ads
+// @Accept       json
+// @Produce      json
+// @Param        Authorization header  string                 true  "JWT Access token"
+// @Param        id            path    int                    true  "ID объявления"
+// @Param        ad            body    dto.UpdateAdRequest    true  "Изменяемые поля"
+// @Success      200           {object} dto.AdBaseResponse    "Изменённое объявление"
+// @Failure      400           {object} dto.ErrorResponse     "Неверные данные запроса"
+// @Failure      401           {object} dto.ErrorResponse     "Неавторизован"
+// @Failure      403           {object} dto.ErrorResponse     "Объявление принадлежит другому пользователю"
+// @Failure      404           {object} dto.ErrorResponse     "Объявление не найдено"
+// @Failure      409           {object} dto.ErrorResponse     "Правка ничего не меняет"
+// @Failure      500           {object} dto.ErrorResponse     "Внутренняя ошибка"
+// @Router       /ad/{id} [patch]
+func (h *Handler) UpdateAd(ctx *gin.Context) {
+	log.Println("[handler:ad] UpdateAd called")
+
+	userId := ctx.GetInt("userId")
+
+	adID, ok := parseAdID(ctx)
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateAdRequest
+	err := bind.JSON(ctx, &req)
+	if bind.Parsed(err) {
+		err = bind.Merge(err, h.validator.ValidateUpdateAd(req))
+	}
+	if err != nil {
+		log.Println("[handler:ad][ERROR] ValidateUpdateAd:", err)
+		ctx.Error(err)
+		return
+	}
+
+	ad, err := h.service.UpdateAd(ctx, userId, adID, &req)
+	if err != nil {
+		log.Println("[handler:ad][ERROR] UpdateAd:", err)
+		ctx.Error(err)
+		return
+	}
+
+	resp := dto.NewAdBaseResponse(ad)
+	resp.IsMine = true
+
+	log.Printf("[handler:ad] UpdateAd succeeded: adID=%d", adID)
+	ctx.JSON(http.StatusOK, resp)
+}
+
+// GetRevisions godoc
+// @Summary      История правок объявления
+// @Description  Возвращает все ревизии объявления: кто и когда изменил заголовок, описание, цену или изображение. Доступно автору и модераторам
+// @Tags         ads
+// @Produce      json
+// @Param        Authorization header string true "JWT Access token"
+// @Param        id            path   int    true "ID объявления"
+// @Success      200           {object} dto.GetRevisionsResponse "Ревизии объявления"
+// @Failure      400           {object} dto.ErrorResponse        "Неверный ID"
+// @Failure      401           {object} dto.ErrorResponse        "Неавторизован"
+// @Failure      403           {object} dto.ErrorResponse        "Недостаточно прав"
+// @Failure      404           {object} dto.ErrorResponse        "Объявление не найдено"
+// @Failure      500           {object} dto.ErrorResponse        "Внутренняя ошибка"
+// @Router       /ad/{id}/revisions [get]
+func (h *Handler) GetRevisions(ctx *gin.Context) {
+	log.Println("[handler:ad] GetRevisions called")
+
+	userId := ctx.GetInt("userId")
+	isModerator := ctx.GetBool("isModerator")
+
+	adID, ok := parseAdID(ctx)
+	if !ok {
+		return
+	}
+
+	revisions, err := h.service.GetRevisions(ctx, userId, isModerator, adID)
+	if err != nil {
+		log.Println("[handler:ad][ERROR] GetRevisions:", err)
+		ctx.Error(err)
+		return
+	}
+
+	log.Printf("[handler:ad] GetRevisions succeeded: adID=%d returned=%d", adID, len(revisions))
+	ctx.JSON(http.StatusOK, dto.NewGetRevisionsResponse(adID, revisions))
+}
+
+// DiffRevisions godoc
+// @Summary      Сравнить ревизии объявления
+// @Description  Возвращает поля, изменившиеся между двумя ревизиями объявления. Доступно автору и модераторам
+// @Tags         ads
+// @Produce      json
+// @Param        Authorization header string true "JWT Access token"
+// @Param        id            path   int    true "ID объявления"
+// @Param        from          query  int    true "Номер исходной ревизии" minimum(1)
+// @Param        to            query  int    true "Номер целевой ревизии"  minimum(1)
+// @Success      200           {object} dto.RevisionsDiffResponse "Изменённые поля"
+// @Failure      400           {object} dto.ErrorResponse         "Неверные параметры запроса"
+// @Failure      401           {object} dto.ErrorResponse         "Неавторизован"
+// @Failure      403           {object} dto.ErrorResponse         "Недостаточно прав"
+// @Failure      404           {object} dto.ErrorResponse         "Объявление или ревизия не найдены"
+// @Failure      500           {object} dto.ErrorResponse         "Внутренняя ошибка"
+// @Router       /ad/{id}/revisions/diff [get]
+func (h *Handler) DiffRevisions(ctx *gin.Context) {
+	log.Println("[handler:ad] DiffRevisions called")
+
+	userId := ctx.GetInt("userId")
+	isModerator := ctx.GetBool("isModerator")
+
+	adID, ok := parseAdID(ctx)
+	if !ok {
+		return
+	}
+
+	var req dto.RevisionsDiffRequest
+	if err := bind.Query(ctx, &req); err != nil {
+		log.Println("[handler:ad][ERROR] bind query:", err)
+		ctx.Error(err)
+		return
+	}
+
+	from, to, changes, err := h.service.DiffRevisions(ctx, userId, isModerator, adID, req.From, req.To)
+	if err != nil {
+		log.Println("[handler:ad][ERROR] DiffRevisions:", err)
+		ctx.Error(err)
+		return
+	}
+
+	log.Printf("[handler:ad] DiffRevisions succeeded: adID=%d changes=%d", adID, len(changes))
+	ctx.JSON(http.StatusOK, dto.NewRevisionsDiffResponse(adID, from, to, changes))
+}
@@ -11,20 +11,35 @@ type AdBaseResponse struct {
 	Description string `json:"description"`
 	ImageURL    string `json:"image_url"`
 	Price       int    `json:"price"`
+	Category    string `json:"category"`
 	AuthorID    int    `json:"author_id,omitempty"`
 	AuthorEmail string `json:"author_email"`
 	CreatedAt   string `json:"created_at"`
 	IsMine      bool   `json:"is_mine,omitempty"`
+	IsPromoted  bool   `json:"is_promoted"`
+	Status      string `json:"status"`
+	PublishAt   string `json:"publish_at,omitempty"`
+	PublishedAt string `json:"published_at,omitempty"`
 }
 
 func NewAdBaseResponse(ad *entity.Ad) AdBaseResponse {
-	return AdBaseResponse{
+	resp := AdBaseResponse{
 		ID:          ad.ID,
 		Title:       ad.Title,
 		Description: ad.Description,
 		ImageURL:    ad.ImageURL,
 		Price:       ad.Price,
+		Category:    ad.Category,
 		AuthorEmail: ad.AuthorEmail,
 		CreatedAt:   ad.CreatedAt.Format(time.RFC3339),
+		IsPromoted:  ad.IsPromoted,
+		Status:      ad.Status,
 	}
+	if ad.PublishAt != nil {
+		resp.PublishAt = ad.PublishAt.Format(time.RFC3339)
+	}
+	if ad.PublishedAt != nil {
+		resp.PublishedAt = ad.PublishedAt.Format(time.RFC3339)
+	}
+	return resp
 }
@@ -1,8 +1,17 @@
 package dto
 
+import "time"
+
 type CreateAdRequest struct {
 	Title       string `json:"title" binding:"required"`
 	Description string `json:"description" binding:"required"`
 	ImageURL    string `json:"image_url" binding:"required,url"`
 	Price       int    `json:"price" binding:"required"`
+	// Category по умолчанию — other.
+	Category string `json:"category"`
+
+	// Draft сохраняет объявление черновиком, видимым только автору.
+	Draft bool `json:"draft"`
+	// PublishAt откладывает публикацию до указанного момента.
+	PublishAt *time.Time `json:"publish_at"`
 }
@@ -0,0 +1,39 @@
+package dto
+
+import entityAF "github.com/1URose/marketplace/internal/announcement/domain/ad_filter/entity"
+
+// PriceBucketResponse — диапазон цен [from, to); отсутствующая граница означает открытый край.
+type PriceBucketResponse struct {
+	From  *int `json:"from"`
+	To    *int `json:"to"`
+	Count int  `json:"count"`
+}
+
+type ValueCountResponse struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+type FacetsResponse struct {
+	Price     []PriceBucketResponse `json:"price,omitempty"`
+	Category  []ValueCountResponse  `json:"category,omitempty"`
+	CreatedAt []ValueCountResponse  `json:"created_at,omitempty"`
+}
+
+func NewFacetsResponse(facets *entityAF.Facets) *FacetsResponse {
+	if facets == nil {
+		return nil
+	}
+
+	resp := &FacetsResponse{}
+	for _, b := range facets.Price {
+		resp.Price = append(resp.Price, PriceBucketResponse{From: b.From, To: b.To, Count: b.Count})
+	}
+	for _, c := range facets.Category {
+		resp.Category = append(resp.Category, ValueCountResponse{Value: c.Value, Count: c.Count})
+	}
+	for _, c := range facets.CreatedAt {
+		resp.CreatedAt = append(resp.CreatedAt, ValueCountResponse{Value: c.Value, Count: c.Count})
+	}
+	return resp
+}
@@ -1,9 +1,32 @@
 package dto
 
+import "strings"
+
 type GetAllAdsRequest struct {
-	Page      int    `form:"page,default=1" binding:"min=1"`
-	SortBy    string `form:"sort_by,default=created_at"`
-	SortOrder string `form:"sort_order,default=desc" binding:"oneof=asc desc"`
-	MinPrice  *int   `form:"min_price" binding:"omitempty,min=0"`
-	MaxPrice  *int   `form:"max_price" binding:"omitempty,min=0"`
+	Page          int     `form:"page,default=1" binding:"min=1"`
+	SortBy        string  `form:"sort_by,default=created_at"`
+	SortOrder     string  `form:"sort_order,default=desc" binding:"oneof=asc desc"`
+	MinPrice      *int    `form:"min_price" binding:"omitempty,min=0"`
+	MaxPrice      *int    `form:"max_price" binding:"omitempty,min=0"`
+	Category      *string `form:"category"`
+	CreatedWithin string  `form:"created_within" binding:"omitempty,oneof=day week month"`
+	// Facets — список фасетов через запятую: price, category, created_at.
+	Facets string `form:"facets"`
+}
+
+func (r *GetAllAdsRequest) FacetList() []string {
+	facets := make([]string, 0)
+	seen := make(map[string]struct{})
+	for _, f := range strings.Split(r.Facets, ",") {
+		f = strings.ToLower(strings.TrimSpace(f))
+		if f == "" {
+			continue
+		}
+		if _, ok := seen[f]; ok {
+			continue
+		}
+		seen[f] = struct{}{}
+		facets = append(facets, f)
+	}
+	return facets
 }
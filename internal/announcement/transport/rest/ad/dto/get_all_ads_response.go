@@ -1,13 +1,17 @@
 package dto
 
-import "github.com/1URose/marketplace/internal/announcement/domain/ad/entity"
+import (
+	"github.com/1URose/marketplace/internal/announcement/domain/ad/entity"
+	entityAF "github.com/1URose/marketplace/internal/announcement/domain/ad_filter/entity"
+)
 
 type GetAllAdsResponse struct {
 	Ads        []AdBaseResponse `json:"ads"`
 	CountPages int              `json:"count_pages"`
+	Facets     *FacetsResponse  `json:"facets,omitempty"`
 }
 
-func NewGetAllAdsResponse(ads []*entity.Ad, userID, countPages int) *GetAllAdsResponse {
+func NewGetAllAdsResponse(ads []*entity.Ad, userID, countPages int, facets *entityAF.Facets) *GetAllAdsResponse {
 	resp := make([]AdBaseResponse, len(ads))
 	for i, a := range ads {
 		base := NewAdBaseResponse(a)
@@ -17,5 +21,15 @@ func NewGetAllAdsResponse(ads []*entity.Ad, userID, countPages int) *GetAllAdsRe
 		}
 		resp[i] = base
 	}
-	return &GetAllAdsResponse{Ads: resp, CountPages: countPages}
+	return &GetAllAdsResponse{Ads: resp, CountPages: countPages, Facets: NewFacetsResponse(facets)}
+}
+
+func NewAdsResponse(ads []*entity.Ad) []AdBaseResponse {
+	resp := make([]AdBaseResponse, len(ads))
+	for i, a := range ads {
+		base := NewAdBaseResponse(a)
+		base.IsMine = true
+		resp[i] = base
+	}
+	return resp
 }
@@ -0,0 +1,34 @@
+package dto
+
+import "github.com/1URose/marketplace/internal/announcement/domain/ad/entity"
+
+type RevisionsDiffRequest struct {
+	From int `form:"from" binding:"required,min=1"`
+	To   int `form:"to" binding:"required,min=1"`
+}
+
+type FieldChangeResponse struct {
+	Field string      `json:"field"`
+	From  interface{} `json:"from"`
+	To    interface{} `json:"to"`
+}
+
+type RevisionsDiffResponse struct {
+	AdID    int                   `json:"ad_id"`
+	From    RevisionResponse      `json:"from"`
+	To      RevisionResponse      `json:"to"`
+	Changes []FieldChangeResponse `json:"changes"`
+}
+
+func NewRevisionsDiffResponse(adID int, from, to *entity.Revision, changes []entity.FieldChange) *RevisionsDiffResponse {
+	resp := &RevisionsDiffResponse{
+		AdID:    adID,
+		From:    NewRevisionResponse(from),
+		To:      NewRevisionResponse(to),
+		Changes: make([]FieldChangeResponse, len(changes)),
+	}
+	for i, c := range changes {
+		resp.Changes[i] = FieldChangeResponse{Field: c.Field, From: c.From, To: c.To}
+	}
+	return resp
+}
@@ -0,0 +1,46 @@
+package dto
+
+import (
+	"github.com/1URose/marketplace/internal/announcement/domain/ad/entity"
+	"time"
+)
+
+type RevisionResponse struct {
+	Revision    int    `json:"revision"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	ImageURL    string `json:"image_url"`
+	Price       int    `json:"price"`
+	EditedBy    int    `json:"edited_by"`
+	EditorEmail string `json:"editor_email,omitempty"`
+	CreatedAt   string `json:"created_at"`
+}
+
+func NewRevisionResponse(rev *entity.Revision) RevisionResponse {
+	return RevisionResponse{
+		Revision:    rev.Number,
+		Title:       rev.Title,
+		Description: rev.Description,
+		ImageURL:    rev.ImageURL,
+		Price:       rev.Price,
+		EditedBy:    rev.EditorID,
+		EditorEmail: rev.EditorEmail,
+		CreatedAt:   rev.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+type GetRevisionsResponse struct {
+	AdID      int                `json:"ad_id"`
+	Revisions []RevisionResponse `json:"revisions"`
+}
+
+func NewGetRevisionsResponse(adID int, revisions []*entity.Revision) *GetRevisionsResponse {
+	resp := &GetRevisionsResponse{
+		AdID:      adID,
+		Revisions: make([]RevisionResponse, len(revisions)),
+	}
+	for i, rev := range revisions {
+		resp.Revisions[i] = NewRevisionResponse(rev)
+	}
+	return resp
+}
@@ -0,0 +1,24 @@
+package dto
+
+import "github.com/1URose/marketplace/internal/announcement/domain/ad/entity"
+
+// UpdateAdRequest — частичная правка объявления: незаданные поля не меняются.
+type UpdateAdRequest struct {
+	Title       *string `json:"title"`
+	Description *string `json:"description"`
+	ImageURL    *string `json:"image_url" binding:"omitempty,url"`
+	Price       *int    `json:"price"`
+}
+
+func (r *UpdateAdRequest) IsEmpty() bool {
+	return r.Title == nil && r.Description == nil && r.ImageURL == nil && r.Price == nil
+}
+
+func (r *UpdateAdRequest) ToChanges() *entity.AdChanges {
+	return &entity.AdChanges{
+		Title:       r.Title,
+		Description: r.Description,
+		ImageURL:    r.ImageURL,
+		Price:       r.Price,
+	}
+}
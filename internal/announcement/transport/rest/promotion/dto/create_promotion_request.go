@@ -0,0 +1,10 @@
+package dto
+
+import "time"
+
+type CreatePromotionRequest struct {
+	AdID     int       `json:"ad_id" binding:"required,min=1"`
+	Type     string    `json:"type" binding:"required,oneof=top highlight"`
+	StartsAt time.Time `json:"starts_at" binding:"required"`
+	EndsAt   time.Time `json:"ends_at" binding:"required"`
+}
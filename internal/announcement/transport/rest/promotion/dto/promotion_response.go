@@ -0,0 +1,36 @@
+package dto
+
+import (
+	"github.com/1URose/marketplace/internal/announcement/domain/promotion/entity"
+	"time"
+)
+
+type PromotionResponse struct {
+	ID        int    `json:"id"`
+	AdID      int    `json:"ad_id"`
+	Type      string `json:"type"`
+	StartsAt  string `json:"starts_at"`
+	EndsAt    string `json:"ends_at"`
+	CreatedBy int    `json:"created_by,omitempty"`
+	CreatedAt string `json:"created_at"`
+}
+
+func NewPromotionResponse(promotion *entity.Promotion) *PromotionResponse {
+	return &PromotionResponse{
+		ID:        promotion.ID,
+		AdID:      promotion.AdID,
+		Type:      promotion.Type,
+		StartsAt:  promotion.StartsAt.Format(time.RFC3339),
+		EndsAt:    promotion.EndsAt.Format(time.RFC3339),
+		CreatedBy: promotion.CreatedBy,
+		CreatedAt: promotion.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+func NewPromotionsResponse(promotions []*entity.Promotion) []*PromotionResponse {
+	resp := make([]*PromotionResponse, len(promotions))
+	for i, p := range promotions {
+		resp[i] = NewPromotionResponse(p)
+	}
+	return resp
+}
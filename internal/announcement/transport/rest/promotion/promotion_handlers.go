@@ -0,0 +1,83 @@
+package promotion
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/1URose/marketplace/internal/announcement/transport/rest/promotion/dto"
+	"github.com/1URose/marketplace/internal/announcement/use_cases"
+	"github.com/1URose/marketplace/internal/common/transport/rest/bind"
+	"github.com/gin-gonic/gin"
+)
+
+type Handler struct {
+	service *use_cases.PromotionService
+}
+
+func NewHandler(service *use_cases.PromotionService) *Handler {
+	log.Println("[handler:promotion] NewHandler initialized")
+	return &Handler{service: service}
+}
+
+// CreatePromotion godoc
+// @Summary      Продвинуть объявление
+// @Description  Создаёт оплаченное продвижение объявления на период: top — закрепление в начале ленты, highlight — выделение в общей выдаче. Доступно только администраторам
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        Authorization header  string                       true  "JWT Access token"
+// @Param        promotion     body    dto.CreatePromotionRequest   true  "Параметры продвижения"
+// @Success      201           {object} dto.PromotionResponse       "Созданное продвижение"
+// @Failure      400           {object} dto.ErrorResponse           "Неверные данные запроса"
+// @Failure      401           {object} dto.ErrorResponse           "Неавторизован"
+// @Failure      403           {object} dto.ErrorResponse           "Недостаточно прав"
+// @Failure      404           {object} dto.ErrorResponse           "Объявление не найдено"
+// @Failure      500           {object} dto.ErrorResponse           "Внутренняя ошибка"
+// @Router       /admin/promotions [post]
+func (h *Handler) CreatePromotion(ctx *gin.Context) {
+	log.Println("[handler:promotion] CreatePromotion called")
+
+	userId := ctx.GetInt("userId")
+
+	var req dto.CreatePromotionRequest
+	if err := bind.JSON(ctx, &req); err != nil {
+		log.Println("[handler:promotion][ERROR] bind body:", err)
+		ctx.Error(err)
+		return
+	}
+
+	promotion, err := h.service.CreatePromotion(ctx, userId, &req)
+	if err != nil {
+		log.Println("[handler:promotion][ERROR] CreatePromotion:", err)
+		ctx.Error(err)
+		return
+	}
+
+	log.Printf("[handler:promotion] CreatePromotion succeeded: promotionID=%d", promotion.ID)
+	ctx.JSON(http.StatusCreated, dto.NewPromotionResponse(promotion))
+}
+
+// GetActivePromotions godoc
+// @Summary      Активные продвижения
+// @Description  Возвращает продвижения, действующие в текущий момент. Доступно только администраторам
+// @Tags         admin
+// @Produce      json
+// @Param        Authorization header string true "JWT Access token"
+// @Success      200           {array}  dto.PromotionResponse "Активные продвижения"
+// @Failure      401           {object} dto.ErrorResponse     "Неавторизован"
+// @Failure      403           {object} dto.ErrorResponse     "Недостаточно прав"
+// @Failure      500           {object} dto.ErrorResponse     "Внутренняя ошибка"
+// @Router       /admin/promotions [get]
+func (h *Handler) GetActivePromotions(ctx *gin.Context) {
+	log.Println("[handler:promotion] GetActivePromotions called")
+
+	promotions, err := h.service.GetActivePromotions(ctx)
+	if err != nil {
+		log.Println("[handler:promotion][ERROR] GetActivePromotions:", err)
+		ctx.Error(err)
+		return
+	}
+
+	log.Printf("[handler:promotion] GetActivePromotions succeeded: returned=%d", len(promotions))
+	ctx.JSON(http.StatusOK, dto.NewPromotionsResponse(promotions))
+}
@@ -0,0 +1,5 @@
+package dto
+
+type GetReportsRequest struct {
+	Page int `form:"page,default=1" binding:"min=1"`
+}
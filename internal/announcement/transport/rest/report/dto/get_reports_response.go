@@ -0,0 +1,49 @@
+package dto
+
+import (
+	"github.com/1URose/marketplace/internal/announcement/domain/report/entity"
+	"time"
+)
+
+type AdReportsResponse struct {
+	AdID              int               `json:"ad_id"`
+	AdTitle           string            `json:"ad_title"`
+	AuthorID          int               `json:"author_id"`
+	AuthorEmail       string            `json:"author_email"`
+	AdHidden          bool              `json:"ad_hidden"`
+	ReportsCount      int               `json:"reports_count"`
+	DistinctReporters int               `json:"distinct_reporters"`
+	ReasonCounts      map[string]int    `json:"reason_counts"`
+	FirstReportedAt   string            `json:"first_reported_at"`
+	LastReportedAt    string            `json:"last_reported_at"`
+	Reports           []*ReportResponse `json:"reports"`
+}
+
+type GetReportsResponse struct {
+	Ads        []*AdReportsResponse `json:"ads"`
+	CountPages int                  `json:"count_pages"`
+}
+
+func NewGetReportsResponse(groups []*entity.AdReports, countPages int) *GetReportsResponse {
+	resp := make([]*AdReportsResponse, len(groups))
+	for i, g := range groups {
+		reports := make([]*ReportResponse, len(g.Reports))
+		for j, r := range g.Reports {
+			reports[j] = NewReportResponse(r)
+		}
+		resp[i] = &AdReportsResponse{
+			AdID:              g.AdID,
+			AdTitle:           g.AdTitle,
+			AuthorID:          g.AuthorID,
+			AuthorEmail:       g.AuthorEmail,
+			AdHidden:          g.AdHidden,
+			ReportsCount:      g.ReportsCount,
+			DistinctReporters: g.DistinctReporters,
+			ReasonCounts:      g.ReasonCounts,
+			FirstReportedAt:   g.FirstReportedAt.Format(time.RFC3339),
+			LastReportedAt:    g.LastReportedAt.Format(time.RFC3339),
+			Reports:           reports,
+		}
+	}
+	return &GetReportsResponse{Ads: resp, CountPages: countPages}
+}
@@ -0,0 +1,6 @@
+package dto
+
+type ReportAdRequest struct {
+	Reason  string `json:"reason" binding:"required,oneof=scam spam prohibited offensive duplicate wrong_price other"`
+	Comment string `json:"comment" binding:"max=1000"`
+}
@@ -0,0 +1,28 @@
+package dto
+
+import (
+	"github.com/1URose/marketplace/internal/announcement/domain/report/entity"
+	"time"
+)
+
+type ReportResponse struct {
+	ID         int    `json:"id"`
+	AdID       int    `json:"ad_id"`
+	ReporterID int    `json:"reporter_id"`
+	Reason     string `json:"reason"`
+	Comment    string `json:"comment,omitempty"`
+	Status     string `json:"status"`
+	CreatedAt  string `json:"created_at"`
+}
+
+func NewReportResponse(report *entity.Report) *ReportResponse {
+	return &ReportResponse{
+		ID:         report.ID,
+		AdID:       report.AdID,
+		ReporterID: report.ReporterID,
+		Reason:     report.Reason,
+		Comment:    report.Comment,
+		Status:     report.Status,
+		CreatedAt:  report.CreatedAt.Format(time.RFC3339),
+	}
+}
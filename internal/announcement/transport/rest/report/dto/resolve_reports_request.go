@@ -0,0 +1,5 @@
+package dto
+
+type ResolveReportsRequest struct {
+	Action string `json:"action" binding:"required,oneof=dismiss hide_ad suspend_author"`
+}
@@ -0,0 +1,15 @@
+package dto
+
+type ResolveReportsResponse struct {
+	AdID     int    `json:"ad_id"`
+	Action   string `json:"action"`
+	Resolved int    `json:"resolved"`
+}
+
+func NewResolveReportsResponse(adID int, action string, resolved int) *ResolveReportsResponse {
+	return &ResolveReportsResponse{
+		AdID:     adID,
+		Action:   action,
+		Resolved: resolved,
+	}
+}
@@ -0,0 +1,150 @@
+package report
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/1URose/marketplace/internal/announcement/transport/rest/report/dto"
+	"github.com/1URose/marketplace/internal/announcement/use_cases"
+	"github.com/1URose/marketplace/internal/common/apperror"
+	"github.com/1URose/marketplace/internal/common/transport/rest/bind"
+	"github.com/gin-gonic/gin"
+)
+
+var errInvalidAdID = apperror.Validation("ad.invalid_id")
+
+type Handler struct {
+	service *use_cases.ReportService
+}
+
+func NewHandler(service *use_cases.ReportService) *Handler {
+	log.Println("[handler:report] NewHandler initialized")
+	return &Handler{service: service}
+}
+
+// ReportAd godoc
+// @Summary      Пожаловаться на объявление
+// @Description  Создаёт жалобу текущего пользователя на объявление. Одновременно может быть открыта только одна жалоба пользователя на объявление
+// @Tags         reports
+// @Accept       json
+// @Produce      json
+// @Param        Authorization header  string                true  "JWT Access token"
+// @Param        id            path    int                   true  "ID объявления"
+// @Param        report        body    dto.ReportAdRequest   true  "Причина жалобы"
+// @Success      201           {object} dto.ReportResponse   "Созданная жалоба"
+// @Failure      400           {object} dto.ErrorResponse    "Неверные данные запроса"
+// @Failure      401           {object} dto.ErrorResponse    "Неавторизован"
+// @Failure      404           {object} dto.ErrorResponse    "Объявление не найдено"
+// @Failure      409           {object} dto.ErrorResponse    "Открытая жалоба уже существует"
+// @Failure      500           {object} dto.ErrorResponse    "Внутренняя ошибка"
+// @Router       /ads/{id}/report [post]
+func (h *Handler) ReportAd(ctx *gin.Context) {
+	log.Println("[handler:report] ReportAd called")
+
+	userId := ctx.GetInt("userId")
+
+	adID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil || adID < 1 {
+		log.Printf("[handler:report][ERROR] invalid ad id %q", ctx.Param("id"))
+		ctx.Error(errInvalidAdID)
+		return
+	}
+
+	var req dto.ReportAdRequest
+	if err := bind.JSON(ctx, &req); err != nil {
+		log.Println("[handler:report][ERROR] bind body:", err)
+		ctx.Error(err)
+		return
+	}
+
+	report, err := h.service.ReportAd(ctx, userId, adID, &req)
+	if err != nil {
+		log.Println("[handler:report][ERROR] ReportAd:", err)
+		ctx.Error(err)
+		return
+	}
+
+	log.Printf("[handler:report] ReportAd succeeded: reportID=%d", report.ID)
+	ctx.JSON(http.StatusCreated, dto.NewReportResponse(report))
+}
+
+// GetReports godoc
+// @Summary      Очередь жалоб
+// @Description  Возвращает открытые жалобы, сгруппированные по объявлениям. Доступно только администраторам
+// @Tags         admin
+// @Produce      json
+// @Param        Authorization header string false "JWT Access token"
+// @Param        page          query  int    false "Номер страницы" default(1)
+// @Success      200           {object} dto.GetReportsResponse "Жалобы по объявлениям и количество страниц"
+// @Failure      400           {object} dto.ErrorResponse      "Неверные параметры запроса"
+// @Failure      401           {object} dto.ErrorResponse      "Неавторизован"
+// @Failure      403           {object} dto.ErrorResponse      "Недостаточно прав"
+// @Failure      500           {object} dto.ErrorResponse      "Внутренняя ошибка"
+// @Router       /admin/reports [get]
+func (h *Handler) GetReports(ctx *gin.Context) {
+	log.Println("[handler:report] GetReports called")
+
+	var req dto.GetReportsRequest
+	if err := bind.Query(ctx, &req); err != nil {
+		log.Println("[handler:report][ERROR] bind query:", err)
+		ctx.Error(err)
+		return
+	}
+
+	groups, countPages, err := h.service.GetOpenReports(ctx, req.Page)
+	if err != nil {
+		log.Println("[handler:report][ERROR] GetOpenReports:", err)
+		ctx.Error(err)
+		return
+	}
+
+	log.Printf("[handler:report] GetReports succeeded: groups=%d pages=%d", len(groups), countPages)
+	ctx.JSON(http.StatusOK, dto.NewGetReportsResponse(groups, countPages))
+}
+
+// ResolveReports godoc
+// @Summary      Разобрать жалобы на объявление
+// @Description  Закрывает все открытые жалобы на объявление: dismiss — отклонить (объявление, скрытое автоматически по порогу жалоб, возвращается в ленту), hide_ad — скрыть объявление, suspend_author — скрыть объявление, заблокировать автора и завершить все его сессии
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        Authorization header  string                      true  "JWT Access token"
+// @Param        id            path    int                         true  "ID объявления"
+// @Param        resolution    body    dto.ResolveReportsRequest   true  "Решение модератора"
+// @Success      200           {object} dto.ResolveReportsResponse "Результат разбора"
+// @Failure      400           {object} dto.ErrorResponse          "Неверные данные запроса"
+// @Failure      401           {object} dto.ErrorResponse          "Неавторизован"
+// @Failure      403           {object} dto.ErrorResponse          "Недостаточно прав"
+// @Failure      404           {object} dto.ErrorResponse          "Открытых жалоб нет"
+// @Failure      500           {object} dto.ErrorResponse          "Внутренняя ошибка"
+// @Router       /admin/reports/ads/{id}/resolve [post]
+func (h *Handler) ResolveReports(ctx *gin.Context) {
+	log.Println("[handler:report] ResolveReports called")
+
+	moderatorID := ctx.GetInt("userId")
+
+	adID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil || adID < 1 {
+		log.Printf("[handler:report][ERROR] invalid ad id %q", ctx.Param("id"))
+		ctx.Error(errInvalidAdID)
+		return
+	}
+
+	var req dto.ResolveReportsRequest
+	if err := bind.JSON(ctx, &req); err != nil {
+		log.Println("[handler:report][ERROR] bind body:", err)
+		ctx.Error(err)
+		return
+	}
+
+	resolved, err := h.service.Resolve(ctx, adID, moderatorID, req.Action, ctx.ClientIP(), ctx.Request.UserAgent())
+	if err != nil {
+		log.Println("[handler:report][ERROR] Resolve:", err)
+		ctx.Error(err)
+		return
+	}
+
+	log.Printf("[handler:report] ResolveReports succeeded: adID=%d resolved=%d", adID, resolved)
+	ctx.JSON(http.StatusOK, dto.NewResolveReportsResponse(adID, req.Action, resolved))
+}
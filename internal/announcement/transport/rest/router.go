@@ -13,5 +13,13 @@ func RegisterRoutes(deps *app.Deps) {
 
 	adRoute.RegisterRoutes()
 
+	reportRoute := routers.NewReportRoute(deps)
+
+	reportRoute.RegisterRoutes()
+
+	promotionRoute := routers.NewPromotionRoute(deps)
+
+	promotionRoute.RegisterRoutes()
+
 	log.Println("[rest:announcement] announcement routers registered successfully")
 }
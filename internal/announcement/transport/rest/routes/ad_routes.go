@@ -5,9 +5,12 @@ import (
 	"github.com/1URose/marketplace/internal/announcement/infrastructure/repository/postgresql"
 	"github.com/1URose/marketplace/internal/announcement/transport/rest/ad"
 	"github.com/1URose/marketplace/internal/announcement/use_cases"
+	apiKeyEntity "github.com/1URose/marketplace/internal/auth_signup/domain/api_key/entity"
 	"github.com/1URose/marketplace/internal/auth_signup/transport/rest/auth"
 	"github.com/1URose/marketplace/internal/common/app"
+	"github.com/1URose/marketplace/internal/common/clock"
 	"github.com/1URose/marketplace/internal/common/config"
+	"github.com/1URose/marketplace/internal/common/config/ad_limits"
 	"github.com/1URose/marketplace/internal/common/jwt"
 	"github.com/1URose/marketplace/internal/common/validator"
 
@@ -38,12 +41,19 @@ func NewAdRoute(deps *app.Deps) *AdRoute {
 	}
 }
 
-func initAdService(PGClient *pgConfig.Client, pageSize int) *use_cases.AdService {
+func initAdService(PGClient *pgConfig.Client, cfg *ad_limits.AdConfig, clk clock.Clock) *use_cases.AdService {
 	log.Println("[routers:ad] initializing AdService")
 
 	repo := postgresql.NewAdRepository(PGClient)
 
-	service := use_cases.NewAdService(repo, pageSize)
+	service := use_cases.NewAdService(
+		repo,
+		cfg.PageSize,
+		cfg.PromotedSlots,
+		cfg.Categories(),
+		cfg.PriceFacetEdges,
+		clk,
+	)
 
 	log.Println("[routers:ad] AdService initialized")
 
@@ -54,20 +64,47 @@ func initAdService(PGClient *pgConfig.Client, pageSize int) *use_cases.AdService
 func (ar *AdRoute) RegisterRoutes() {
 	log.Println("[routers:ad] registering /ad endpoints")
 
-	service := initAdService(ar.pgClient, ar.cfg.AdConfig.PageSize)
+	// сервис и проверка publish_at должны сверяться с одними часами
+	clk := clock.NewSystem()
+
+	service := initAdService(ar.pgClient, ar.cfg.AdConfig, clk)
 
 	v := validator.NewAllowedValues(ar.cfg.AdConfig)
 
-	handler := ad.NewHandler(service, v)
+	handler := ad.NewHandler(service, v, clk)
+
+	writeApiGroup := ar.engine.Group("/ad").Use(ar.authMiddleware.Require(apiKeyEntity.ScopeAdsWrite))
 
-	privateApiGroup := ar.engine.Group("/ad").Use(ar.authMiddleware.Require())
+	createAd := []gin.HandlerFunc{handler.CreateAd}
+	if ar.cfg.CommonConfig.EmailVerification.RequiredForAds {
+		createAd = append([]gin.HandlerFunc{ar.authMiddleware.RequireVerifiedEmail()}, createAd...)
+	}
 
 	{
-		privateApiGroup.POST("/", handler.CreateAd)
+		writeApiGroup.POST("/", createAd...)
 		log.Println("[routers:ad] registered POST /ad/")
+
+		writeApiGroup.POST("/:id/publish", handler.PublishAd)
+		log.Println("[routers:ad] registered POST /ad/:id/publish")
+
+		writeApiGroup.PATCH("/:id", handler.UpdateAd)
+		log.Println("[routers:ad] registered PATCH /ad/:id")
+	}
+
+	readApiGroup := ar.engine.Group("/ad").Use(ar.authMiddleware.Require(apiKeyEntity.ScopeAdsRead))
+
+	{
+		readApiGroup.GET("/mine", handler.GetMyAds)
+		log.Println("[routers:ad] registered GET /ad/mine")
+
+		readApiGroup.GET("/:id/revisions", handler.GetRevisions)
+		log.Println("[routers:ad] registered GET /ad/:id/revisions")
+
+		readApiGroup.GET("/:id/revisions/diff", handler.DiffRevisions)
+		log.Println("[routers:ad] registered GET /ad/:id/revisions/diff")
 	}
 
-	publicApiGroup := ar.engine.Group("/ads").Use(ar.authMiddleware.Optional())
+	publicApiGroup := ar.engine.Group("/ads").Use(ar.authMiddleware.Optional(apiKeyEntity.ScopeAdsRead))
 	{
 		publicApiGroup.GET("/", handler.GetAllAds)
 		log.Println("[routers:ad] registered GET /ad/")
@@ -0,0 +1,65 @@
+package routers
+
+import (
+	"context"
+	"github.com/1URose/marketplace/internal/announcement/infrastructure/repository/postgresql"
+	"github.com/1URose/marketplace/internal/announcement/transport/rest/promotion"
+	"github.com/1URose/marketplace/internal/announcement/use_cases"
+	"github.com/1URose/marketplace/internal/auth_signup/transport/rest/auth"
+	"github.com/1URose/marketplace/internal/common/app"
+	"github.com/1URose/marketplace/internal/common/clock"
+
+	pgConfig "github.com/1URose/marketplace/internal/common/db/postgresql"
+
+	"github.com/gin-gonic/gin"
+	"log"
+)
+
+type PromotionRoute struct {
+	ctx            context.Context
+	engine         *gin.Engine
+	pgClient       *pgConfig.Client
+	authMiddleware *auth.Middleware
+}
+
+func NewPromotionRoute(deps *app.Deps) *PromotionRoute {
+	log.Println("[routers:promotion] initializing PromotionRoute")
+	return &PromotionRoute{
+		ctx:            deps.Ctx,
+		engine:         deps.Engine,
+		pgClient:       deps.DB.PostgresConn,
+		authMiddleware: deps.AuthMiddleware,
+	}
+}
+
+func initPromotionService(PGClient *pgConfig.Client) *use_cases.PromotionService {
+	log.Println("[routers:promotion] initializing PromotionService")
+
+	promotionRepo := postgresql.NewPromotionRepository(PGClient)
+	adRepo := postgresql.NewAdRepository(PGClient)
+
+	service := use_cases.NewPromotionService(promotionRepo, adRepo, clock.NewSystem())
+
+	log.Println("[routers:promotion] PromotionService initialized")
+
+	return service
+}
+
+func (pr *PromotionRoute) RegisterRoutes() {
+	log.Println("[routers:promotion] registering /admin/promotions endpoints")
+
+	service := initPromotionService(pr.pgClient)
+
+	handler := promotion.NewHandler(service)
+
+	adminApiGroup := pr.engine.Group("/admin/promotions").Use(pr.authMiddleware.RequireAdmin())
+	{
+		adminApiGroup.POST("/", handler.CreatePromotion)
+		log.Println("[routers:promotion] registered POST /admin/promotions/")
+
+		adminApiGroup.GET("/", handler.GetActivePromotions)
+		log.Println("[routers:promotion] registered GET /admin/promotions/")
+	}
+
+	log.Println("[routers:promotion] /admin/promotions endpoints registered successfully")
+}
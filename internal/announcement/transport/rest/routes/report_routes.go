@@ -0,0 +1,77 @@
+package routers
+
+import (
+	"context"
+	"github.com/1URose/marketplace/internal/announcement/infrastructure/repository/postgresql"
+	"github.com/1URose/marketplace/internal/announcement/transport/rest/report"
+	"github.com/1URose/marketplace/internal/announcement/use_cases"
+	apiKeyEntity "github.com/1URose/marketplace/internal/auth_signup/domain/api_key/entity"
+	"github.com/1URose/marketplace/internal/auth_signup/transport/rest/auth"
+	"github.com/1URose/marketplace/internal/common/app"
+	"github.com/1URose/marketplace/internal/common/config"
+	userEntity "github.com/1URose/marketplace/internal/user_profile/domain/user/entity"
+
+	pgConfig "github.com/1URose/marketplace/internal/common/db/postgresql"
+
+	"github.com/gin-gonic/gin"
+	"log"
+)
+
+type ReportRoute struct {
+	ctx            context.Context
+	engine         *gin.Engine
+	pgClient       *pgConfig.Client
+	cfg            *config.GeneralConfig
+	authMiddleware *auth.Middleware
+	sessions       use_cases.SessionRevoker
+}
+
+func NewReportRoute(deps *app.Deps) *ReportRoute {
+	log.Println("[routers:report] initializing ReportRoute")
+	return &ReportRoute{
+		ctx:            deps.Ctx,
+		engine:         deps.Engine,
+		pgClient:       deps.DB.PostgresConn,
+		cfg:            deps.GeneralConfig,
+		authMiddleware: deps.AuthMiddleware,
+		sessions:       deps.SessionRevoker,
+	}
+}
+
+func initReportService(PGClient *pgConfig.Client, sessions use_cases.SessionRevoker, autoHideThreshold, pageSize int) *use_cases.ReportService {
+	log.Println("[routers:report] initializing ReportService")
+
+	reportRepo := postgresql.NewReportRepository(PGClient)
+	adRepo := postgresql.NewAdRepository(PGClient)
+
+	service := use_cases.NewReportService(reportRepo, adRepo, sessions, autoHideThreshold, pageSize)
+
+	log.Println("[routers:report] ReportService initialized")
+
+	return service
+}
+
+func (rr *ReportRoute) RegisterRoutes() {
+	log.Println("[routers:report] registering report endpoints")
+
+	service := initReportService(rr.pgClient, rr.sessions, rr.cfg.AdConfig.ReportAutoHideThreshold, rr.cfg.AdConfig.PageSize)
+
+	handler := report.NewHandler(service)
+
+	privateApiGroup := rr.engine.Group("/ads").Use(rr.authMiddleware.Require(apiKeyEntity.ScopeReportsWrite))
+	{
+		privateApiGroup.POST("/:id/report", handler.ReportAd)
+		log.Println("[routers:report] registered POST /ads/:id/report")
+	}
+
+	adminApiGroup := rr.engine.Group("/admin/reports").Use(rr.authMiddleware.RequireRole(userEntity.RoleModerator, userEntity.RoleAdmin))
+	{
+		adminApiGroup.GET("/", handler.GetReports)
+		log.Println("[routers:report] registered GET /admin/reports/")
+
+		adminApiGroup.POST("/ads/:id/resolve", handler.ResolveReports)
+		log.Println("[routers:report] registered POST /admin/reports/ads/:id/resolve")
+	}
+
+	log.Println("[routers:report] report endpoints registered successfully")
+}
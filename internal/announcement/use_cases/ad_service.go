@@ -7,40 +7,59 @@ import (
 	"github.com/1URose/marketplace/internal/announcement/domain/ad/repository"
 	entityAF "github.com/1URose/marketplace/internal/announcement/domain/ad_filter/entity"
 	"github.com/1URose/marketplace/internal/announcement/transport/rest/ad/dto"
+	"github.com/1URose/marketplace/internal/common/clock"
 	"log"
 	"math"
 )
 
 type AdService struct {
-	adRepo   repository.AdRepository
-	pageSize int
+	adRepo          repository.AdRepository
+	pageSize        int
+	promotedSlots   int
+	categories      []string
+	priceFacetEdges []int
+	clock           clock.Clock
 }
 
-func NewAdService(adRepo repository.AdRepository, pageSize int) *AdService {
-	log.Printf("[usecase:ad] NewAdService initialized: pageSize=%d", pageSize)
+func NewAdService(adRepo repository.AdRepository, pageSize, promotedSlots int, categories []string, priceFacetEdges []int, clk clock.Clock) *AdService {
+	log.Printf("[usecase:ad] NewAdService initialized: pageSize=%d promotedSlots=%d categories=%v priceFacetEdges=%v",
+		pageSize, promotedSlots, categories, priceFacetEdges,
+	)
 	return &AdService{
-		adRepo:   adRepo,
-		pageSize: pageSize,
+		adRepo:          adRepo,
+		pageSize:        pageSize,
+		promotedSlots:   promotedSlots,
+		categories:      categories,
+		priceFacetEdges: priceFacetEdges,
+		clock:           clk,
 	}
 }
 
 func (as *AdService) CreateAd(ctx context.Context, userId int, req *dto.CreateAdRequest) (*entity.Ad, error) {
 	log.Printf("[usecase:ad] CreateAd called: userId=%d title=%q price=%d", userId, req.Title, req.Price)
 
-	newAd := entity.NewAd(req.Title, req.Description, req.ImageURL, req.Price, userId)
+	newAd := entity.NewAd(req.Title, req.Description, req.ImageURL, req.Price, req.Category, userId)
+	switch {
+	case req.Draft:
+		newAd.Status = entity.StatusDraft
+	case req.PublishAt != nil:
+		newAd.Status = entity.StatusScheduled
+		newAd.PublishAt = req.PublishAt
+	}
+
 	createdAd, err := as.adRepo.CreateAd(ctx, newAd)
 	if err != nil {
 		log.Printf("[usecase:ad][ERROR] CreateAd failed: %v", err)
 		return nil, err
 	}
 
-	log.Printf("[usecase:ad] CreateAd succeeded: adID=%d createdAt=%s", createdAd.ID, createdAd.CreatedAt)
+	log.Printf("[usecase:ad] CreateAd succeeded: adID=%d status=%s createdAt=%s", createdAd.ID, createdAd.Status, createdAd.CreatedAt)
 	return createdAd, nil
 }
 
-func (as *AdService) GetAllAds(ctx context.Context, req *dto.GetAllAdsRequest) ([]*entity.Ad, int, error) {
-	log.Printf("[usecase:ad] GetAllAds called: page=%d sortBy=%s sortOrder=%s minPrice=%v maxPrice=%v",
-		req.Page, req.SortBy, req.SortOrder, req.MinPrice, req.MaxPrice,
+func (as *AdService) GetAllAds(ctx context.Context, req *dto.GetAllAdsRequest) ([]*entity.Ad, int, *entityAF.Facets, error) {
+	log.Printf("[usecase:ad] GetAllAds called: page=%d sortBy=%s sortOrder=%s minPrice=%v maxPrice=%v category=%v createdWithin=%q facets=%q",
+		req.Page, req.SortBy, req.SortOrder, req.MinPrice, req.MaxPrice, req.Category, req.CreatedWithin, req.Facets,
 	)
 
 	filter := entityAF.NewAdFilter(
@@ -50,34 +69,219 @@ func (as *AdService) GetAllAds(ctx context.Context, req *dto.GetAllAdsRequest) (
 		req.SortOrder,
 		req.MinPrice,
 		req.MaxPrice,
+		req.Category,
+		req.CreatedWithin,
+		as.clock.Now(),
+		as.promotedSlots,
 	)
 
-	total, err := as.adRepo.CountAds(ctx)
+	total, err := as.adRepo.CountAds(ctx, filter)
 	if err != nil {
 		log.Printf("[usecase:ad][ERROR] CountAds failed: %v", err)
-		return nil, 0, fmt.Errorf("count ads: %w", err)
-	}
-
-	if total == 0 {
-		log.Printf("[usecase:ad] GetAllAds: no ads found")
-		return nil, 0, nil
+		return nil, 0, nil, fmt.Errorf("count ads: %w", err)
 	}
 
 	log.Printf("[usecase:ad] CountAds succeeded: total=%d", total)
 
+	// первая страница существует всегда: даже без обычных объявлений на ней могут быть продвигаемые
 	countPages := int(math.Ceil(float64(total) / float64(as.pageSize)))
+	if countPages == 0 {
+		countPages = 1
+	}
 
 	if req.Page > countPages {
+		err := entity.ErrPageOutOfRange.WithDetail(fmt.Sprintf("%d > %d", req.Page, countPages))
 		log.Printf("[usecase:ad][ERROR] %v", err)
-		return nil, 0, fmt.Errorf("invalid page number: %d > %d", req.Page, countPages)
+		return nil, 0, nil, err
 	}
 
 	ads, err := as.adRepo.GetAllAds(ctx, filter)
 	if err != nil {
 		log.Printf("[usecase:ad][ERROR] GetAllAds failed: %v", err)
-		return nil, 0, fmt.Errorf("query ads: %w", err)
+		return nil, 0, nil, fmt.Errorf("query ads: %w", err)
+	}
+
+	// фасеты нужны и при пустой выдаче: они подсказывают, как ослабить фильтры
+	var facets *entityAF.Facets
+	if requested := req.FacetList(); len(requested) > 0 {
+		facets, err = as.adRepo.CountFacets(ctx, filter, requested, as.priceFacetEdges)
+		if err != nil {
+			log.Printf("[usecase:ad][ERROR] CountFacets failed: %v", err)
+			return nil, 0, nil, fmt.Errorf("count facets: %w", err)
+		}
+		if facets.Category != nil {
+			facets.Category = as.orderCategories(facets.Category)
+		}
+	}
+
+	if len(ads) == 0 {
+		log.Printf("[usecase:ad] GetAllAds: no ads found")
+		return nil, 0, facets, nil
 	}
 
 	log.Printf("[usecase:ad] GetAllAds succeeded: returned=%d countPages=%d", len(ads), countPages)
-	return ads, countPages, nil
+	return ads, countPages, facets, nil
+}
+
+// orderCategories дополняет фасет категорий нулевыми счётчиками и упорядочивает его как в конфигурации.
+// Категории, которых уже нет в конфигурации, но которые остались у старых объявлений, идут в конце.
+func (as *AdService) orderCategories(counts []entityAF.ValueCount) []entityAF.ValueCount {
+	byValue := make(map[string]int, len(counts))
+	for _, c := range counts {
+		byValue[c.Value] = c.Count
+	}
+
+	ordered := make([]entityAF.ValueCount, 0, len(as.categories))
+	for _, category := range as.categories {
+		ordered = append(ordered, entityAF.ValueCount{Value: category, Count: byValue[category]})
+		delete(byValue, category)
+	}
+	for _, c := range counts {
+		if _, ok := byValue[c.Value]; ok {
+			ordered = append(ordered, c)
+		}
+	}
+	return ordered
+}
+
+func (as *AdService) GetMyAds(ctx context.Context, userId int) ([]*entity.Ad, error) {
+	log.Printf("[usecase:ad] GetMyAds called: userId=%d", userId)
+
+	ads, err := as.adRepo.GetAdsByAuthor(ctx, userId)
+	if err != nil {
+		log.Printf("[usecase:ad][ERROR] GetAdsByAuthor failed: %v", err)
+		return nil, fmt.Errorf("query own ads: %w", err)
+	}
+
+	log.Printf("[usecase:ad] GetMyAds succeeded: returned=%d", len(ads))
+	return ads, nil
+}
+
+func (as *AdService) PublishAd(ctx context.Context, userId, adID int) (*entity.Ad, error) {
+	log.Printf("[usecase:ad] PublishAd called: userId=%d adID=%d", userId, adID)
+
+	ad, err := as.adRepo.GetAdByID(ctx, adID)
+	if err != nil {
+		log.Printf("[usecase:ad][ERROR] GetAdByID failed: %v", err)
+		return nil, fmt.Errorf("get ad: %w", err)
+	}
+	if ad == nil {
+		return nil, entity.ErrAdNotFound
+	}
+	if ad.AuthorID != userId {
+		return nil, entity.ErrNotAdOwner
+	}
+	if ad.IsPublished() {
+		return nil, entity.ErrAlreadyPublished
+	}
+
+	now := as.clock.Now()
+	ok, err := as.adRepo.PublishAd(ctx, adID, userId, now)
+	if err != nil {
+		log.Printf("[usecase:ad][ERROR] PublishAd failed: %v", err)
+		return nil, fmt.Errorf("publish ad: %w", err)
+	}
+	if !ok {
+		// планировщик успел опубликовать объявление между чтением и обновлением
+		return nil, entity.ErrAlreadyPublished
+	}
+
+	ad.Status = entity.StatusPublished
+	ad.PublishedAt = &now
+
+	log.Printf("[usecase:ad] PublishAd succeeded: adID=%d", adID)
+	return ad, nil
+}
+
+func (as *AdService) UpdateAd(ctx context.Context, userId, adID int, req *dto.UpdateAdRequest) (*entity.Ad, error) {
+	log.Printf("[usecase:ad] UpdateAd called: userId=%d adID=%d", userId, adID)
+
+	ad, err := as.adRepo.GetAdByID(ctx, adID)
+	if err != nil {
+		log.Printf("[usecase:ad][ERROR] GetAdByID failed: %v", err)
+		return nil, fmt.Errorf("get ad: %w", err)
+	}
+	if ad == nil {
+		return nil, entity.ErrAdNotFound
+	}
+	if ad.AuthorID != userId {
+		return nil, entity.ErrNotAdOwner
+	}
+
+	rev, err := as.adRepo.UpdateAd(ctx, adID, userId, req.ToChanges())
+	if err != nil {
+		log.Printf("[usecase:ad][ERROR] UpdateAd failed: %v", err)
+		return nil, fmt.Errorf("update ad: %w", err)
+	}
+	if rev == nil {
+		return nil, entity.ErrNoChanges
+	}
+
+	ad.Title = rev.Title
+	ad.Description = rev.Description
+	ad.ImageURL = rev.ImageURL
+	ad.Price = rev.Price
+
+	log.Printf("[usecase:ad] UpdateAd succeeded: adID=%d revision=%d", adID, rev.Number)
+	return ad, nil
+}
+
+// authorizeHistory пропускает к истории правок только автора объявления и модераторов.
+func (as *AdService) authorizeHistory(ctx context.Context, userId int, isModerator bool, adID int) error {
+	ad, err := as.adRepo.GetAdByID(ctx, adID)
+	if err != nil {
+		log.Printf("[usecase:ad][ERROR] GetAdByID failed: %v", err)
+		return fmt.Errorf("get ad: %w", err)
+	}
+	if ad == nil {
+		return entity.ErrAdNotFound
+	}
+	if ad.AuthorID != userId && !isModerator {
+		return entity.ErrNotAdOwner
+	}
+	return nil
+}
+
+func (as *AdService) GetRevisions(ctx context.Context, userId int, isModerator bool, adID int) ([]*entity.Revision, error) {
+	log.Printf("[usecase:ad] GetRevisions called: userId=%d moderator=%t adID=%d", userId, isModerator, adID)
+
+	if err := as.authorizeHistory(ctx, userId, isModerator, adID); err != nil {
+		return nil, err
+	}
+
+	revisions, err := as.adRepo.GetRevisions(ctx, adID)
+	if err != nil {
+		log.Printf("[usecase:ad][ERROR] GetRevisions failed: %v", err)
+		return nil, fmt.Errorf("query revisions: %w", err)
+	}
+
+	log.Printf("[usecase:ad] GetRevisions succeeded: returned=%d", len(revisions))
+	return revisions, nil
+}
+
+func (as *AdService) DiffRevisions(ctx context.Context, userId int, isModerator bool, adID, from, to int) (*entity.Revision, *entity.Revision, []entity.FieldChange, error) {
+	log.Printf("[usecase:ad] DiffRevisions called: userId=%d moderator=%t adID=%d from=%d to=%d", userId, isModerator, adID, from, to)
+
+	if err := as.authorizeHistory(ctx, userId, isModerator, adID); err != nil {
+		return nil, nil, nil, err
+	}
+
+	fromRev, err := as.adRepo.GetRevision(ctx, adID, from)
+	if err != nil {
+		log.Printf("[usecase:ad][ERROR] GetRevision failed: %v", err)
+		return nil, nil, nil, fmt.Errorf("get revision: %w", err)
+	}
+	toRev, err := as.adRepo.GetRevision(ctx, adID, to)
+	if err != nil {
+		log.Printf("[usecase:ad][ERROR] GetRevision failed: %v", err)
+		return nil, nil, nil, fmt.Errorf("get revision: %w", err)
+	}
+	if fromRev == nil || toRev == nil {
+		return nil, nil, nil, entity.ErrRevisionNotFound
+	}
+
+	changes := entity.Diff(fromRev, toRev)
+
+	log.Printf("[usecase:ad] DiffRevisions succeeded: changes=%d", len(changes))
+	return fromRev, toRev, changes, nil
 }
@@ -0,0 +1,71 @@
+package use_cases
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/1URose/marketplace/internal/announcement/domain/ad/entity"
+	"github.com/1URose/marketplace/internal/announcement/domain/ad/repository"
+	entityAF "github.com/1URose/marketplace/internal/announcement/domain/ad_filter/entity"
+	"github.com/1URose/marketplace/internal/announcement/transport/rest/ad/dto"
+	"github.com/1URose/marketplace/internal/common/clock"
+)
+
+// feedRepo отдаёт заданную обычную выдачу и запоминает фильтры, с которыми его вызвали.
+// Остальные методы AdRepository в этих тестах не нужны.
+type feedRepo struct {
+	repository.AdRepository
+
+	organic int
+	filters []*entityAF.AdFilter
+}
+
+func (r *feedRepo) CountAds(_ context.Context, filter *entityAF.AdFilter) (int, error) {
+	r.filters = append(r.filters, filter)
+	return r.organic, nil
+}
+
+func (r *feedRepo) GetAllAds(_ context.Context, filter *entityAF.AdFilter) ([]*entity.Ad, error) {
+	r.filters = append(r.filters, filter)
+	return []*entity.Ad{{ID: 1, IsPromoted: true}, {ID: 2}}, nil
+}
+
+func TestGetAllAdsUsesClockForPromotions(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	repo := &feedRepo{organic: 4}
+	service := NewAdService(repo, 3, 2, nil, nil, clock.Func(func() time.Time { return now }))
+
+	_, pages, _, err := service.GetAllAds(context.Background(), &dto.GetAllAdsRequest{Page: 2, SortBy: "created_at", SortOrder: "desc"})
+	if err != nil {
+		t.Fatalf("GetAllAds: %v", err)
+	}
+	// страницы считаются только по обычной выдаче: закреплённые места есть на каждой странице
+	if pages != 2 {
+		t.Fatalf("countPages=%d, want 2", pages)
+	}
+	for _, filter := range repo.filters {
+		if !filter.Now.Equal(now) || filter.PromotedSlots != 2 {
+			t.Fatalf("filter now=%s slots=%d, want now=%s slots=2", filter.Now, filter.PromotedSlots, now)
+		}
+	}
+
+	_, _, _, err = service.GetAllAds(context.Background(), &dto.GetAllAdsRequest{Page: 3, SortBy: "created_at", SortOrder: "desc"})
+	if !errors.Is(err, entity.ErrPageOutOfRange) {
+		t.Fatalf("page 3: got %v, want %v", err, entity.ErrPageOutOfRange)
+	}
+}
+
+func TestGetAllAdsKeepsFirstPageWithoutOrganicAds(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	service := NewAdService(&feedRepo{}, 3, 2, nil, nil, clock.Func(func() time.Time { return now }))
+
+	ads, pages, _, err := service.GetAllAds(context.Background(), &dto.GetAllAdsRequest{Page: 1, SortBy: "created_at", SortOrder: "desc"})
+	if err != nil {
+		t.Fatalf("GetAllAds: %v", err)
+	}
+	if pages != 1 || len(ads) != 2 {
+		t.Fatalf("got %d ads on %d pages, want 2 ads on 1 page", len(ads), pages)
+	}
+}
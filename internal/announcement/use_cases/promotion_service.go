@@ -0,0 +1,71 @@
+package use_cases
+
+import (
+	"context"
+	"fmt"
+	adRepository "github.com/1URose/marketplace/internal/announcement/domain/ad/repository"
+	"github.com/1URose/marketplace/internal/announcement/domain/promotion/entity"
+	"github.com/1URose/marketplace/internal/announcement/domain/promotion/repository"
+	"github.com/1URose/marketplace/internal/announcement/transport/rest/promotion/dto"
+	"github.com/1URose/marketplace/internal/common/clock"
+	"log"
+)
+
+type PromotionService struct {
+	promotionRepo repository.PromotionRepository
+	adRepo        adRepository.AdRepository
+	clock         clock.Clock
+}
+
+func NewPromotionService(
+	promotionRepo repository.PromotionRepository,
+	adRepo adRepository.AdRepository,
+	clk clock.Clock,
+) *PromotionService {
+	log.Println("[usecase:promotion] NewPromotionService initialized")
+	return &PromotionService{
+		promotionRepo: promotionRepo,
+		adRepo:        adRepo,
+		clock:         clk,
+	}
+}
+
+func (ps *PromotionService) CreatePromotion(ctx context.Context, createdBy int, req *dto.CreatePromotionRequest) (*entity.Promotion, error) {
+	log.Printf("[usecase:promotion] CreatePromotion called: adID=%d type=%q createdBy=%d", req.AdID, req.Type, createdBy)
+
+	if !req.EndsAt.After(req.StartsAt) || !req.EndsAt.After(ps.clock.Now()) {
+		return nil, entity.ErrInvalidPeriod
+	}
+
+	ad, err := ps.adRepo.GetAdByID(ctx, req.AdID)
+	if err != nil {
+		log.Printf("[usecase:promotion][ERROR] GetAdByID failed: %v", err)
+		return nil, fmt.Errorf("get ad: %w", err)
+	}
+	if ad == nil {
+		return nil, entity.ErrAdNotFound
+	}
+
+	promotion := entity.NewPromotion(req.AdID, req.Type, req.StartsAt, req.EndsAt, createdBy)
+	created, err := ps.promotionRepo.CreatePromotion(ctx, promotion)
+	if err != nil {
+		log.Printf("[usecase:promotion][ERROR] CreatePromotion failed: %v", err)
+		return nil, err
+	}
+
+	log.Printf("[usecase:promotion] CreatePromotion succeeded: promotionID=%d", created.ID)
+	return created, nil
+}
+
+func (ps *PromotionService) GetActivePromotions(ctx context.Context) ([]*entity.Promotion, error) {
+	log.Println("[usecase:promotion] GetActivePromotions called")
+
+	promotions, err := ps.promotionRepo.GetActivePromotions(ctx, ps.clock.Now())
+	if err != nil {
+		log.Printf("[usecase:promotion][ERROR] GetActivePromotions failed: %v", err)
+		return nil, fmt.Errorf("query promotions: %w", err)
+	}
+
+	log.Printf("[usecase:promotion] GetActivePromotions succeeded: returned=%d", len(promotions))
+	return promotions, nil
+}
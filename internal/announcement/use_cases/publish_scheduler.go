@@ -0,0 +1,56 @@
+package use_cases
+
+import (
+	"context"
+	"github.com/1URose/marketplace/internal/announcement/domain/ad/repository"
+	"github.com/1URose/marketplace/internal/common/clock"
+	"log"
+	"time"
+)
+
+// PublishScheduler периодически публикует отложенные объявления, время которых наступило.
+// Состояние хранится только в БД, поэтому после перезапуска пропущенные публикации
+// выполняются при первом же проходе.
+type PublishScheduler struct {
+	adRepo   repository.AdRepository
+	interval time.Duration
+	clock    clock.Clock
+}
+
+func NewPublishScheduler(adRepo repository.AdRepository, interval time.Duration, clk clock.Clock) *PublishScheduler {
+	log.Printf("[usecase:publish_scheduler] NewPublishScheduler initialized: interval=%s", interval)
+	return &PublishScheduler{
+		adRepo:   adRepo,
+		interval: interval,
+		clock:    clk,
+	}
+}
+
+func (ps *PublishScheduler) Run(ctx context.Context) {
+	log.Println("[usecase:publish_scheduler] started")
+
+	ticker := time.NewTicker(ps.interval)
+	defer ticker.Stop()
+
+	for {
+		ps.PublishDue(ctx)
+
+		select {
+		case <-ctx.Done():
+			log.Println("[usecase:publish_scheduler] stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (ps *PublishScheduler) PublishDue(ctx context.Context) {
+	ids, err := ps.adRepo.PublishDue(ctx, ps.clock.Now())
+	if err != nil {
+		log.Printf("[usecase:publish_scheduler][ERROR] PublishDue failed: %v", err)
+		return
+	}
+	if len(ids) > 0 {
+		log.Printf("[usecase:publish_scheduler] published scheduled ads: ids=%v", ids)
+	}
+}
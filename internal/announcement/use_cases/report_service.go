@@ -0,0 +1,160 @@
+package use_cases
+
+import (
+	"context"
+	"fmt"
+	adRepository "github.com/1URose/marketplace/internal/announcement/domain/ad/repository"
+	"github.com/1URose/marketplace/internal/announcement/domain/report/entity"
+	"github.com/1URose/marketplace/internal/announcement/domain/report/repository"
+	"github.com/1URose/marketplace/internal/announcement/transport/rest/report/dto"
+	"log"
+	"math"
+)
+
+// SessionRevoker закрывает все сессии пользователя и отзывает его токены; реализуется сервисом аутентификации.
+type SessionRevoker interface {
+	LogoutAll(ctx context.Context, userID int, ip, userAgent string) error
+}
+
+type ReportService struct {
+	reportRepo        repository.ReportRepository
+	adRepo            adRepository.AdRepository
+	sessions          SessionRevoker
+	autoHideThreshold int
+	pageSize          int
+}
+
+func NewReportService(
+	reportRepo repository.ReportRepository,
+	adRepo adRepository.AdRepository,
+	sessions SessionRevoker,
+	autoHideThreshold, pageSize int,
+) *ReportService {
+	log.Printf("[usecase:report] NewReportService initialized: autoHideThreshold=%d pageSize=%d", autoHideThreshold, pageSize)
+	return &ReportService{
+		reportRepo:        reportRepo,
+		adRepo:            adRepo,
+		sessions:          sessions,
+		autoHideThreshold: autoHideThreshold,
+		pageSize:          pageSize,
+	}
+}
+
+func (rs *ReportService) ReportAd(ctx context.Context, reporterID, adID int, req *dto.ReportAdRequest) (*entity.Report, error) {
+	log.Printf("[usecase:report] ReportAd called: reporterID=%d adID=%d reason=%q", reporterID, adID, req.Reason)
+
+	ad, err := rs.adRepo.GetAdByID(ctx, adID)
+	if err != nil {
+		log.Printf("[usecase:report][ERROR] GetAdByID failed: %v", err)
+		return nil, fmt.Errorf("get ad: %w", err)
+	}
+	if ad == nil || !ad.IsPublished() {
+		return nil, entity.ErrAdNotFound
+	}
+	if ad.AuthorID == reporterID {
+		return nil, entity.ErrOwnAd
+	}
+
+	report, err := rs.reportRepo.CreateReport(ctx, entity.NewReport(adID, reporterID, req.Reason, req.Comment))
+	if err != nil {
+		log.Printf("[usecase:report][ERROR] CreateReport failed: %v", err)
+		return nil, err
+	}
+
+	if rs.autoHideThreshold <= 0 || ad.IsHidden() {
+		return report, nil
+	}
+
+	reporters, err := rs.reportRepo.CountDistinctOpenReporters(ctx, adID)
+	if err != nil {
+		log.Printf("[usecase:report][ERROR] CountDistinctOpenReporters failed: %v", err)
+		return nil, fmt.Errorf("count reporters: %w", err)
+	}
+
+	if reporters >= rs.autoHideThreshold {
+		log.Printf("[usecase:report] ReportAd: adID=%d reached %d distinct reports, hiding", adID, reporters)
+		if err := rs.reportRepo.HideAd(ctx, adID); err != nil {
+			log.Printf("[usecase:report][ERROR] HideAd failed: %v", err)
+			return nil, fmt.Errorf("hide ad: %w", err)
+		}
+	}
+
+	log.Printf("[usecase:report] ReportAd succeeded: reportID=%d", report.ID)
+	return report, nil
+}
+
+func (rs *ReportService) GetOpenReports(ctx context.Context, page int) ([]*entity.AdReports, int, error) {
+	log.Printf("[usecase:report] GetOpenReports called: page=%d", page)
+
+	total, err := rs.reportRepo.CountAdsWithOpenReports(ctx)
+	if err != nil {
+		log.Printf("[usecase:report][ERROR] CountAdsWithOpenReports failed: %v", err)
+		return nil, 0, fmt.Errorf("count reported ads: %w", err)
+	}
+	if total == 0 {
+		log.Printf("[usecase:report] GetOpenReports: no open reports")
+		return nil, 0, nil
+	}
+
+	countPages := int(math.Ceil(float64(total) / float64(rs.pageSize)))
+	if page > countPages {
+		return nil, 0, entity.ErrPageOutOfRange.WithDetail(fmt.Sprintf("%d > %d", page, countPages))
+	}
+
+	groups, err := rs.reportRepo.GetOpenReportsGroupedByAd(ctx, (page-1)*rs.pageSize, rs.pageSize)
+	if err != nil {
+		log.Printf("[usecase:report][ERROR] GetOpenReportsGroupedByAd failed: %v", err)
+		return nil, 0, fmt.Errorf("query reports: %w", err)
+	}
+
+	log.Printf("[usecase:report] GetOpenReports succeeded: groups=%d countPages=%d", len(groups), countPages)
+	return groups, countPages, nil
+}
+
+// Resolve применяет решение модератора. При блокировке автора все его сессии закрываются, а токены отзываются:
+// иначе он продолжал бы публиковать объявления до истечения access-токена. Сессии закрываются до фиксации
+// решения: если отзыв не удался, жалобы остаются открытыми и модератор может повторить запрос.
+// ip и userAgent — модератора.
+func (rs *ReportService) Resolve(ctx context.Context, adID, moderatorID int, action, ip, userAgent string) (int, error) {
+	log.Printf("[usecase:report] Resolve called: adID=%d moderatorID=%d action=%q", adID, moderatorID, action)
+
+	switch action {
+	case entity.ActionDismiss, entity.ActionHideAd, entity.ActionSuspendAuthor:
+	default:
+		return 0, entity.ErrUnsupportedAction
+	}
+
+	var authorID int
+	if action == entity.ActionSuspendAuthor {
+		ad, err := rs.adRepo.GetAdByID(ctx, adID)
+		if err != nil {
+			log.Printf("[usecase:report][ERROR] GetAdByID failed: %v", err)
+			return 0, fmt.Errorf("get ad: %w", err)
+		}
+		if ad == nil {
+			return 0, entity.ErrAdNotFound
+		}
+		authorID = ad.AuthorID
+	}
+
+	var revokeSessions func(ctx context.Context) error
+	if action == entity.ActionSuspendAuthor {
+		revokeSessions = func(ctx context.Context) error {
+			if err := rs.sessions.LogoutAll(ctx, authorID, ip, userAgent); err != nil {
+				log.Printf("[usecase:report][ERROR] revoke sessions of authorID=%d: %v", authorID, err)
+				return fmt.Errorf("revoke author sessions: %w", err)
+			}
+			log.Printf("[usecase:report] Resolve: sessions of authorID=%d revoked", authorID)
+			return nil
+		}
+	}
+
+	resolved, err := rs.reportRepo.Resolve(ctx, adID, moderatorID, action, revokeSessions)
+	if err != nil {
+		log.Printf("[usecase:report][ERROR] Resolve failed: %v", err)
+		return 0, err
+	}
+
+	log.Printf("[usecase:report] Resolve succeeded: adID=%d resolved=%d", adID, resolved)
+	return resolved, nil
+}
@@ -0,0 +1,70 @@
+package app
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/1URose/marketplace/internal/common/app"
+	"github.com/1URose/marketplace/internal/common/config/password_hashing"
+	pgConfig "github.com/1URose/marketplace/internal/common/config/postgresql"
+	"github.com/1URose/marketplace/internal/common/db/postgresql"
+	pgRepo "github.com/1URose/marketplace/internal/user_profile/infrastructure/repository/postgresql"
+	"github.com/1URose/marketplace/internal/user_profile/use_cases"
+)
+
+// CreateAdmin — команда `marketplace create-admin -email <email>`: назначает существующему пользователю
+// роль администратора или создаёт нового администратора. Пароль нового пользователя читается из первой
+// строки stdin, чтобы не оставлять его в истории shell и списке процессов.
+func CreateAdmin(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("create-admin", flag.ContinueOnError)
+	email := fs.String("email", "", "email администратора")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	addr := strings.ToLower(strings.TrimSpace(*email))
+	if addr == "" {
+		return errors.New("-email is required")
+	}
+
+	if err := app.ConfigurePasswordHashing(password_hashing.LoadPasswordHashingConfigFromEnv()); err != nil {
+		return fmt.Errorf("configure password hashing: %w", err)
+	}
+
+	client, err := postgresql.NewClient(pgConfig.LoadPGConfigFromEnv())
+	if err != nil {
+		return fmt.Errorf("connect to postgres: %w", err)
+	}
+	defer client.Close()
+
+	service := use_cases.NewUserService(pgRepo.NewUserRepository(client))
+
+	user, created, err := service.EnsureAdmin(ctx, addr, readPasswordFromStdin)
+	if err != nil {
+		return err
+	}
+
+	if created {
+		log.Printf("[cmd:create-admin] created admin id=%d email=%s", user.ID, user.Email)
+	} else {
+		log.Printf("[cmd:create-admin] user id=%d email=%s is now admin", user.ID, user.Email)
+	}
+	return nil
+}
+
+func readPasswordFromStdin() (string, error) {
+	fmt.Fprintln(os.Stderr, "User not found, enter password for the new admin:")
+
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
@@ -21,6 +21,7 @@ import (
 	"github.com/1URose/marketplace/internal/common/app"
 	"github.com/1URose/marketplace/internal/common/config"
 	"github.com/1URose/marketplace/internal/common/db"
+	"github.com/1URose/marketplace/internal/common/transport/rest/middleware"
 	userApp "github.com/1URose/marketplace/internal/user_profile/app"
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
@@ -51,7 +52,11 @@ func Run(ctx context.Context) error {
 
 	log.Println("Database connections established")
 
-	deps := app.NewDeps(ctx, engine, connections, generalConfig)
+	deps, err := app.NewDeps(ctx, engine, connections, generalConfig)
+	if err != nil {
+		log.Printf("failed to initialize dependencies: %v", err)
+		return err
+	}
 
 	userApp.Run(deps)
 	authApp.Run(deps)
@@ -97,15 +102,16 @@ func loggingMiddleware(c *gin.Context) {
 	method := c.Request.Method
 	path := c.Request.URL.Path
 	ip := c.ClientIP()
+	requestID := middleware.GetRequestID(c)
 
 	if len(c.Errors) > 0 {
 
-		log.Printf("ERROR: status=%d method=%s path=%s ip=%s latency=%v errors=%s",
-			status, method, path, ip, latency, c.Errors.String())
+		log.Printf("ERROR: status=%d method=%s path=%s ip=%s request_id=%s latency=%v errors=%s",
+			status, method, path, ip, requestID, latency, c.Errors.String())
 	} else {
 
-		log.Printf("INFO: status=%d method=%s path=%s ip=%s latency=%v",
-			status, method, path, ip, latency)
+		log.Printf("INFO: status=%d method=%s path=%s ip=%s request_id=%s latency=%v",
+			status, method, path, ip, requestID, latency)
 	}
 }
 
@@ -121,7 +127,9 @@ func init() {
 func initializeGin() *gin.Engine {
 	engine := gin.New()
 	engine.Use(gin.Recovery())
+	engine.Use(middleware.RequestID())
 	engine.Use(loggingMiddleware)
+	engine.Use(middleware.ErrorHandler())
 
 	engine.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{"*"},
@@ -0,0 +1,152 @@
+package entity
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// KeyPrefix отличает API-ключ от JWT в заголовке Authorization.
+const KeyPrefix = "mk_"
+
+const (
+	// prefixBytes — открытая часть ключа: по ней ключ ищется в базе и её видит владелец в списке ключей.
+	prefixBytes = 6
+	secretBytes = 32
+)
+
+// Scope — право API-ключа на группу маршрутов.
+const (
+	ScopeAdsRead      = "ads:read"
+	ScopeAdsWrite     = "ads:write"
+	ScopeReportsWrite = "reports:write"
+)
+
+var knownScopes = map[string]struct{}{
+	ScopeAdsRead:      {},
+	ScopeAdsWrite:     {},
+	ScopeReportsWrite: {},
+}
+
+// APIKey — ключ машинного клиента, выпущенный пользователем. Сам ключ не хранится, только SHA-256 от него:
+// в отличие от пароля он случайный и длинный, поэтому медленный хеш не нужен.
+type APIKey struct {
+	ID         int
+	UserID     int
+	Name       string
+	Prefix     string
+	KeyHash    string
+	Scopes     []string
+	ExpiresAt  *time.Time
+	LastUsedAt *time.Time
+	CreatedAt  time.Time
+}
+
+// NewAPIKey выпускает ключ и возвращает его вместе с открытым значением, которое показывается владельцу один раз.
+func NewAPIKey(userID int, name string, scopes []string, expiresAt *time.Time) (*APIKey, string, error) {
+	normalized, err := normalizeScopes(scopes)
+	if err != nil {
+		return nil, "", err
+	}
+
+	prefix, err := randomHex(prefixBytes)
+	if err != nil {
+		return nil, "", err
+	}
+	secret, err := randomHex(secretBytes)
+	if err != nil {
+		return nil, "", err
+	}
+
+	prefix = KeyPrefix + prefix
+	raw := prefix + "_" + secret
+
+	return &APIKey{
+		UserID:    userID,
+		Name:      name,
+		Prefix:    prefix,
+		KeyHash:   HashKey(raw),
+		Scopes:    normalized,
+		ExpiresAt: expiresAt,
+	}, raw, nil
+}
+
+// IsAPIKey сообщает, похоже ли значение на API-ключ, а не на JWT.
+func IsAPIKey(raw string) bool {
+	return strings.HasPrefix(raw, KeyPrefix)
+}
+
+// ParsePrefix выделяет открытую часть ключа вида mk_<prefix>_<secret>.
+func ParsePrefix(raw string) (string, bool) {
+	if !IsAPIKey(raw) {
+		return "", false
+	}
+	prefix, secret, ok := strings.Cut(strings.TrimPrefix(raw, KeyPrefix), "_")
+	if !ok || len(prefix) != prefixBytes*2 || len(secret) != secretBytes*2 {
+		return "", false
+	}
+	return KeyPrefix + prefix, true
+}
+
+func HashKey(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
+
+// Matches сравнивает предъявленный ключ с сохранённым хешем за постоянное время.
+func (k *APIKey) Matches(raw string) bool {
+	return subtle.ConstantTimeCompare([]byte(HashKey(raw)), []byte(k.KeyHash)) == 1
+}
+
+func (k *APIKey) IsExpired(now time.Time) bool {
+	return k.ExpiresAt != nil && !now.Before(*k.ExpiresAt)
+}
+
+// HasScopes сообщает, выданы ли ключу все перечисленные права.
+func (k *APIKey) HasScopes(scopes ...string) bool {
+	for _, want := range scopes {
+		found := false
+		for _, s := range k.Scopes {
+			if s == want {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func normalizeScopes(scopes []string) ([]string, error) {
+	seen := make(map[string]struct{}, len(scopes))
+	normalized := make([]string, 0, len(scopes))
+	for _, s := range scopes {
+		s = strings.ToLower(strings.TrimSpace(s))
+		if _, ok := knownScopes[s]; !ok {
+			return nil, ErrUnknownScope.WithDetail(s)
+		}
+		if _, dup := seen[s]; dup {
+			continue
+		}
+		seen[s] = struct{}{}
+		normalized = append(normalized, s)
+	}
+	if len(normalized) == 0 {
+		return nil, ErrNoScopes
+	}
+	return normalized, nil
+}
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate api key: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
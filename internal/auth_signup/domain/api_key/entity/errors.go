@@ -0,0 +1,12 @@
+package entity
+
+import "github.com/1URose/marketplace/internal/common/apperror"
+
+var (
+	// ErrInvalidAPIKey — ключ не найден, не совпал, истёк или его владелец заблокирован; причина клиенту не раскрывается.
+	ErrInvalidAPIKey  = apperror.Unauthorized("api_key.invalid")
+	ErrAPIKeyNotFound = apperror.NotFound("api_key.not_found")
+	ErrUnknownScope   = apperror.Validation("api_key.unknown_scope")
+	ErrNoScopes       = apperror.Validation("api_key.no_scopes")
+	ErrExpiryInPast   = apperror.Validation("api_key.expiry_in_past")
+)
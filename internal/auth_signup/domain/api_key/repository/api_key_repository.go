@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+	"github.com/1URose/marketplace/internal/auth_signup/domain/api_key/entity"
+	"time"
+)
+
+type APIKeyRepository interface {
+	// Create сохраняет ключ и заполняет ID и CreatedAt.
+	Create(ctx context.Context, key *entity.APIKey) error
+	ListByUser(ctx context.Context, userID int) ([]*entity.APIKey, error)
+	// GetByPrefix возвращает nil, если ключа с такой открытой частью нет.
+	GetByPrefix(ctx context.Context, prefix string) (*entity.APIKey, error)
+	// Delete отзывает ключ владельца; false — такого ключа у пользователя нет.
+	Delete(ctx context.Context, userID, keyID int) (bool, error)
+	// TouchLastUsed обновляет время последнего использования не чаще раза в минуту.
+	TouchLastUsed(ctx context.Context, keyID int, at time.Time) error
+}
@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+// ResendRepository ограничивает частоту писем с подтверждением email.
+type ResendRepository interface {
+	// Acquire занимает окно cooldown для пользователя. Если окно уже занято предыдущим письмом,
+	// возвращает, сколько ещё ждать; 0 — письмо можно отправлять.
+	Acquire(ctx context.Context, userID int, cooldown time.Duration) (time.Duration, error)
+}
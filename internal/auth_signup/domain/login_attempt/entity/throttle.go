@@ -0,0 +1,53 @@
+package entity
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Threshold — после скольких неудачных попыток подряд включается задержка и после скольких — блокировка.
+type Threshold struct {
+	BackoffAfter int
+	LockoutAfter int
+}
+
+// ThrottlePolicy описывает защиту входа от перебора паролей.
+// Задержка растёт экспоненциально от BackoffBase до BackoffMax, а при достижении LockoutAfter
+// вход блокируется на LockoutDuration. Счётчики неудач живут FailureWindow с первой ошибки.
+type ThrottlePolicy struct {
+	FailureWindow   time.Duration
+	BackoffBase     time.Duration
+	BackoffMax      time.Duration
+	LockoutDuration time.Duration
+	Account         Threshold
+	IP              Threshold
+}
+
+// Delay возвращает, на сколько закрыть вход после failures неудачных попыток, и является ли это блокировкой.
+func (p ThrottlePolicy) Delay(t Threshold, failures int) (time.Duration, bool) {
+	if failures >= t.LockoutAfter {
+		return p.LockoutDuration, true
+	}
+	if failures < t.BackoffAfter {
+		return 0, false
+	}
+
+	delay := p.BackoffBase
+	for i := t.BackoffAfter; i < failures && delay < p.BackoffMax; i++ {
+		delay *= 2
+	}
+	if delay > p.BackoffMax {
+		delay = p.BackoffMax
+	}
+	return delay, false
+}
+
+// AccountKey — ключ счётчиков учётной записи; email нормализуется, чтобы регистр не давал обходить лимит.
+func AccountKey(email string) string {
+	return fmt.Sprintf("account:%s", strings.ToLower(strings.TrimSpace(email)))
+}
+
+func IPKey(ip string) string {
+	return fmt.Sprintf("ip:%s", ip)
+}
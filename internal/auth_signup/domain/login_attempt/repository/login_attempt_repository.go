@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+type LoginAttemptRepository interface {
+	// RegisterFailure увеличивает счётчик неудачных попыток по ключу и возвращает его новое значение.
+	RegisterFailure(ctx context.Context, key string, window time.Duration) (int, error)
+	// Block закрывает вход по ключу до момента until.
+	Block(ctx context.Context, key string, until time.Time) error
+	// BlockedUntil возвращает, до какого момента закрыт вход по ключу; нулевое время — вход открыт.
+	BlockedUntil(ctx context.Context, key string) (time.Time, error)
+	// Reset сбрасывает счётчик и блокировку по ключу.
+	Reset(ctx context.Context, key string) error
+}
@@ -0,0 +1,40 @@
+package entity
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"github.com/1URose/marketplace/internal/common/apperror"
+)
+
+const deviceSecretBytes = 32
+
+var (
+	// ErrInvalidMagicLink — ссылка подделана, истекла, уже использована или выдана для прежнего email.
+	ErrInvalidMagicLink = apperror.Unauthorized("auth.invalid_magic_link")
+	// ErrDeviceMismatch — ссылка привязана к другому устройству, например переслана.
+	ErrDeviceMismatch = apperror.Forbidden("auth.magic_link_device_mismatch")
+)
+
+// NewDeviceSecret возвращает секрет, который остаётся на запросившем ссылку устройстве, и его хеш для токена.
+func NewDeviceSecret() (secret, hash string, err error) {
+	b := make([]byte, deviceSecretBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+
+	secret = base64.RawURLEncoding.EncodeToString(b)
+	return secret, HashDeviceSecret(secret), nil
+}
+
+func HashDeviceSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// DeviceMatches сравнивает предъявленный секрет с хешем из токена за постоянное время.
+func DeviceMatches(secret, hash string) bool {
+	return subtle.ConstantTimeCompare([]byte(HashDeviceSecret(secret)), []byte(hash)) == 1
+}
@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+type MagicLinkRepository interface {
+	// AcquireSendWindow занимает окно cooldown для писем со ссылкой входа; false — ссылка уже недавно отправлялась.
+	AcquireSendWindow(ctx context.Context, userID int, cooldown time.Duration) (bool, error)
+	// MarkUsed отмечает ссылку по её jti использованной до ttl; false — её уже использовали.
+	MarkUsed(ctx context.Context, jti string, ttl time.Duration) (bool, error)
+}
@@ -0,0 +1,10 @@
+package entity
+
+import "github.com/1URose/marketplace/internal/common/apperror"
+
+var (
+	ErrMFAAlreadyEnabled = apperror.Conflict("mfa.already_enabled")
+	ErrMFANotEnrolled    = apperror.Validation("mfa.not_enrolled")
+	ErrMFANotEnabled     = apperror.Validation("mfa.not_enabled")
+	ErrInvalidMFACode    = apperror.Validation("mfa.invalid_code")
+)
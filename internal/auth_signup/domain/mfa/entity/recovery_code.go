@@ -0,0 +1,39 @@
+package entity
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// RecoveryCodesCount — сколько одноразовых кодов восстановления выдаётся при подключении TOTP.
+const RecoveryCodesCount = 10
+
+// NewRecoveryCodes генерирует коды вида «a1b2c-3d4e5».
+func NewRecoveryCodes() ([]string, error) {
+	codes := make([]string, RecoveryCodesCount)
+	for i := range codes {
+		b := make([]byte, 5)
+		if _, err := rand.Read(b); err != nil {
+			return nil, err
+		}
+		s := hex.EncodeToString(b)
+		codes[i] = s[:5] + "-" + s[5:]
+	}
+	return codes, nil
+}
+
+// NormalizeRecoveryCode приводит введённый код к виду, в котором он хешируется: без дефисов, пробелов и регистра.
+func NormalizeRecoveryCode(code string) string {
+	code = strings.ToLower(code)
+	code = strings.ReplaceAll(code, "-", "")
+	return strings.ReplaceAll(code, " ", "")
+}
+
+// HashRecoveryCode — SHA-256 от нормализованного кода. Код хранится только в виде хеша и показывается
+// пользователю один раз; он случайный, поэтому медленный хеш не нужен, а проверка сводится к поиску по хешу.
+func HashRecoveryCode(code string) string {
+	sum := sha256.Sum256([]byte(NormalizeRecoveryCode(code)))
+	return hex.EncodeToString(sum[:])
+}
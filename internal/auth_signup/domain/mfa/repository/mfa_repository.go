@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"context"
+	"github.com/1URose/marketplace/internal/auth_signup/domain/mfa/entity"
+)
+
+type MFARepository interface {
+	// GetTOTP возвращает nil, если пользователь не начинал подключение TOTP.
+	GetTOTP(ctx context.Context, userID int) (*entity.TOTP, error)
+	// SaveTOTPSecret начинает (или начинает заново) подключение; для уже включённого TOTP возвращает entity.ErrMFAAlreadyEnabled.
+	SaveTOTPSecret(ctx context.Context, userID int, secret string) error
+	// EnableTOTP подтверждает подключение кодом шага step и заменяет коды восстановления.
+	EnableTOTP(ctx context.Context, userID int, step int64, recoveryCodeHashes []string) error
+	// UseTOTPStep отмечает шаг использованным; false — шаг уже использован или TOTP выключен.
+	UseTOTPStep(ctx context.Context, userID int, step int64) (bool, error)
+	DisableTOTP(ctx context.Context, userID int) error
+
+	// UseRecoveryCode гасит неиспользованный код пользователя с хешем codeHash; false — такого кода нет
+	// или его уже успел использовать параллельный запрос.
+	UseRecoveryCode(ctx context.Context, userID int, codeHash string) (bool, error)
+}
@@ -0,0 +1,58 @@
+package entity
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"time"
+)
+
+// AuthRequest — незавершённый вход через провайдера: хранится до возвращения пользователя на callback.
+// State защищает callback от подделки (CSRF), Nonce привязывает ID-токен к этому входу,
+// CodeVerifier — секрет PKCE, без которого перехваченный код авторизации бесполезен.
+type AuthRequest struct {
+	Provider     string    `json:"provider"`
+	State        string    `json:"state"`
+	Nonce        string    `json:"nonce"`
+	CodeVerifier string    `json:"code_verifier"`
+	DeviceName   string    `json:"device_name"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+func NewAuthRequest(provider, deviceName string, now time.Time) (*AuthRequest, error) {
+	state, err := randomToken()
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := randomToken()
+	if err != nil {
+		return nil, err
+	}
+	verifier, err := randomToken()
+	if err != nil {
+		return nil, err
+	}
+
+	return &AuthRequest{
+		Provider:     provider,
+		State:        state,
+		Nonce:        nonce,
+		CodeVerifier: verifier,
+		DeviceName:   deviceName,
+		CreatedAt:    now,
+	}, nil
+}
+
+// CodeChallenge — PKCE-вызов метода S256 (RFC 7636).
+func (r *AuthRequest) CodeChallenge() string {
+	sum := sha256.Sum256([]byte(r.CodeVerifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func randomToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
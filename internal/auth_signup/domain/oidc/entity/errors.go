@@ -0,0 +1,17 @@
+package entity
+
+import "github.com/1URose/marketplace/internal/common/apperror"
+
+var (
+	ErrUnknownProvider = apperror.NotFound("oidc.unknown_provider")
+	// ErrInvalidState — state не выдавался, уже использован или истёк.
+	ErrInvalidState   = apperror.Unauthorized("oidc.invalid_response").WithDetailKey("oidc.invalid_state")
+	ErrNonceMismatch  = apperror.Unauthorized("oidc.invalid_response").WithDetailKey("oidc.nonce_mismatch")
+	ErrInvalidIDToken = apperror.Unauthorized("oidc.invalid_response").WithDetailKey("oidc.invalid_id_token")
+	// ErrEmailNotVerified — провайдер не подтвердил email, поэтому новую учётную запись к нему не привязать.
+	ErrEmailNotVerified = apperror.Forbidden("oidc.email_not_verified")
+	// ErrLocalAccountNotVerified — пользователь с этим email есть, но адрес не подтвердил: его мог завести
+	// кто угодно, поэтому внешняя учётная запись к нему не привязывается.
+	ErrLocalAccountNotVerified = apperror.Forbidden("oidc.local_account_not_verified")
+	ErrIdentityExists          = apperror.Conflict("oidc.identity_exists")
+)
@@ -0,0 +1,31 @@
+package entity
+
+import "time"
+
+// Identity связывает учётную запись у внешнего провайдера (provider + sub) с пользователем маркетплейса.
+type Identity struct {
+	ID          int
+	UserID      int
+	Provider    string
+	Subject     string
+	Email       string
+	CreatedAt   time.Time
+	LastLoginAt time.Time
+}
+
+func NewIdentity(userID int, provider, subject, email string) *Identity {
+	return &Identity{
+		UserID:   userID,
+		Provider: provider,
+		Subject:  subject,
+		Email:    email,
+	}
+}
+
+// ExternalClaims — проверенные данные ID-токена провайдера.
+type ExternalClaims struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Nonce         string
+}
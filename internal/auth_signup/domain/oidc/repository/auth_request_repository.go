@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"context"
+	"github.com/1URose/marketplace/internal/auth_signup/domain/oidc/entity"
+	"time"
+)
+
+type AuthRequestRepository interface {
+	Save(ctx context.Context, req *entity.AuthRequest, ttl time.Duration) error
+	// Take возвращает запрос по state и сразу удаляет его, так что state срабатывает один раз; nil — не найден.
+	Take(ctx context.Context, state string) (*entity.AuthRequest, error)
+}
@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+	"github.com/1URose/marketplace/internal/auth_signup/domain/oidc/entity"
+)
+
+// IdentityProvider — внешний OpenID Connect провайдер.
+type IdentityProvider interface {
+	Name() string
+	// AuthCodeURL возвращает адрес страницы входа провайдера для authorization code flow с PKCE.
+	AuthCodeURL(ctx context.Context, state, nonce, codeChallenge string) (string, error)
+	// Exchange обменивает код авторизации на ID-токен и возвращает его проверенные claims.
+	// Проверка nonce остаётся вызывающему: он знает, какой nonce выдавался.
+	Exchange(ctx context.Context, code, codeVerifier string) (*entity.ExternalClaims, error)
+}
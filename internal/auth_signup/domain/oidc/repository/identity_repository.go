@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+	"github.com/1URose/marketplace/internal/auth_signup/domain/oidc/entity"
+)
+
+type IdentityRepository interface {
+	// GetUserID возвращает пользователя, к которому привязана внешняя учётная запись; 0 — не привязана.
+	GetUserID(ctx context.Context, provider, subject string) (int, error)
+	// Link привязывает внешнюю учётную запись к существующему пользователю.
+	Link(ctx context.Context, identity *entity.Identity) error
+	// CreateUserWithIdentity в одной транзакции создаёт пользователя без пароля и привязывает к нему учётную запись.
+	CreateUserWithIdentity(ctx context.Context, email string, identity *entity.Identity) (int, error)
+	TouchLogin(ctx context.Context, provider, subject string) error
+}
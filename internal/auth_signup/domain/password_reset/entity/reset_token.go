@@ -0,0 +1,32 @@
+package entity
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"github.com/1URose/marketplace/internal/common/apperror"
+)
+
+const tokenBytes = 32
+
+// ErrInvalidResetToken — токен сброса пароля неизвестен, истёк или уже использован.
+var ErrInvalidResetToken = apperror.Validation("auth.invalid_reset_token")
+
+// NewResetToken возвращает случайный токен для ссылки из письма и его хеш. Хранится только хеш,
+// поэтому утечка хранилища не даёт сбросить чужой пароль.
+func NewResetToken() (raw, hash string, err error) {
+	b := make([]byte, tokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+
+	raw = base64.RawURLEncoding.EncodeToString(b)
+	return raw, HashToken(raw), nil
+}
+
+// HashToken — SHA-256 от токена: токен случайный и длинный, поэтому медленный хеш не нужен.
+func HashToken(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
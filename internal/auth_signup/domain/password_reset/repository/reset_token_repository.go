@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+type ResetTokenRepository interface {
+	// Save сохраняет хеш токена на ttl. У пользователя действует только последний выданный токен.
+	Save(ctx context.Context, userID int, tokenHash string, ttl time.Duration) error
+	// Peek возвращает пользователя по хешу токена, не расходуя токен; 0 — не найден.
+	Peek(ctx context.Context, tokenHash string) (int, error)
+	// Take возвращает пользователя по хешу токена и сразу удаляет токен, так что он срабатывает один раз; 0 — не найден.
+	Take(ctx context.Context, tokenHash string) (int, error)
+	// AcquireSendWindow занимает окно cooldown для писем сброса пользователю; false — письмо уже недавно отправлялось.
+	AcquireSendWindow(ctx context.Context, userID int, cooldown time.Duration) (bool, error)
+}
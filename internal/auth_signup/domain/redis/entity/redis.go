@@ -1,13 +0,0 @@
-package entity
-
-type Redis struct {
-	Email        string `json:"email"`
-	RefreshToken string `json:"refresh_token"`
-}
-
-func NewSession(email string, refreshToken string) *Redis {
-	return &Redis{
-		Email:        email,
-		RefreshToken: refreshToken,
-	}
-}
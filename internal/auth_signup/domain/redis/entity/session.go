@@ -0,0 +1,70 @@
+package entity
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+)
+
+// Session — вход пользователя с одного устройства. Каждая сессия хранит свой refresh-токен,
+// поэтому вход с телефона не затрагивает сессию на ноутбуке.
+// Сессия же образует семейство refresh-токенов: каждый обмен выдаёт следующий токен семейства,
+// а предъявление уже заменённого токена означает его кражу.
+type Session struct {
+	ID           string    `json:"id"`
+	UserID       int       `json:"user_id"`
+	Email        string    `json:"email"`
+	RefreshToken string    `json:"refresh_token"`
+	DeviceName   string    `json:"device_name"`
+	IP           string    `json:"ip"`
+	UserAgent    string    `json:"user_agent"`
+	CreatedAt    time.Time `json:"created_at"`
+	LastUsedAt   time.Time `json:"last_used_at"`
+
+	PreviousRefreshToken string    `json:"previous_refresh_token,omitempty"`
+	RotatedAt            time.Time `json:"rotated_at,omitempty"`
+}
+
+func NewSession(userID int, email, deviceName, ip, userAgent string, now time.Time) (*Session, error) {
+	id, err := newSessionID()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Session{
+		ID:         id,
+		UserID:     userID,
+		Email:      email,
+		DeviceName: deviceName,
+		IP:         ip,
+		UserAgent:  userAgent,
+		CreatedAt:  now,
+		LastUsedAt: now,
+	}, nil
+}
+
+// Rotate заменяет refresh-токен следующим в семействе и отмечает использование сессии.
+func (s *Session) Rotate(refreshToken, ip, userAgent string, now time.Time) {
+	s.PreviousRefreshToken = s.RefreshToken
+	s.RefreshToken = refreshToken
+	s.RotatedAt = now
+	s.IP = ip
+	s.UserAgent = userAgent
+	s.LastUsedAt = now
+}
+
+// InGrace сообщает, что предъявлен только что заменённый токен и окно grace ещё не закрылось:
+// так бывает, когда клиент параллельно повторяет запрос на обновление.
+func (s *Session) InGrace(refreshToken string, grace time.Duration, now time.Time) bool {
+	return s.PreviousRefreshToken != "" &&
+		s.PreviousRefreshToken == refreshToken &&
+		now.Sub(s.RotatedAt) <= grace
+}
+
+func newSessionID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
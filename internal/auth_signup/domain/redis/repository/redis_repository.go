@@ -3,9 +3,19 @@ package repository
 import (
 	"context"
 	"github.com/1URose/marketplace/internal/auth_signup/domain/redis/entity"
+	"time"
 )
 
 type RedisRepository interface {
-	Set(ctx context.Context, hash *entity.Redis) error
-	Get(ctx context.Context, email string) (*entity.Redis, error)
+	// Set сохраняет сессию и закрывает самые давно неиспользуемые сессии пользователя сверх maxSessions;
+	// возвращает идентификаторы закрытых сессий.
+	Set(ctx context.Context, session *entity.Session, maxSessions int) ([]string, error)
+	Get(ctx context.Context, sessionID string) (*entity.Session, error)
+	Replace(ctx context.Context, session *entity.Session, expectedRefreshToken string) (bool, error)
+	List(ctx context.Context, userID int) ([]*entity.Session, error)
+	Delete(ctx context.Context, userID int, sessionID string) error
+	// DeleteAll атомарно удаляет все сессии пользователя и возвращает идентификаторы удалённых.
+	DeleteAll(ctx context.Context, userID int) ([]string, error)
+	SetTokensValidAfter(ctx context.Context, userID int, validAfter time.Time, ttl time.Duration) error
+	GetTokensValidAfter(ctx context.Context, userID int) (time.Time, error)
 }
@@ -0,0 +1,18 @@
+package entity
+
+import (
+	"github.com/1URose/marketplace/internal/common/apperror"
+	"time"
+)
+
+var ErrUnknownType = apperror.Validation("security_event.unknown_type")
+
+// Filter отбирает события журнала; пустые поля не ограничивают выборку.
+type Filter struct {
+	UserID *int
+	Types  []string
+	IP     string
+	// From и To ограничивают время события: From включительно, To не включительно.
+	From *time.Time
+	To   *time.Time
+}
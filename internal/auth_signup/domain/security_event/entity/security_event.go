@@ -0,0 +1,82 @@
+package entity
+
+import "time"
+
+const (
+	TypeSignUp = "signup"
+	// TypeLoginSucceeded — открыта новая сессия: вход по паролю, второму фактору, OIDC или ссылке из письма.
+	TypeLoginSucceeded = "login_succeeded"
+	// TypeLoginFailed — неверный пароль или код второго фактора для существующей учётной записи.
+	TypeLoginFailed = "login_failed"
+	// TypeTokenRefreshed — refresh-токен сессии обменян на новую пару токенов.
+	TypeTokenRefreshed = "token_refreshed"
+	// TypeRefreshTokenReuse — предъявлен уже заменённый refresh-токен, семейство отозвано.
+	TypeRefreshTokenReuse = "refresh_token_reuse"
+	TypeLogout            = "logout"
+	// TypeLogoutAll — закрыты все сессии пользователя и отозваны его токены: выход на всех устройствах,
+	// отзыв администратором, сброс пароля или понижение роли.
+	TypeLogoutAll = "logout_all"
+	// TypeAccountLocked — вход в учётную запись заблокирован после серии неудачных попыток.
+	TypeAccountLocked = "account_locked"
+	// TypeAccountUnlocked — администратор снял блокировку входа.
+	TypeAccountUnlocked = "account_unlocked"
+	TypeMFAEnabled      = "mfa_enabled"
+	TypeMFADisabled     = "mfa_disabled"
+	// TypeRecoveryCodeUsed — вход выполнен по одноразовому коду восстановления вместо TOTP.
+	TypeRecoveryCodeUsed = "recovery_code_used"
+	// TypeRoleChanged — администратор изменил роль пользователя.
+	TypeRoleChanged = "role_changed"
+	// TypePasswordReset — пароль сброшен по ссылке из письма, все сессии закрыты.
+	TypePasswordReset = "password_reset"
+	// TypePasswordChanged — пользователь сменил пароль, остальные сессии закрыты.
+	TypePasswordChanged = "password_changed"
+)
+
+// Types — все типы событий; по ним проверяется фильтр журнала.
+var Types = []string{
+	TypeSignUp,
+	TypeLoginSucceeded,
+	TypeLoginFailed,
+	TypeTokenRefreshed,
+	TypeRefreshTokenReuse,
+	TypeLogout,
+	TypeLogoutAll,
+	TypeAccountLocked,
+	TypeAccountUnlocked,
+	TypeMFAEnabled,
+	TypeMFADisabled,
+	TypeRecoveryCodeUsed,
+	TypeRoleChanged,
+	TypePasswordReset,
+	TypePasswordChanged,
+}
+
+func IsKnownType(eventType string) bool {
+	for _, t := range Types {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+type SecurityEvent struct {
+	ID        int64
+	Type      string
+	UserID    int
+	SessionID string
+	IP        string
+	UserAgent string
+	CreatedAt time.Time
+}
+
+func NewSecurityEvent(eventType string, userID int, sessionID, ip, userAgent string, now time.Time) *SecurityEvent {
+	return &SecurityEvent{
+		Type:      eventType,
+		UserID:    userID,
+		SessionID: sessionID,
+		IP:        ip,
+		UserAgent: userAgent,
+		CreatedAt: now,
+	}
+}
@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+	"github.com/1URose/marketplace/internal/auth_signup/domain/security_event/entity"
+	"time"
+)
+
+type SecurityEventRepository interface {
+	Record(ctx context.Context, event *entity.SecurityEvent) error
+	// List возвращает события по фильтру, начиная с самых новых.
+	List(ctx context.Context, filter *entity.Filter, offset, limit int) ([]*entity.SecurityEvent, error)
+	Count(ctx context.Context, filter *entity.Filter) (int, error)
+	// DeleteBefore удаляет события старше before и возвращает, сколько удалено.
+	DeleteBefore(ctx context.Context, before time.Time) (int64, error)
+}
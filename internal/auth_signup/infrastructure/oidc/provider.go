@@ -0,0 +1,299 @@
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/1URose/marketplace/internal/auth_signup/domain/oidc/entity"
+	oidcConfig "github.com/1URose/marketplace/internal/common/config/oidc"
+	"github.com/golang-jwt/jwt/v4"
+)
+
+const (
+	httpTimeout = 10 * time.Second
+	// maxResponseSize ограничивает ответы провайдера: discovery, JWKS и токены заведомо меньше.
+	maxResponseSize = 1 << 20
+	// jwksRefreshInterval — не чаще этого перечитываем JWKS, встретив незнакомый kid:
+	// так провайдер может ротировать ключи, а поток токенов с чужим kid не заваливает его запросами.
+	jwksRefreshInterval = time.Minute
+)
+
+// discovery — нужная часть /.well-known/openid-configuration.
+type discovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+type idTokenClaims struct {
+	Email string `json:"email"`
+	// часть провайдеров отдаёт email_verified строкой "true"
+	EmailVerified interface{} `json:"email_verified"`
+	Nonce         string      `json:"nonce"`
+	jwt.RegisteredClaims
+}
+
+// Provider — OpenID Connect провайдер. Метаданные и ключи загружаются при первом входе,
+// поэтому недоступный провайдер не мешает запуску сервиса.
+type Provider struct {
+	cfg    oidcConfig.Provider
+	client *http.Client
+
+	mu            sync.Mutex
+	meta          *discovery
+	keys          map[string]interface{}
+	keysFetchedAt time.Time
+}
+
+func NewProvider(cfg oidcConfig.Provider) *Provider {
+	log.Printf("[oidc:%s] NewProvider initialized: issuer=%s", cfg.Name, cfg.Issuer)
+
+	return &Provider{
+		cfg:    cfg,
+		client: &http.Client{Timeout: httpTimeout},
+	}
+}
+
+func (p *Provider) Name() string {
+	return p.cfg.Name
+}
+
+func (p *Provider) AuthCodeURL(ctx context.Context, state, nonce, codeChallenge string) (string, error) {
+	meta, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	q := url.Values{}
+	q.Set("response_type", "code")
+	q.Set("client_id", p.cfg.ClientID)
+	q.Set("redirect_uri", p.cfg.RedirectURL)
+	q.Set("scope", strings.Join(p.cfg.Scopes, " "))
+	q.Set("state", state)
+	q.Set("nonce", nonce)
+	q.Set("code_challenge", codeChallenge)
+	q.Set("code_challenge_method", "S256")
+
+	sep := "?"
+	if strings.Contains(meta.AuthorizationEndpoint, "?") {
+		sep = "&"
+	}
+	return meta.AuthorizationEndpoint + sep + q.Encode(), nil
+}
+
+func (p *Provider) Exchange(ctx context.Context, code, codeVerifier string) (*entity.ExternalClaims, error) {
+	log.Printf("[oidc:%s] Exchange called", p.cfg.Name)
+
+	meta, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", p.cfg.RedirectURL)
+	form.Set("code_verifier", codeVerifier)
+	form.Set("client_id", p.cfg.ClientID)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, meta.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("build token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	// client_secret_basic (RFC 6749, 2.3.1): id и секрет URL-кодируются перед Basic-аутентификацией
+	req.SetBasicAuth(url.QueryEscape(p.cfg.ClientID), url.QueryEscape(p.cfg.ClientSecret))
+
+	var resp struct {
+		IDToken          string `json:"id_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	status, err := p.doJSON(req, &resp)
+	if err != nil {
+		return nil, fmt.Errorf("token request: %w", err)
+	}
+	if status != http.StatusOK || resp.IDToken == "" {
+		log.Printf("[oidc:%s][ERROR] token endpoint: status=%d error=%q description=%q",
+			p.cfg.Name, status, resp.Error, resp.ErrorDescription,
+		)
+		return nil, fmt.Errorf("%w: token endpoint returned %d %s", entity.ErrInvalidIDToken, status, resp.Error)
+	}
+
+	return p.verifyIDToken(ctx, meta, resp.IDToken)
+}
+
+// verifyIDToken проверяет подпись по JWKS провайдера, iss, aud и срок действия (OpenID Connect Core, 3.1.3.7).
+func (p *Provider) verifyIDToken(ctx context.Context, meta *discovery, raw string) (*entity.ExternalClaims, error) {
+	parser := jwt.NewParser(jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "ES256", "ES384", "EdDSA"}))
+
+	var claims idTokenClaims
+	_, err := parser.ParseWithClaims(raw, &claims, func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+		return p.key(ctx, meta, kid)
+	})
+	if err != nil {
+		log.Printf("[oidc:%s][ERROR] verify id token: %v", p.cfg.Name, err)
+		return nil, fmt.Errorf("%w: %v", entity.ErrInvalidIDToken, err)
+	}
+
+	now := time.Now()
+	switch {
+	case claims.Issuer != meta.Issuer:
+		return nil, fmt.Errorf("%w: unexpected issuer %q", entity.ErrInvalidIDToken, claims.Issuer)
+	case !claims.VerifyAudience(p.cfg.ClientID, true):
+		return nil, fmt.Errorf("%w: audience mismatch", entity.ErrInvalidIDToken)
+	case !claims.VerifyExpiresAt(now, true):
+		return nil, fmt.Errorf("%w: expired", entity.ErrInvalidIDToken)
+	case claims.Subject == "":
+		return nil, fmt.Errorf("%w: empty subject", entity.ErrInvalidIDToken)
+	}
+
+	verified := false
+	switch v := claims.EmailVerified.(type) {
+	case bool:
+		verified = v
+	case string:
+		verified = v == "true"
+	}
+
+	log.Printf("[oidc:%s] id token verified: sub=%s email=%s verified=%t", p.cfg.Name, claims.Subject, claims.Email, verified)
+
+	return &entity.ExternalClaims{
+		Subject:       claims.Subject,
+		Email:         strings.TrimSpace(claims.Email),
+		EmailVerified: verified,
+		Nonce:         claims.Nonce,
+	}, nil
+}
+
+func (p *Provider) discover(ctx context.Context) (*discovery, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.meta != nil {
+		return p.meta, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.cfg.Issuer+"/.well-known/openid-configuration", nil)
+	if err != nil {
+		return nil, fmt.Errorf("build discovery request: %w", err)
+	}
+
+	var meta discovery
+	status, err := p.doJSON(req, &meta)
+	if err != nil {
+		return nil, fmt.Errorf("discovery: %w", err)
+	}
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("discovery: unexpected status %d", status)
+	}
+	if strings.TrimSuffix(meta.Issuer, "/") != p.cfg.Issuer {
+		return nil, fmt.Errorf("discovery: issuer %q does not match configured %q", meta.Issuer, p.cfg.Issuer)
+	}
+	if meta.AuthorizationEndpoint == "" || meta.TokenEndpoint == "" || meta.JWKSURI == "" {
+		return nil, fmt.Errorf("discovery: incomplete provider metadata")
+	}
+
+	log.Printf("[oidc:%s] discovery loaded: authorize=%s token=%s jwks=%s",
+		p.cfg.Name, meta.AuthorizationEndpoint, meta.TokenEndpoint, meta.JWKSURI,
+	)
+	p.meta = &meta
+	return p.meta, nil
+}
+
+// key возвращает открытый ключ провайдера по kid, при необходимости перечитывая JWKS.
+func (p *Provider) key(ctx context.Context, meta *discovery, kid string) (interface{}, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if key, ok := p.lookup(kid); ok {
+		return key, nil
+	}
+	if time.Since(p.keysFetchedAt) < jwksRefreshInterval {
+		return nil, fmt.Errorf("unknown key id %q", kid)
+	}
+
+	keys, err := p.fetchKeys(ctx, meta.JWKSURI)
+	if err != nil {
+		return nil, err
+	}
+	p.keys, p.keysFetchedAt = keys, time.Now()
+
+	if key, ok := p.lookup(kid); ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("unknown key id %q", kid)
+}
+
+// lookup ищет ключ по kid; токен без kid допустим, только если у провайдера единственный ключ.
+func (p *Provider) lookup(kid string) (interface{}, bool) {
+	if kid == "" && len(p.keys) == 1 {
+		for _, key := range p.keys {
+			return key, true
+		}
+	}
+	key, ok := p.keys[kid]
+	return key, ok
+}
+
+func (p *Provider) fetchKeys(ctx context.Context, jwksURI string) (map[string]interface{}, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, jwksURI, nil)
+	if err != nil {
+		return nil, fmt.Errorf("build jwks request: %w", err)
+	}
+
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	status, err := p.doJSON(req, &set)
+	if err != nil {
+		return nil, fmt.Errorf("jwks: %w", err)
+	}
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("jwks: unexpected status %d", status)
+	}
+
+	keys := make(map[string]interface{}, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		key, err := k.publicKey()
+		if err != nil {
+			log.Printf("[oidc:%s][ERROR] skip jwk kid=%q: %v", p.cfg.Name, k.Kid, err)
+			continue
+		}
+		keys[k.Kid] = key
+	}
+
+	log.Printf("[oidc:%s] jwks loaded: keys=%d", p.cfg.Name, len(keys))
+	return keys, nil
+}
+
+func (p *Provider) doJSON(req *http.Request, out interface{}) (int, error) {
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return 0, err
+	}
+	if err := json.Unmarshal(body, out); err != nil && resp.StatusCode == http.StatusOK {
+		return resp.StatusCode, fmt.Errorf("decode response: %w", err)
+	}
+	return resp.StatusCode, nil
+}
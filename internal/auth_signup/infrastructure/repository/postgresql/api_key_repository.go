@@ -0,0 +1,125 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/1URose/marketplace/internal/auth_signup/domain/api_key/entity"
+	"github.com/1URose/marketplace/internal/common/db/postgresql"
+	"github.com/jackc/pgx/v5"
+	"log"
+	"time"
+)
+
+type APIKeyRepository struct {
+	Connection *postgresql.Client
+}
+
+func NewAPIKeyRepository(connection *postgresql.Client) *APIKeyRepository {
+	log.Println("[repository:api_key] NewAPIKeyRepository initialized")
+	return &APIKeyRepository{Connection: connection}
+}
+
+func (kr *APIKeyRepository) Create(ctx context.Context, key *entity.APIKey) error {
+	log.Printf("[repository:api_key] Create called: userID=%d prefix=%s", key.UserID, key.Prefix)
+
+	const q = `
+        INSERT INTO api_keys (user_id, name, prefix, key_hash, scopes, expires_at)
+        VALUES ($1, $2, $3, $4, $5, $6)
+        RETURNING id, created_at
+    `
+	err := kr.Connection.GetPool().
+		QueryRow(ctx, q, key.UserID, key.Name, key.Prefix, key.KeyHash, key.Scopes, key.ExpiresAt).
+		Scan(&key.ID, &key.CreatedAt)
+	if err != nil {
+		log.Printf("[repository:api_key][ERROR] Create failed: %v", err)
+		return fmt.Errorf("Create api key scan: %w", err)
+	}
+
+	return nil
+}
+
+func (kr *APIKeyRepository) ListByUser(ctx context.Context, userID int) ([]*entity.APIKey, error) {
+	log.Printf("[repository:api_key] ListByUser called: userID=%d", userID)
+
+	const q = `
+        SELECT id, user_id, name, prefix, key_hash, scopes, expires_at, last_used_at, created_at
+        FROM api_keys
+        WHERE user_id = $1
+        ORDER BY created_at DESC
+    `
+	rows, err := kr.Connection.GetPool().Query(ctx, q, userID)
+	if err != nil {
+		log.Printf("[repository:api_key][ERROR] ListByUser query failed: %v", err)
+		return nil, fmt.Errorf("ListByUser query: %w", err)
+	}
+	defer rows.Close()
+
+	keys := make([]*entity.APIKey, 0)
+	for rows.Next() {
+		k := new(entity.APIKey)
+		if err := scanAPIKey(rows, k); err != nil {
+			log.Printf("[repository:api_key][ERROR] ListByUser scan failed: %v", err)
+			return nil, fmt.Errorf("ListByUser scan: %w", err)
+		}
+		keys = append(keys, k)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ListByUser rows: %w", err)
+	}
+
+	return keys, nil
+}
+
+func (kr *APIKeyRepository) GetByPrefix(ctx context.Context, prefix string) (*entity.APIKey, error) {
+	const q = `
+        SELECT id, user_id, name, prefix, key_hash, scopes, expires_at, last_used_at, created_at
+        FROM api_keys
+        WHERE prefix = $1
+    `
+	k := new(entity.APIKey)
+	err := scanAPIKey(kr.Connection.GetPool().QueryRow(ctx, q, prefix), k)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		log.Printf("[repository:api_key][ERROR] GetByPrefix scan failed: %v", err)
+		return nil, fmt.Errorf("GetByPrefix scan: %w", err)
+	}
+
+	return k, nil
+}
+
+func (kr *APIKeyRepository) Delete(ctx context.Context, userID, keyID int) (bool, error) {
+	log.Printf("[repository:api_key] Delete called: userID=%d keyID=%d", userID, keyID)
+
+	const q = `DELETE FROM api_keys WHERE id = $1 AND user_id = $2`
+
+	tag, err := kr.Connection.GetPool().Exec(ctx, q, keyID, userID)
+	if err != nil {
+		log.Printf("[repository:api_key][ERROR] Delete failed: %v", err)
+		return false, fmt.Errorf("Delete api key exec: %w", err)
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+func (kr *APIKeyRepository) TouchLastUsed(ctx context.Context, keyID int, at time.Time) error {
+	// ключ интеграции может дёргать API постоянно: минутной точности достаточно, а запись не на каждый запрос
+	const q = `
+        UPDATE api_keys
+        SET last_used_at = $2
+        WHERE id = $1 AND (last_used_at IS NULL OR last_used_at < $2 - interval '1 minute')
+    `
+	if _, err := kr.Connection.GetPool().Exec(ctx, q, keyID, at); err != nil {
+		log.Printf("[repository:api_key][ERROR] TouchLastUsed failed: %v", err)
+		return fmt.Errorf("TouchLastUsed exec: %w", err)
+	}
+
+	return nil
+}
+
+func scanAPIKey(row pgx.Row, k *entity.APIKey) error {
+	return row.Scan(&k.ID, &k.UserID, &k.Name, &k.Prefix, &k.KeyHash, &k.Scopes, &k.ExpiresAt, &k.LastUsedAt, &k.CreatedAt)
+}
@@ -0,0 +1,146 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/1URose/marketplace/internal/auth_signup/domain/oidc/entity"
+	"github.com/1URose/marketplace/internal/common/db/postgresql"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"log"
+)
+
+const pgUniqueViolation = "23505"
+
+type IdentityRepository struct {
+	Connection *postgresql.Client
+}
+
+func NewIdentityRepository(connection *postgresql.Client) *IdentityRepository {
+	log.Println("[repository:identity] NewIdentityRepository initialized")
+	return &IdentityRepository{Connection: connection}
+}
+
+func (ir *IdentityRepository) GetUserID(ctx context.Context, provider, subject string) (int, error) {
+	log.Printf("[repository:identity] GetUserID called: provider=%s subject=%s", provider, subject)
+
+	const q = `
+        SELECT user_id
+        FROM user_identities
+        WHERE provider = $1 AND subject = $2
+    `
+	var userID int
+	err := ir.Connection.GetPool().QueryRow(ctx, q, provider, subject).Scan(&userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, nil
+	}
+	if err != nil {
+		log.Printf("[repository:identity][ERROR] GetUserID scan failed: %v", err)
+		return 0, fmt.Errorf("GetUserID scan: %w", err)
+	}
+
+	return userID, nil
+}
+
+func (ir *IdentityRepository) Link(ctx context.Context, identity *entity.Identity) error {
+	log.Printf("[repository:identity] Link called: userID=%d provider=%s subject=%s",
+		identity.UserID, identity.Provider, identity.Subject,
+	)
+
+	const q = `
+        INSERT INTO user_identities (user_id, provider, subject, email)
+        VALUES ($1, $2, $3, $4)
+        RETURNING id, created_at, last_login_at
+    `
+	err := ir.Connection.GetPool().
+		QueryRow(ctx, q, identity.UserID, identity.Provider, identity.Subject, identity.Email).
+		Scan(&identity.ID, &identity.CreatedAt, &identity.LastLoginAt)
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == pgUniqueViolation {
+		log.Printf("[repository:identity][ERROR] Link: identity already linked")
+		return entity.ErrIdentityExists
+	}
+	if err != nil {
+		log.Printf("[repository:identity][ERROR] Link scan failed: %v", err)
+		return fmt.Errorf("Link scan: %w", err)
+	}
+
+	return nil
+}
+
+func (ir *IdentityRepository) CreateUserWithIdentity(ctx context.Context, email string, identity *entity.Identity) (int, error) {
+	log.Printf("[repository:identity] CreateUserWithIdentity called: email=%q provider=%s", email, identity.Provider)
+
+	tx, err := ir.Connection.GetPool().Begin(ctx)
+	if err != nil {
+		log.Printf("[repository:identity][ERROR] CreateUserWithIdentity begin tx failed: %v", err)
+		return 0, fmt.Errorf("CreateUserWithIdentity begin tx: %w", err)
+	}
+	defer func() {
+		if err := tx.Rollback(ctx); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+			log.Printf("[repository:identity][ERROR] CreateUserWithIdentity rollback failed: %v", err)
+		}
+	}()
+
+	// пустой password_hash не совпадёт ни с одним паролем: войти можно только через провайдера.
+	// Адрес подтверждён провайдером, поэтому отдельное письмо не нужно.
+	const userQuery = `
+        INSERT INTO users (email, password_hash, email_verified_at)
+        VALUES ($1, '', now())
+        RETURNING id
+    `
+	var userID int
+	err = tx.QueryRow(ctx, userQuery, email).Scan(&userID)
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == pgUniqueViolation {
+		log.Printf("[repository:identity][ERROR] CreateUserWithIdentity: user %q already exists", email)
+		return 0, entity.ErrIdentityExists
+	}
+	if err != nil {
+		log.Printf("[repository:identity][ERROR] CreateUserWithIdentity insert user failed: %v", err)
+		return 0, fmt.Errorf("CreateUserWithIdentity insert user: %w", err)
+	}
+
+	const identityQuery = `
+        INSERT INTO user_identities (user_id, provider, subject, email)
+        VALUES ($1, $2, $3, $4)
+        RETURNING id, created_at, last_login_at
+    `
+	err = tx.QueryRow(ctx, identityQuery, userID, identity.Provider, identity.Subject, identity.Email).
+		Scan(&identity.ID, &identity.CreatedAt, &identity.LastLoginAt)
+	if errors.As(err, &pgErr) && pgErr.Code == pgUniqueViolation {
+		log.Printf("[repository:identity][ERROR] CreateUserWithIdentity: identity already linked")
+		return 0, entity.ErrIdentityExists
+	}
+	if err != nil {
+		log.Printf("[repository:identity][ERROR] CreateUserWithIdentity insert identity failed: %v", err)
+		return 0, fmt.Errorf("CreateUserWithIdentity insert identity: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		log.Printf("[repository:identity][ERROR] CreateUserWithIdentity commit failed: %v", err)
+		return 0, fmt.Errorf("CreateUserWithIdentity commit: %w", err)
+	}
+
+	identity.UserID = userID
+	log.Printf("[repository:identity] CreateUserWithIdentity succeeded: userID=%d", userID)
+
+	return userID, nil
+}
+
+func (ir *IdentityRepository) TouchLogin(ctx context.Context, provider, subject string) error {
+	const q = `
+        UPDATE user_identities
+        SET last_login_at = now()
+        WHERE provider = $1 AND subject = $2
+    `
+	if _, err := ir.Connection.GetPool().Exec(ctx, q, provider, subject); err != nil {
+		log.Printf("[repository:identity][ERROR] TouchLogin failed: %v", err)
+		return fmt.Errorf("TouchLogin exec: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,179 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/1URose/marketplace/internal/auth_signup/domain/mfa/entity"
+	"github.com/1URose/marketplace/internal/common/db/postgresql"
+	"github.com/jackc/pgx/v5"
+	"log"
+)
+
+type MFARepository struct {
+	Connection *postgresql.Client
+}
+
+func NewMFARepository(connection *postgresql.Client) *MFARepository {
+	log.Println("[repository:mfa] NewMFARepository initialized")
+	return &MFARepository{Connection: connection}
+}
+
+func (mr *MFARepository) GetTOTP(ctx context.Context, userID int) (*entity.TOTP, error) {
+	log.Printf("[repository:mfa] GetTOTP called: userID=%d", userID)
+
+	const q = `
+        SELECT user_id, secret, enabled_at, last_used_step, created_at
+        FROM user_totp
+        WHERE user_id = $1
+    `
+	t := new(entity.TOTP)
+	err := mr.Connection.GetPool().
+		QueryRow(ctx, q, userID).
+		Scan(&t.UserID, &t.Secret, &t.EnabledAt, &t.LastUsedStep, &t.CreatedAt)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		log.Printf("[repository:mfa][ERROR] GetTOTP scan failed: %v", err)
+		return nil, fmt.Errorf("GetTOTP scan: %w", err)
+	}
+
+	return t, nil
+}
+
+func (mr *MFARepository) SaveTOTPSecret(ctx context.Context, userID int, secret string) error {
+	log.Printf("[repository:mfa] SaveTOTPSecret called: userID=%d", userID)
+
+	// включённый TOTP не перезаписывается: сначала его нужно выключить
+	const q = `
+        INSERT INTO user_totp (user_id, secret)
+        VALUES ($1, $2)
+        ON CONFLICT (user_id) DO UPDATE
+            SET secret = EXCLUDED.secret, last_used_step = 0, created_at = now()
+            WHERE user_totp.enabled_at IS NULL
+    `
+	tag, err := mr.Connection.GetPool().Exec(ctx, q, userID, secret)
+	if err != nil {
+		log.Printf("[repository:mfa][ERROR] SaveTOTPSecret failed: %v", err)
+		return fmt.Errorf("SaveTOTPSecret exec: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return entity.ErrMFAAlreadyEnabled
+	}
+
+	return nil
+}
+
+func (mr *MFARepository) EnableTOTP(ctx context.Context, userID int, step int64, recoveryCodeHashes []string) error {
+	log.Printf("[repository:mfa] EnableTOTP called: userID=%d codes=%d", userID, len(recoveryCodeHashes))
+
+	tx, err := mr.Connection.GetPool().Begin(ctx)
+	if err != nil {
+		log.Printf("[repository:mfa][ERROR] EnableTOTP begin tx failed: %v", err)
+		return fmt.Errorf("EnableTOTP begin tx: %w", err)
+	}
+	defer func() {
+		if err := tx.Rollback(ctx); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+			log.Printf("[repository:mfa][ERROR] EnableTOTP rollback failed: %v", err)
+		}
+	}()
+
+	const enableQuery = `
+        UPDATE user_totp
+        SET enabled_at = now(), last_used_step = $2
+        WHERE user_id = $1 AND enabled_at IS NULL
+    `
+	tag, err := tx.Exec(ctx, enableQuery, userID, step)
+	if err != nil {
+		log.Printf("[repository:mfa][ERROR] EnableTOTP update failed: %v", err)
+		return fmt.Errorf("EnableTOTP update: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return entity.ErrMFAAlreadyEnabled
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM user_recovery_codes WHERE user_id = $1`, userID); err != nil {
+		log.Printf("[repository:mfa][ERROR] EnableTOTP delete codes failed: %v", err)
+		return fmt.Errorf("EnableTOTP delete codes: %w", err)
+	}
+
+	const insertQuery = `
+        INSERT INTO user_recovery_codes (user_id, code_hash)
+        SELECT $1, unnest($2::text[])
+    `
+	if _, err := tx.Exec(ctx, insertQuery, userID, recoveryCodeHashes); err != nil {
+		log.Printf("[repository:mfa][ERROR] EnableTOTP insert codes failed: %v", err)
+		return fmt.Errorf("EnableTOTP insert codes: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		log.Printf("[repository:mfa][ERROR] EnableTOTP commit failed: %v", err)
+		return fmt.Errorf("EnableTOTP commit: %w", err)
+	}
+
+	log.Printf("[repository:mfa] EnableTOTP succeeded: userID=%d", userID)
+	return nil
+}
+
+func (mr *MFARepository) UseTOTPStep(ctx context.Context, userID int, step int64) (bool, error) {
+	const q = `
+        UPDATE user_totp
+        SET last_used_step = $2
+        WHERE user_id = $1 AND enabled_at IS NOT NULL AND last_used_step < $2
+    `
+	tag, err := mr.Connection.GetPool().Exec(ctx, q, userID, step)
+	if err != nil {
+		log.Printf("[repository:mfa][ERROR] UseTOTPStep failed: %v", err)
+		return false, fmt.Errorf("UseTOTPStep exec: %w", err)
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+func (mr *MFARepository) DisableTOTP(ctx context.Context, userID int) error {
+	log.Printf("[repository:mfa] DisableTOTP called: userID=%d", userID)
+
+	tx, err := mr.Connection.GetPool().Begin(ctx)
+	if err != nil {
+		log.Printf("[repository:mfa][ERROR] DisableTOTP begin tx failed: %v", err)
+		return fmt.Errorf("DisableTOTP begin tx: %w", err)
+	}
+	defer func() {
+		if err := tx.Rollback(ctx); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+			log.Printf("[repository:mfa][ERROR] DisableTOTP rollback failed: %v", err)
+		}
+	}()
+
+	if _, err := tx.Exec(ctx, `DELETE FROM user_recovery_codes WHERE user_id = $1`, userID); err != nil {
+		log.Printf("[repository:mfa][ERROR] DisableTOTP delete codes failed: %v", err)
+		return fmt.Errorf("DisableTOTP delete codes: %w", err)
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM user_totp WHERE user_id = $1`, userID); err != nil {
+		log.Printf("[repository:mfa][ERROR] DisableTOTP delete totp failed: %v", err)
+		return fmt.Errorf("DisableTOTP delete totp: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		log.Printf("[repository:mfa][ERROR] DisableTOTP commit failed: %v", err)
+		return fmt.Errorf("DisableTOTP commit: %w", err)
+	}
+
+	return nil
+}
+
+func (mr *MFARepository) UseRecoveryCode(ctx context.Context, userID int, codeHash string) (bool, error) {
+	const q = `
+        UPDATE user_recovery_codes
+        SET used_at = now()
+        WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL
+    `
+	tag, err := mr.Connection.GetPool().Exec(ctx, q, userID, codeHash)
+	if err != nil {
+		log.Printf("[repository:mfa][ERROR] UseRecoveryCode failed: %v", err)
+		return false, fmt.Errorf("UseRecoveryCode exec: %w", err)
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
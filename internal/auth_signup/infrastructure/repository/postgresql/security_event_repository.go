@@ -0,0 +1,161 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/1URose/marketplace/internal/auth_signup/domain/security_event/entity"
+	"github.com/1URose/marketplace/internal/common/db/postgresql"
+	"github.com/jackc/pgx/v5"
+	"log"
+	"strings"
+	"time"
+)
+
+type SecurityEventRepository struct {
+	Connection *postgresql.Client
+}
+
+func NewSecurityEventRepository(connection *postgresql.Client) *SecurityEventRepository {
+	log.Println("[repository:security_event] NewSecurityEventRepository initialized")
+	return &SecurityEventRepository{Connection: connection}
+}
+
+func (sr *SecurityEventRepository) Record(ctx context.Context, event *entity.SecurityEvent) error {
+	log.Printf("[repository:security_event] Record called: userID=%d type=%s sid=%s ip=%s",
+		event.UserID, event.Type, event.SessionID, event.IP,
+	)
+
+	const q = `
+        INSERT INTO security_events (user_id, type, session_id, ip, user_agent, created_at)
+        VALUES ($1, $2, $3, $4, $5, $6)
+        RETURNING id
+    `
+	err := sr.Connection.GetPool().
+		QueryRow(ctx, q, event.UserID, event.Type, event.SessionID, event.IP, event.UserAgent, event.CreatedAt).
+		Scan(&event.ID)
+	if err != nil {
+		log.Printf("[repository:security_event][ERROR] Record failed: %v", err)
+		return fmt.Errorf("Record security event scan: %w", err)
+	}
+
+	return nil
+}
+
+func (sr *SecurityEventRepository) List(ctx context.Context, filter *entity.Filter, offset, limit int) ([]*entity.SecurityEvent, error) {
+	log.Printf("[repository:security_event] List called: filter=%+v offset=%d limit=%d", filter, offset, limit)
+
+	where, args := buildSecurityEventsWhere(filter)
+	args = append(args, offset, limit)
+
+	q := `
+        SELECT id, user_id, type, session_id, ip, user_agent, created_at
+        FROM security_events` + where + fmt.Sprintf(`
+        ORDER BY created_at DESC, id DESC
+        OFFSET $%d LIMIT $%d
+    `, len(args)-1, len(args))
+
+	rows, err := sr.Connection.GetPool().Query(ctx, q, args...)
+	if err != nil {
+		log.Printf("[repository:security_event][ERROR] List query failed: %v", err)
+		return nil, fmt.Errorf("List security events query: %w", err)
+	}
+	defer rows.Close()
+
+	events := make([]*entity.SecurityEvent, 0)
+	for rows.Next() {
+		e := new(entity.SecurityEvent)
+		if err := rows.Scan(&e.ID, &e.UserID, &e.Type, &e.SessionID, &e.IP, &e.UserAgent, &e.CreatedAt); err != nil {
+			log.Printf("[repository:security_event][ERROR] List scan failed: %v", err)
+			return nil, fmt.Errorf("List security events scan: %w", err)
+		}
+		events = append(events, e)
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("[repository:security_event][ERROR] List rows failed: %v", err)
+		return nil, fmt.Errorf("List security events rows: %w", err)
+	}
+
+	log.Printf("[repository:security_event] List succeeded: events=%d", len(events))
+	return events, nil
+}
+
+func (sr *SecurityEventRepository) Count(ctx context.Context, filter *entity.Filter) (int, error) {
+	log.Printf("[repository:security_event] Count called: filter=%+v", filter)
+
+	where, args := buildSecurityEventsWhere(filter)
+
+	var count int
+	if err := sr.Connection.GetPool().QueryRow(ctx, `SELECT count(*) FROM security_events`+where, args...).Scan(&count); err != nil {
+		log.Printf("[repository:security_event][ERROR] Count scan failed: %v", err)
+		return 0, fmt.Errorf("Count security events scan: %w", err)
+	}
+
+	return count, nil
+}
+
+// DeleteBefore удаляет устаревшие события. Триггер таблицы запрещает удаление,
+// пока в транзакции не включён флаг security_events.purge.
+func (sr *SecurityEventRepository) DeleteBefore(ctx context.Context, before time.Time) (int64, error) {
+	log.Printf("[repository:security_event] DeleteBefore called: before=%s", before.Format(time.RFC3339))
+
+	tx, err := sr.Connection.GetPool().Begin(ctx)
+	if err != nil {
+		log.Printf("[repository:security_event][ERROR] DeleteBefore begin tx failed: %v", err)
+		return 0, fmt.Errorf("DeleteBefore begin tx: %w", err)
+	}
+	defer func() {
+		if err := tx.Rollback(ctx); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+			log.Printf("[repository:security_event][ERROR] DeleteBefore rollback failed: %v", err)
+		}
+	}()
+
+	if _, err := tx.Exec(ctx, `SET LOCAL security_events.purge = 'on'`); err != nil {
+		log.Printf("[repository:security_event][ERROR] DeleteBefore enable purge failed: %v", err)
+		return 0, fmt.Errorf("DeleteBefore enable purge: %w", err)
+	}
+
+	tag, err := tx.Exec(ctx, `DELETE FROM security_events WHERE created_at < $1`, before)
+	if err != nil {
+		log.Printf("[repository:security_event][ERROR] DeleteBefore exec failed: %v", err)
+		return 0, fmt.Errorf("DeleteBefore exec: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		log.Printf("[repository:security_event][ERROR] DeleteBefore commit failed: %v", err)
+		return 0, fmt.Errorf("DeleteBefore commit: %w", err)
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+func buildSecurityEventsWhere(filter *entity.Filter) (string, []interface{}) {
+	args := make([]interface{}, 0)
+	conditions := make([]string, 0)
+
+	if filter.UserID != nil {
+		args = append(args, *filter.UserID)
+		conditions = append(conditions, fmt.Sprintf("user_id = $%d", len(args)))
+	}
+	if len(filter.Types) > 0 {
+		args = append(args, filter.Types)
+		conditions = append(conditions, fmt.Sprintf("type = ANY($%d)", len(args)))
+	}
+	if filter.IP != "" {
+		args = append(args, filter.IP)
+		conditions = append(conditions, fmt.Sprintf("ip = $%d", len(args)))
+	}
+	if filter.From != nil {
+		args = append(args, *filter.From)
+		conditions = append(conditions, fmt.Sprintf("created_at >= $%d", len(args)))
+	}
+	if filter.To != nil {
+		args = append(args, *filter.To)
+		conditions = append(conditions, fmt.Sprintf("created_at < $%d", len(args)))
+	}
+
+	if len(conditions) == 0 {
+		return "", args
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
@@ -0,0 +1,63 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"github.com/1URose/marketplace/internal/common/db/redis"
+	"log"
+	"time"
+)
+
+type DenylistRepository struct {
+	Client *redis.Client
+}
+
+func NewDenylistRepository(client *redis.Client) *DenylistRepository {
+	log.Println("[redis:denylist] NewDenylistRepository initialized")
+
+	return &DenylistRepository{
+		Client: client,
+	}
+}
+
+func denylistKey(jti string) string {
+	return fmt.Sprintf("token_denylist:%s", jti)
+}
+
+func (dr *DenylistRepository) Add(ctx context.Context, jti string, expiresAt time.Time) error {
+	key := denylistKey(jti)
+
+	// запись нужна, только пока токен сам не истёк
+	ttl := time.Until(expiresAt)
+	if ttl <= 0 {
+		log.Printf("[redis:denylist] Add skipped: key=%q already expired", key)
+		return nil
+	}
+
+	log.Printf("[redis:denylist] Add called: key=%q ttl=%s", key, ttl)
+
+	if err := dr.Client.Connection.Set(ctx, key, 1, ttl).Err(); err != nil {
+
+		log.Printf("[redis:denylist][ERROR] SET command failed for key=%q: %v", key, err)
+
+		return err
+	}
+
+	log.Printf("[redis:denylist] Add succeeded for key=%q", key)
+
+	return nil
+}
+
+func (dr *DenylistRepository) Contains(ctx context.Context, jti string) (bool, error) {
+	key := denylistKey(jti)
+
+	n, err := dr.Client.Connection.Exists(ctx, key).Result()
+	if err != nil {
+
+		log.Printf("[redis:denylist][ERROR] EXISTS command failed for key=%q: %v", key, err)
+
+		return false, err
+	}
+
+	return n > 0, nil
+}
@@ -0,0 +1,114 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/1URose/marketplace/internal/common/db/redis"
+	goredis "github.com/go-redis/redis/v8"
+	"log"
+	"strconv"
+	"time"
+)
+
+type LoginAttemptRepository struct {
+	Client *redis.Client
+}
+
+func NewLoginAttemptRepository(client *redis.Client) *LoginAttemptRepository {
+	log.Println("[redis:login_attempts] NewLoginAttemptRepository initialized")
+
+	return &LoginAttemptRepository{
+		Client: client,
+	}
+}
+
+func loginFailuresKey(key string) string {
+	return fmt.Sprintf("login_failures:%s", key)
+}
+
+func loginBlockedKey(key string) string {
+	return fmt.Sprintf("login_blocked:%s", key)
+}
+
+func (lr *LoginAttemptRepository) RegisterFailure(ctx context.Context, key string, window time.Duration) (int, error) {
+	counter := loginFailuresKey(key)
+
+	// окно отсчитывается от первой неудачи и не продлевается следующими: счётчик создаётся сразу с TTL
+	// через SET NX, а INCR идёт в той же транзакции, поэтому ключ не может остаться без срока жизни
+	var incr *goredis.IntCmd
+	_, err := lr.Client.Connection.TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+		pipe.SetNX(ctx, counter, 0, window)
+		incr = pipe.Incr(ctx, counter)
+		return nil
+	})
+	if err != nil {
+
+		log.Printf("[redis:login_attempts][ERROR] SET NX/INCR transaction failed for key=%q: %v", counter, err)
+
+		return 0, err
+	}
+	failures := incr.Val()
+
+	log.Printf("[redis:login_attempts] RegisterFailure: key=%q failures=%d", counter, failures)
+
+	return int(failures), nil
+}
+
+func (lr *LoginAttemptRepository) Block(ctx context.Context, key string, until time.Time) error {
+	blocked := loginBlockedKey(key)
+
+	ttl := time.Until(until)
+	if ttl <= 0 {
+		return nil
+	}
+
+	log.Printf("[redis:login_attempts] Block called: key=%q ttl=%s", blocked, ttl)
+
+	if err := lr.Client.Connection.Set(ctx, blocked, until.Unix(), ttl).Err(); err != nil {
+
+		log.Printf("[redis:login_attempts][ERROR] SET command failed for key=%q: %v", blocked, err)
+
+		return err
+	}
+
+	return nil
+}
+
+func (lr *LoginAttemptRepository) BlockedUntil(ctx context.Context, key string) (time.Time, error) {
+	blocked := loginBlockedKey(key)
+
+	val, err := lr.Client.Connection.Get(ctx, blocked).Result()
+	if errors.Is(err, goredis.Nil) {
+		return time.Time{}, nil
+	}
+	if err != nil {
+
+		log.Printf("[redis:login_attempts][ERROR] GET command failed for key=%q: %v", blocked, err)
+
+		return time.Time{}, err
+	}
+
+	unix, err := strconv.ParseInt(val, 10, 64)
+	if err != nil {
+
+		log.Printf("[redis:login_attempts][ERROR] parse blocked until for key=%q: %v", blocked, err)
+
+		return time.Time{}, err
+	}
+
+	return time.Unix(unix, 0), nil
+}
+
+func (lr *LoginAttemptRepository) Reset(ctx context.Context, key string) error {
+	log.Printf("[redis:login_attempts] Reset called: key=%q", key)
+
+	if err := lr.Client.Connection.Del(ctx, loginFailuresKey(key), loginBlockedKey(key)).Err(); err != nil {
+
+		log.Printf("[redis:login_attempts][ERROR] DEL command failed for key=%q: %v", key, err)
+
+		return err
+	}
+
+	return nil
+}
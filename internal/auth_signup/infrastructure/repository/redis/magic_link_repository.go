@@ -0,0 +1,62 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"github.com/1URose/marketplace/internal/common/db/redis"
+	"log"
+	"time"
+)
+
+type MagicLinkRepository struct {
+	Client *redis.Client
+}
+
+func NewMagicLinkRepository(client *redis.Client) *MagicLinkRepository {
+	log.Println("[redis:magic_link] NewMagicLinkRepository initialized")
+
+	return &MagicLinkRepository{
+		Client: client,
+	}
+}
+
+func magicLinkSendKey(userID int) string {
+	return fmt.Sprintf("magic_link_send:%d", userID)
+}
+
+func magicLinkUsedKey(jti string) string {
+	return fmt.Sprintf("magic_link_used:%s", jti)
+}
+
+func (mr *MagicLinkRepository) AcquireSendWindow(ctx context.Context, userID int, cooldown time.Duration) (bool, error) {
+	if cooldown <= 0 {
+		return true, nil
+	}
+
+	acquired, err := mr.Client.Connection.SetNX(ctx, magicLinkSendKey(userID), time.Now().Unix(), cooldown).Result()
+	if err != nil {
+
+		log.Printf("[redis:magic_link][ERROR] SETNX command failed: %v", err)
+
+		return false, err
+	}
+
+	return acquired, nil
+}
+
+func (mr *MagicLinkRepository) MarkUsed(ctx context.Context, jti string, ttl time.Duration) (bool, error) {
+	// ключ живёт, пока не истечёт сама ссылка; после этого её отвергнет проверка срока действия
+	if ttl <= 0 {
+		ttl = time.Second
+	}
+
+	first, err := mr.Client.Connection.SetNX(ctx, magicLinkUsedKey(jti), time.Now().Unix(), ttl).Result()
+	if err != nil {
+
+		log.Printf("[redis:magic_link][ERROR] SETNX command failed: %v", err)
+
+		return false, err
+	}
+
+	return first, nil
+}
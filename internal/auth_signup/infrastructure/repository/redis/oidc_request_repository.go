@@ -0,0 +1,79 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/1URose/marketplace/internal/auth_signup/domain/oidc/entity"
+	"github.com/1URose/marketplace/internal/common/db/redis"
+	goredis "github.com/go-redis/redis/v8"
+	"log"
+	"time"
+)
+
+type OIDCRequestRepository struct {
+	Client *redis.Client
+}
+
+func NewOIDCRequestRepository(client *redis.Client) *OIDCRequestRepository {
+	log.Println("[redis:oidc] NewOIDCRequestRepository initialized")
+
+	return &OIDCRequestRepository{
+		Client: client,
+	}
+}
+
+func oidcStateKey(state string) string {
+	return fmt.Sprintf("oidc_state:%s", state)
+}
+
+func (or *OIDCRequestRepository) Save(ctx context.Context, req *entity.AuthRequest, ttl time.Duration) error {
+	key := oidcStateKey(req.State)
+
+	log.Printf("[redis:oidc] Save called: provider=%s ttl=%s", req.Provider, ttl)
+
+	data, err := json.Marshal(req)
+
+	if err != nil {
+
+		log.Printf("[redis:oidc][ERROR] marshal auth request failed: %v", err)
+
+		return err
+	}
+
+	if err := or.Client.Connection.Set(ctx, key, data, ttl).Err(); err != nil {
+
+		log.Printf("[redis:oidc][ERROR] SET command failed: %v", err)
+
+		return err
+	}
+
+	return nil
+}
+
+func (or *OIDCRequestRepository) Take(ctx context.Context, state string) (*entity.AuthRequest, error) {
+	key := oidcStateKey(state)
+
+	data, err := or.Client.Connection.GetDel(ctx, key).Bytes()
+	if errors.Is(err, goredis.Nil) {
+		log.Printf("[redis:oidc] Take: state not found")
+		return nil, nil
+	}
+	if err != nil {
+
+		log.Printf("[redis:oidc][ERROR] GETDEL command failed: %v", err)
+
+		return nil, err
+	}
+
+	var req entity.AuthRequest
+	if err := json.Unmarshal(data, &req); err != nil {
+
+		log.Printf("[redis:oidc][ERROR] unmarshal auth request failed: %v", err)
+
+		return nil, err
+	}
+
+	return &req, nil
+}
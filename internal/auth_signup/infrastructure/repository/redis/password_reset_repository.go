@@ -0,0 +1,117 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/1URose/marketplace/internal/common/db/redis"
+	goredis "github.com/go-redis/redis/v8"
+	"log"
+	"time"
+)
+
+type PasswordResetRepository struct {
+	Client *redis.Client
+}
+
+func NewPasswordResetRepository(client *redis.Client) *PasswordResetRepository {
+	log.Println("[redis:password_reset] NewPasswordResetRepository initialized")
+
+	return &PasswordResetRepository{
+		Client: client,
+	}
+}
+
+func resetTokenKey(tokenHash string) string {
+	return fmt.Sprintf("pwreset:%s", tokenHash)
+}
+
+func resetUserKey(userID int) string {
+	return fmt.Sprintf("pwreset_user:%d", userID)
+}
+
+func resetSendKey(userID int) string {
+	return fmt.Sprintf("pwreset_send:%d", userID)
+}
+
+func (pr *PasswordResetRepository) Save(ctx context.Context, userID int, tokenHash string, ttl time.Duration) error {
+	log.Printf("[redis:password_reset] Save called: userID=%d ttl=%s", userID, ttl)
+
+	previous, err := pr.Client.Connection.Get(ctx, resetUserKey(userID)).Result()
+	if err != nil && !errors.Is(err, goredis.Nil) {
+
+		log.Printf("[redis:password_reset][ERROR] GET command failed: %v", err)
+
+		return err
+	}
+
+	// прежняя ссылка перестаёт работать, как только выдана новая
+	_, err = pr.Client.Connection.TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+		if previous != "" {
+			pipe.Del(ctx, resetTokenKey(previous))
+		}
+		pipe.Set(ctx, resetTokenKey(tokenHash), userID, ttl)
+		pipe.Set(ctx, resetUserKey(userID), tokenHash, ttl)
+		return nil
+	})
+	if err != nil {
+
+		log.Printf("[redis:password_reset][ERROR] save token transaction failed: %v", err)
+
+		return err
+	}
+
+	return nil
+}
+
+func (pr *PasswordResetRepository) Peek(ctx context.Context, tokenHash string) (int, error) {
+	userID, err := pr.Client.Connection.Get(ctx, resetTokenKey(tokenHash)).Int()
+	if errors.Is(err, goredis.Nil) {
+		log.Printf("[redis:password_reset] Peek: token not found")
+		return 0, nil
+	}
+	if err != nil {
+
+		log.Printf("[redis:password_reset][ERROR] GET command failed: %v", err)
+
+		return 0, err
+	}
+
+	return userID, nil
+}
+
+func (pr *PasswordResetRepository) Take(ctx context.Context, tokenHash string) (int, error) {
+	userID, err := pr.Client.Connection.GetDel(ctx, resetTokenKey(tokenHash)).Int()
+	if errors.Is(err, goredis.Nil) {
+		log.Printf("[redis:password_reset] Take: token not found")
+		return 0, nil
+	}
+	if err != nil {
+
+		log.Printf("[redis:password_reset][ERROR] GETDEL command failed: %v", err)
+
+		return 0, err
+	}
+
+	if err := pr.Client.Connection.Del(ctx, resetUserKey(userID)).Err(); err != nil {
+		log.Printf("[redis:password_reset][ERROR] DEL command failed for userID=%d: %v", userID, err)
+	}
+
+	return userID, nil
+}
+
+func (pr *PasswordResetRepository) AcquireSendWindow(ctx context.Context, userID int, cooldown time.Duration) (bool, error) {
+	if cooldown <= 0 {
+		return true, nil
+	}
+
+	acquired, err := pr.Client.Connection.SetNX(ctx, resetSendKey(userID), time.Now().Unix(), cooldown).Result()
+	if err != nil {
+
+		log.Printf("[redis:password_reset][ERROR] SETNX command failed: %v", err)
+
+		return false, err
+	}
+
+	return acquired, nil
+}
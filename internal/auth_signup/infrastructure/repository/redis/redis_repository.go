@@ -3,58 +3,259 @@ package redis
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/1URose/marketplace/internal/auth_signup/domain/redis/entity"
 	"github.com/1URose/marketplace/internal/common/db/redis"
+	goredis "github.com/go-redis/redis/v8"
 	"log"
+	"strconv"
 	"time"
 )
 
 type Repository struct {
-	Client *redis.Client
+	Client     *redis.Client
+	sessionTTL time.Duration
 }
 
-func NewRedisRepository(client *redis.Client) *Repository {
-	log.Println("[redis:repo] NewRedisRepository initialized")
+func NewRedisRepository(client *redis.Client, sessionTTL time.Duration) *Repository {
+	log.Printf("[redis:repo] NewRedisRepository initialized: sessionTTL=%s", sessionTTL)
 
 	return &Repository{
-		Client: client,
+		Client:     client,
+		sessionTTL: sessionTTL,
 	}
 }
 
-func (ur *Repository) Set(ctx context.Context, session *entity.Redis) error {
-	key := fmt.Sprintf("refresh:%s", session.Email)
+func sessionKey(sessionID string) string {
+	return fmt.Sprintf("session:%s", sessionID)
+}
+
+// userSessionsKey — индекс сессий пользователя: sorted set с временем последнего использования в score.
+func userSessionsKey(userID int) string {
+	return fmt.Sprintf("sessions:%d", userID)
+}
+
+func (ur *Repository) Set(ctx context.Context, session *entity.Session, maxSessions int) ([]string, error) {
+	key := sessionKey(session.ID)
+	index := userSessionsKey(session.UserID)
+
+	log.Printf("[redis:repo] Set called: key=%q userID=%d ttl=%s", key, session.UserID, ur.sessionTTL)
+
+	data, err := json.Marshal(session)
+
+	if err != nil {
+
+		log.Printf("[redis:repo][ERROR] marshal session failed: %v", err)
+
+		return nil, err
+	}
+
+	_, err = ur.Client.Connection.TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+		pipe.Set(ctx, key, data, ur.sessionTTL)
+		pipe.ZAdd(ctx, index, &goredis.Z{Score: float64(session.LastUsedAt.Unix()), Member: session.ID})
+		pipe.Expire(ctx, index, ur.sessionTTL)
+		return nil
+	})
+	if err != nil {
+
+		log.Printf("[redis:repo][ERROR] SET session failed for key=%q: %v", key, err)
+
+		return nil, err
+	}
+
+	evicted, err := ur.evictOverLimit(ctx, session.UserID, maxSessions)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("[redis:repo] Set succeeded for key=%q", key)
+
+	return evicted, nil
+}
+
+// Replace атомарно сохраняет обновлённую сессию, только если её refresh-токен всё ещё равен expectedRefreshToken.
+// Возвращает false, если сессию успел изменить параллельный запрос или она закрыта.
+func (ur *Repository) Replace(ctx context.Context, session *entity.Session, expectedRefreshToken string) (bool, error) {
+	key := sessionKey(session.ID)
+
+	log.Printf("[redis:repo] Replace called: key=%q", key)
+
+	data, err := json.Marshal(session)
+	if err != nil {
 
-	log.Printf("[redis:repo] Set called: key=%q ttl=24h", key)
+		log.Printf("[redis:repo][ERROR] marshal session failed: %v", err)
 
-	data, err := json.Marshal(session.RefreshToken)
+		return false, err
+	}
+
+	replaced := false
+	err = ur.Client.Connection.Watch(ctx, func(tx *goredis.Tx) error {
+		raw, err := tx.Get(ctx, key).Result()
+		if errors.Is(err, goredis.Nil) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		var stored entity.Session
+		if err := json.Unmarshal([]byte(raw), &stored); err != nil {
+			return err
+		}
+		if stored.RefreshToken != expectedRefreshToken {
+			return nil
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+			pipe.Set(ctx, key, data, ur.sessionTTL)
+			pipe.ZAdd(ctx, userSessionsKey(session.UserID), &goredis.Z{Score: float64(session.LastUsedAt.Unix()), Member: session.ID})
+			pipe.Expire(ctx, userSessionsKey(session.UserID), ur.sessionTTL)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		replaced = true
+		return nil
+	}, key)
+
+	if errors.Is(err, goredis.TxFailedErr) {
 
+		log.Printf("[redis:repo] Replace: session %q changed concurrently", key)
+
+		return false, nil
+	}
 	if err != nil {
 
-		log.Printf("[redis:repo][ERROR] marshal token failed: %v", err)
+		log.Printf("[redis:repo][ERROR] Replace failed for key=%q: %v", key, err)
+
+		return false, err
+	}
+
+	log.Printf("[redis:repo] Replace finished for key=%q: replaced=%t", key, replaced)
+
+	return replaced, nil
+}
+
+// evictOverLimit закрывает самые давно неиспользуемые сессии, пока их не останется maxSessions,
+// и возвращает идентификаторы закрытых.
+func (ur *Repository) evictOverLimit(ctx context.Context, userID, maxSessions int) ([]string, error) {
+	index := userSessionsKey(userID)
+
+	if err := ur.prune(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	count, err := ur.Client.Connection.ZCard(ctx, index).Result()
+	if err != nil {
+
+		log.Printf("[redis:repo][ERROR] ZCARD command failed for key=%q: %v", index, err)
+
+		return nil, err
+	}
+	if count <= int64(maxSessions) {
+		return nil, nil
+	}
+
+	victims, err := ur.Client.Connection.ZRange(ctx, index, 0, count-int64(maxSessions)-1).Result()
+	if err != nil {
+
+		log.Printf("[redis:repo][ERROR] ZRANGE command failed for key=%q: %v", index, err)
+
+		return nil, err
+	}
+
+	log.Printf("[redis:repo] evicting sessions over limit: userID=%d limit=%d evicted=%d", userID, maxSessions, len(victims))
+
+	if err := ur.deleteSessions(ctx, userID, victims); err != nil {
+		return nil, err
+	}
+	return victims, nil
+}
+
+// prune убирает из индекса сессии, ключи которых уже истекли.
+func (ur *Repository) prune(ctx context.Context, userID int) error {
+	index := userSessionsKey(userID)
+
+	ids, err := ur.Client.Connection.ZRange(ctx, index, 0, -1).Result()
+	if err != nil {
+
+		log.Printf("[redis:repo][ERROR] ZRANGE command failed for key=%q: %v", index, err)
 
 		return err
 	}
 
-	if err = ur.Client.Connection.Set(ctx, key, data, 24*time.Hour).Err(); err != nil {
+	stale := make([]interface{}, 0)
+	for _, id := range ids {
+		exists, err := ur.Client.Connection.Exists(ctx, sessionKey(id)).Result()
+		if err != nil {
 
-		log.Printf("[redis:repo][ERROR] SET command failed for key=%q: %v", key, err)
+			log.Printf("[redis:repo][ERROR] EXISTS command failed for session=%q: %v", id, err)
+
+			return err
+		}
+		if exists == 0 {
+			stale = append(stale, id)
+		}
+	}
+	if len(stale) == 0 {
+		return nil
+	}
+
+	if err := ur.Client.Connection.ZRem(ctx, index, stale...).Err(); err != nil {
+
+		log.Printf("[redis:repo][ERROR] ZREM command failed for key=%q: %v", index, err)
 
 		return err
 	}
 
-	log.Printf("[redis:repo] Set succeeded for key=%q", key)
+	return nil
+}
+
+func (ur *Repository) deleteSessions(ctx context.Context, userID int, sessionIDs []string) error {
+	if len(sessionIDs) == 0 {
+		return nil
+	}
+
+	index := userSessionsKey(userID)
+	keys := make([]string, len(sessionIDs))
+	members := make([]interface{}, len(sessionIDs))
+	for i, id := range sessionIDs {
+		keys[i] = sessionKey(id)
+		members[i] = id
+	}
+
+	_, err := ur.Client.Connection.TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+		pipe.Del(ctx, keys...)
+		pipe.ZRem(ctx, index, members...)
+		return nil
+	})
+	if err != nil {
+
+		log.Printf("[redis:repo][ERROR] delete sessions failed for userID=%d: %v", userID, err)
+
+		return err
+	}
 
 	return nil
 }
 
-func (ur *Repository) Get(ctx context.Context, email string) (*entity.Redis, error) {
-	key := fmt.Sprintf("refresh:%s", email)
+// Get возвращает nil, если сессия не найдена или истекла.
+func (ur *Repository) Get(ctx context.Context, sessionID string) (*entity.Session, error) {
+	key := sessionKey(sessionID)
 
 	log.Printf("[redis:repo] Get called: key=%q", key)
 
 	data, err := ur.Client.Connection.Get(ctx, key).Result()
 
+	if errors.Is(err, goredis.Nil) {
+
+		log.Printf("[redis:repo] Get: session not found for key=%q", key)
+
+		return nil, nil
+	}
+
 	if err != nil {
 
 		log.Printf("[redis:repo][ERROR] GET command failed for key=%q: %v", key, err)
@@ -62,7 +263,7 @@ func (ur *Repository) Get(ctx context.Context, email string) (*entity.Redis, err
 		return nil, err
 	}
 
-	var session entity.Redis
+	var session entity.Session
 
 	if err = json.Unmarshal([]byte(data), &session); err != nil {
 
@@ -71,7 +272,151 @@ func (ur *Repository) Get(ctx context.Context, email string) (*entity.Redis, err
 		return nil, err
 	}
 
-	log.Printf("[redis:repo] Get succeeded for key=%q: token=%q", key, session.RefreshToken)
+	log.Printf("[redis:repo] Get succeeded for key=%q: userID=%d", key, session.UserID)
 
 	return &session, nil
 }
+
+// List возвращает активные сессии пользователя, начиная с последней использованной.
+func (ur *Repository) List(ctx context.Context, userID int) ([]*entity.Session, error) {
+	log.Printf("[redis:repo] List called: userID=%d", userID)
+
+	if err := ur.prune(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	ids, err := ur.Client.Connection.ZRevRange(ctx, userSessionsKey(userID), 0, -1).Result()
+	if err != nil {
+
+		log.Printf("[redis:repo][ERROR] ZREVRANGE command failed for userID=%d: %v", userID, err)
+
+		return nil, err
+	}
+
+	sessions := make([]*entity.Session, 0, len(ids))
+	for _, id := range ids {
+		session, err := ur.Get(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		// сессия могла истечь между prune и чтением
+		if session != nil {
+			sessions = append(sessions, session)
+		}
+	}
+
+	log.Printf("[redis:repo] List succeeded for userID=%d: sessions=%d", userID, len(sessions))
+
+	return sessions, nil
+}
+
+func (ur *Repository) Delete(ctx context.Context, userID int, sessionID string) error {
+	log.Printf("[redis:repo] Delete called: userID=%d session=%q", userID, sessionID)
+
+	if err := ur.deleteSessions(ctx, userID, []string{sessionID}); err != nil {
+		return err
+	}
+
+	log.Printf("[redis:repo] Delete succeeded for session=%q", sessionID)
+
+	return nil
+}
+
+// deleteAllAttempts — сколько раз DeleteAll повторяет удаление, если параллельный вход изменил индекс сессий.
+const deleteAllAttempts = 5
+
+// DeleteAll атомарно удаляет все сессии пользователя и возвращает идентификаторы удалённых.
+// Индекс сессий отслеживается через WATCH: если параллельный вход успел добавить сессию между чтением
+// индекса и удалением, транзакция не применяется и повторяется, поэтому возвращаются ровно удалённые сессии.
+func (ur *Repository) DeleteAll(ctx context.Context, userID int) ([]string, error) {
+	index := userSessionsKey(userID)
+
+	log.Printf("[redis:repo] DeleteAll called: userID=%d", userID)
+
+	for attempt := 1; attempt <= deleteAllAttempts; attempt++ {
+		var ids []string
+		err := ur.Client.Connection.Watch(ctx, func(tx *goredis.Tx) error {
+			var err error
+			ids, err = tx.ZRange(ctx, index, 0, -1).Result()
+			if err != nil {
+				return err
+			}
+
+			keys := make([]string, 0, len(ids)+1)
+			for _, id := range ids {
+				keys = append(keys, sessionKey(id))
+			}
+			keys = append(keys, index)
+
+			_, err = tx.TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+				pipe.Del(ctx, keys...)
+				return nil
+			})
+			return err
+		}, index)
+
+		if errors.Is(err, goredis.TxFailedErr) {
+
+			log.Printf("[redis:repo] DeleteAll: sessions of userID=%d changed concurrently, attempt=%d", userID, attempt)
+
+			continue
+		}
+		if err != nil {
+
+			log.Printf("[redis:repo][ERROR] DeleteAll failed for userID=%d: %v", userID, err)
+
+			return nil, err
+		}
+
+		log.Printf("[redis:repo] DeleteAll succeeded for userID=%d: sessions=%d", userID, len(ids))
+
+		return ids, nil
+	}
+
+	return nil, fmt.Errorf("delete sessions of userID=%d: index changed concurrently %d times", userID, deleteAllAttempts)
+}
+
+// SetTokensValidAfter запоминает момент, до которого (включительно) выданные пользователю токены недействительны.
+// Ключ живёт не дольше самих токенов: после ttl все токены, выданные раньше, истекли сами.
+func (ur *Repository) SetTokensValidAfter(ctx context.Context, userID int, validAfter time.Time, ttl time.Duration) error {
+	key := fmt.Sprintf("tokens_valid_after:%d", userID)
+
+	log.Printf("[redis:repo] SetTokensValidAfter called: key=%q validAfter=%s ttl=%s", key, validAfter.Format(time.RFC3339), ttl)
+
+	if err := ur.Client.Connection.Set(ctx, key, validAfter.Unix(), ttl).Err(); err != nil {
+
+		log.Printf("[redis:repo][ERROR] SET command failed for key=%q: %v", key, err)
+
+		return err
+	}
+
+	log.Printf("[redis:repo] SetTokensValidAfter succeeded for key=%q", key)
+
+	return nil
+}
+
+// GetTokensValidAfter возвращает нулевое время, если пользователь не отзывал свои токены.
+func (ur *Repository) GetTokensValidAfter(ctx context.Context, userID int) (time.Time, error) {
+	key := fmt.Sprintf("tokens_valid_after:%d", userID)
+
+	data, err := ur.Client.Connection.Get(ctx, key).Result()
+	if errors.Is(err, goredis.Nil) {
+		return time.Time{}, nil
+	}
+	if err != nil {
+
+		log.Printf("[redis:repo][ERROR] GET command failed for key=%q: %v", key, err)
+
+		return time.Time{}, err
+	}
+
+	sec, err := strconv.ParseInt(data, 10, 64)
+	if err != nil {
+
+		log.Printf("[redis:repo][ERROR] parse value failed for key=%q: %v", key, err)
+
+		return time.Time{}, err
+	}
+
+	return time.Unix(sec, 0), nil
+}
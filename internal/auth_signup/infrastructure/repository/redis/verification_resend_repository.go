@@ -0,0 +1,56 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"github.com/1URose/marketplace/internal/common/db/redis"
+	"log"
+	"time"
+)
+
+type VerificationResendRepository struct {
+	Client *redis.Client
+}
+
+func NewVerificationResendRepository(client *redis.Client) *VerificationResendRepository {
+	log.Println("[redis:verification_resend] NewVerificationResendRepository initialized")
+
+	return &VerificationResendRepository{
+		Client: client,
+	}
+}
+
+func verificationResendKey(userID int) string {
+	return fmt.Sprintf("verify_resend:%d", userID)
+}
+
+func (vr *VerificationResendRepository) Acquire(ctx context.Context, userID int, cooldown time.Duration) (time.Duration, error) {
+	key := verificationResendKey(userID)
+
+	acquired, err := vr.Client.Connection.SetNX(ctx, key, time.Now().Unix(), cooldown).Result()
+	if err != nil {
+
+		log.Printf("[redis:verification_resend][ERROR] SETNX command failed for key=%q: %v", key, err)
+
+		return 0, err
+	}
+	if acquired {
+		return 0, nil
+	}
+
+	ttl, err := vr.Client.Connection.TTL(ctx, key).Result()
+	if err != nil {
+
+		log.Printf("[redis:verification_resend][ERROR] TTL command failed for key=%q: %v", key, err)
+
+		return 0, err
+	}
+	// ключ мог истечь между SETNX и TTL: тогда ждать почти не нужно
+	if ttl <= 0 {
+		ttl = time.Second
+	}
+
+	log.Printf("[redis:verification_resend] Acquire: key=%q busy for %s", key, ttl)
+
+	return ttl, nil
+}
@@ -0,0 +1,98 @@
+package auth
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/1URose/marketplace/internal/auth_signup/transport/rest/auth/dto"
+	"github.com/1URose/marketplace/internal/auth_signup/use_cases"
+	"github.com/1URose/marketplace/internal/common/apperror"
+	"github.com/1URose/marketplace/internal/common/transport/rest/bind"
+	"github.com/gin-gonic/gin"
+)
+
+// errAccountNotFound — учётная запись из действующего токена уже удалена.
+var errAccountNotFound = apperror.Unauthorized("auth.user_not_found")
+
+// ChangePassword godoc
+// @Summary      Смена пароля
+// @Description  Меняет пароль текущего пользователя после проверки текущего пароля. Все сессии, кроме текущей, закрываются
+// @Tags         account
+// @Accept       json
+// @Security     BearerAuth
+// @Param        request body dto.ChangePasswordRequest true "Текущий и новый пароль"
+// @Success      204 "Пароль изменён"
+// @Failure      400 {object} dto.ErrorResponse "Неверный запрос или новый пароль нарушает политику (violations)"
+// @Failure      401 {object} dto.ErrorResponse "Не авторизован"
+// @Failure      403 {object} dto.ErrorResponse "Неверный текущий пароль"
+// @Failure      429 {object} dto.ErrorResponse "Слишком много неудачных попыток, повторить после Retry-After секунд"
+// @Failure      500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
+// @Router       /me/password [put]
+func (ah *Handler) ChangePassword(ctx *gin.Context) {
+	userId := ctx.GetInt("userId")
+
+	log.Printf("[handler:auth] ChangePassword called: userID=%d", userId)
+
+	var req dto.ChangePasswordRequest
+	if err := bind.JSON(ctx, &req); err != nil {
+		log.Printf("[handler:auth][ERROR] bind body: %v", err)
+		ctx.Error(err)
+		return
+	}
+
+	err := ah.AuthService.ChangePassword(
+		ctx, userId, ctx.GetString("sessionId"), req.CurrentPassword, req.NewPassword, ctx.ClientIP(), ctx.Request.UserAgent(),
+	)
+	if errors.Is(err, use_cases.ErrUserNotFound) {
+		err = errAccountNotFound
+	}
+	if err != nil {
+		log.Printf("[handler:auth][ERROR] ChangePassword failed: %v", err)
+		ctx.Error(passwordError(err, "new_password"))
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// ChangeEmail godoc
+// @Summary      Смена email
+// @Description  Отправляет на новый адрес ссылку подтверждения; учётная запись переключается на него после POST /auth/verify-email с токеном из письма, а на прежний адрес приходит уведомление. Сессии при смене адреса сохраняются
+// @Tags         account
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body dto.ChangeEmailRequest true "Новый email и текущий пароль"
+// @Success      202 {object} dto.ChangeEmailResponse "Письмо подтверждения отправлено"
+// @Failure      400 {object} dto.ErrorResponse "Неверный запрос или адрес совпадает с текущим"
+// @Failure      401 {object} dto.ErrorResponse "Не авторизован"
+// @Failure      403 {object} dto.ErrorResponse "Неверный текущий пароль"
+// @Failure      409 {object} dto.ErrorResponse "Адрес занят"
+// @Failure      429 {object} dto.ErrorResponse "Слишком много неудачных попыток или письмо недавно отправлялось, повторить после Retry-After секунд"
+// @Failure      500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
+// @Router       /me/email [put]
+func (ah *Handler) ChangeEmail(ctx *gin.Context) {
+	userId := ctx.GetInt("userId")
+
+	log.Printf("[handler:auth] ChangeEmail called: userID=%d", userId)
+
+	var req dto.ChangeEmailRequest
+	if err := bind.JSON(ctx, &req); err != nil {
+		log.Printf("[handler:auth][ERROR] bind body: %v", err)
+		ctx.Error(err)
+		return
+	}
+
+	err := ah.EmailVerification.RequestEmailChange(ctx, userId, req.NewEmail, req.CurrentPassword, ctx.ClientIP(), ctx.Request.UserAgent())
+	if errors.Is(err, use_cases.ErrUserNotFound) {
+		err = errAccountNotFound
+	}
+	if err != nil {
+		log.Printf("[handler:auth][ERROR] ChangeEmail failed for userID=%d: %v", userId, err)
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusAccepted, dto.ChangeEmailResponse{PendingEmail: req.NewEmail})
+}
@@ -0,0 +1,111 @@
+package auth
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/1URose/marketplace/internal/auth_signup/transport/rest/auth/dto"
+	"github.com/1URose/marketplace/internal/common/apperror"
+	"github.com/1URose/marketplace/internal/common/transport/rest/bind"
+	"github.com/gin-gonic/gin"
+)
+
+var errInvalidAPIKeyID = apperror.Validation("api_key.invalid_id")
+
+// CreateAPIKey godoc
+// @Summary      Выпустить API-ключ
+// @Description  Создаёт ключ для интеграции с выбранными правами (ads:read, ads:write, reports:write) и необязательным сроком действия. Ключ передаётся в заголовке X-API-Key или Authorization: Bearer <key> и показывается только в этом ответе
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        Authorization header string                  true "JWT Access token"
+// @Param        request       body   dto.CreateAPIKeyRequest true "Название, права и срок действия"
+// @Success      201 {object} dto.CreateAPIKeyResponse "Ключ создан"
+// @Failure      400 {object} dto.ErrorResponse        "Неверный запрос"
+// @Failure      401 {object} dto.ErrorResponse        "Неавторизован"
+// @Failure      500 {object} dto.ErrorResponse        "Внутренняя ошибка сервера"
+// @Router       /auth/api-keys [post]
+func (ah *Handler) CreateAPIKey(ctx *gin.Context) {
+	log.Printf("[handler:auth] CreateAPIKey called")
+
+	var req dto.CreateAPIKeyRequest
+	if err := bind.JSON(ctx, &req); err != nil {
+		log.Printf("[handler:auth][ERROR] bind body: %v", err)
+		ctx.Error(err)
+		return
+	}
+
+	userId := ctx.GetInt("userId")
+
+	key, raw, err := ah.APIKeyService.Create(ctx, userId, req.Name, req.Scopes, req.ExpiresAt)
+	if err != nil {
+		log.Printf("[handler:auth][ERROR] CreateAPIKey failed: %v", err)
+		ctx.Error(err)
+		return
+	}
+
+	log.Printf("[handler:auth] API key %d created for userID=%d", key.ID, userId)
+	ctx.JSON(http.StatusCreated, dto.CreateAPIKeyResponse{
+		APIKeyResponse: dto.NewAPIKeyResponse(key),
+		Key:            raw,
+	})
+}
+
+// ListAPIKeys godoc
+// @Summary      Мои API-ключи
+// @Description  Список ключей с правами, сроком действия и временем последнего использования; сами ключи не возвращаются
+// @Tags         auth
+// @Produce      json
+// @Param        Authorization header string true "JWT Access token"
+// @Success      200 {array}  dto.APIKeyResponse "Ключи пользователя"
+// @Failure      401 {object} dto.ErrorResponse  "Неавторизован"
+// @Failure      500 {object} dto.ErrorResponse  "Внутренняя ошибка сервера"
+// @Router       /auth/api-keys [get]
+func (ah *Handler) ListAPIKeys(ctx *gin.Context) {
+	log.Printf("[handler:auth] ListAPIKeys called")
+
+	userId := ctx.GetInt("userId")
+
+	keys, err := ah.APIKeyService.List(ctx, userId)
+	if err != nil {
+		log.Printf("[handler:auth][ERROR] ListAPIKeys failed: %v", err)
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.NewAPIKeysResponse(keys))
+}
+
+// RevokeAPIKey godoc
+// @Summary      Отозвать API-ключ
+// @Description  Ключ сразу перестаёт приниматься
+// @Tags         auth
+// @Produce      json
+// @Param        Authorization header string true "JWT Access token"
+// @Param        id            path   int    true "ID ключа"
+// @Success      204 "Ключ отозван"
+// @Failure      400 {object} dto.ErrorResponse "Неверный ID"
+// @Failure      401 {object} dto.ErrorResponse "Неавторизован"
+// @Failure      404 {object} dto.ErrorResponse "Ключ не найден"
+// @Failure      500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
+// @Router       /auth/api-keys/{id} [delete]
+func (ah *Handler) RevokeAPIKey(ctx *gin.Context) {
+	log.Printf("[handler:auth] RevokeAPIKey called")
+
+	keyId, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil || keyId < 1 {
+		log.Printf("[handler:auth][ERROR] invalid api key id %q", ctx.Param("id"))
+		ctx.Error(errInvalidAPIKeyID)
+		return
+	}
+
+	err = ah.APIKeyService.Revoke(ctx, ctx.GetInt("userId"), keyId)
+	if err != nil {
+		log.Printf("[handler:auth][ERROR] RevokeAPIKey failed: %v", err)
+		ctx.Error(err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
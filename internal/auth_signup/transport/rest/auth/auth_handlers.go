@@ -1,43 +1,85 @@
 package auth
 
 import (
+	"errors"
+	"fmt"
 	"github.com/1URose/marketplace/internal/auth_signup/domain/redis/entity"
 	"github.com/1URose/marketplace/internal/auth_signup/transport/rest/auth/dto"
 	"github.com/1URose/marketplace/internal/auth_signup/use_cases"
-	dtoErr "github.com/1URose/marketplace/internal/common/transport/rest/dto"
+	"github.com/1URose/marketplace/internal/common/apperror"
+	"github.com/1URose/marketplace/internal/common/i18n"
+	jwtEntity "github.com/1URose/marketplace/internal/common/jwt/entity"
+	"github.com/1URose/marketplace/internal/common/transport/rest/bind"
 
 	"github.com/1URose/marketplace/internal/common/jwt"
+	"github.com/1URose/marketplace/internal/common/password"
+	userEntity "github.com/1URose/marketplace/internal/user_profile/domain/user/entity"
+	userDto "github.com/1URose/marketplace/internal/user_profile/transport/rest/user/dto"
 	"github.com/gin-gonic/gin"
 	"strconv"
 	"strings"
+	"time"
 
 	"log"
 	"net/http"
 )
 
+var (
+	errInvalidUserID       = apperror.Validation("auth.invalid_user_id")
+	errNothingToRevoke     = apperror.Validation("auth.nothing_to_revoke")
+	errInvalidToken        = apperror.Validation("auth.invalid_token")
+	errMissingRefreshToken = apperror.Unauthorized("auth.missing_refresh_token")
+	errInvalidRefreshToken = apperror.Unauthorized("auth.invalid_refresh_token")
+	// errSessionMismatch не различает закрытую сессию и повторно предъявленный токен.
+	errSessionMismatch = apperror.Unauthorized("auth.session_mismatch")
+	errPasswordPolicy  = apperror.Validation("auth.password_policy")
+)
+
 type Handler struct {
-	AuthService *use_cases.AuthService
-	JWTManager  *jwt.Manager
+	AuthService       *use_cases.AuthService
+	OIDCService       *use_cases.OIDCService
+	APIKeyService     *use_cases.APIKeyService
+	EmailVerification *use_cases.EmailVerificationService
+	PasswordReset     *use_cases.PasswordResetService
+	MagicLink         *use_cases.MagicLinkService
+	SecurityEvents    *use_cases.SecurityEventService
+	JWTManager        *jwt.Manager
 }
 
-func NewAuthHandler(authService *use_cases.AuthService, jwtManager *jwt.Manager) *Handler {
+func NewAuthHandler(
+	authService *use_cases.AuthService,
+	oidcService *use_cases.OIDCService,
+	apiKeyService *use_cases.APIKeyService,
+	emailVerification *use_cases.EmailVerificationService,
+	passwordReset *use_cases.PasswordResetService,
+	magicLink *use_cases.MagicLinkService,
+	securityEvents *use_cases.SecurityEventService,
+	jwtManager *jwt.Manager,
+) *Handler {
 	log.Println("[handler:auth] NewAuthHandler initialized")
 
 	return &Handler{
-		AuthService: authService,
-		JWTManager:  jwtManager,
+		AuthService:       authService,
+		OIDCService:       oidcService,
+		APIKeyService:     apiKeyService,
+		EmailVerification: emailVerification,
+		PasswordReset:     passwordReset,
+		MagicLink:         magicLink,
+		SecurityEvents:    securityEvents,
+		JWTManager:        jwtManager,
 	}
 }
 
 // SignUp godoc
 // @Summary      Регистрация нового пользователя
-// @Description  Создать обычного пользователя
+// @Description  Создать обычного пользователя. На указанный email отправляется письмо со ссылкой подтверждения
 // @Tags         auth
 // @Accept       json
 // @Produce      json
 // @Param        user_input  body      dto.SignUpRequest     true  "Регистрационные данные"
 // @Success      201         {object}  dto.SignUpResponse   "Успешная регистрация"
-// @Failure      400         {object}  dto.ErrorResponse    "Ошибка валидации"
+// @Failure      400         {object}  dto.ErrorResponse    "Ошибка валидации; нарушения политики паролей перечислены в violations"
+// @Failure      409         {object}  dto.ErrorResponse    "Email уже занят"
 // @Failure      500         {object}  dto.ErrorResponse    "Внутренняя ошибка сервера"
 // @Router       /auth/signup [post]
 func (ah *Handler) SignUp(ctx *gin.Context) {
@@ -46,30 +88,30 @@ func (ah *Handler) SignUp(ctx *gin.Context) {
 
 	var signUpReq dto.SignUpRequest
 
-	if err := ctx.ShouldBindJSON(&signUpReq); err != nil {
+	if err := bind.JSON(ctx, &signUpReq); err != nil {
 		log.Printf("[handler:auth][ERROR] bind body: %v", err)
 
-		ctx.JSON(http.StatusBadRequest, dtoErr.ErrorResponse{
-			Error:  "Invalid request body",
-			Detail: err.Error(),
-		})
+		ctx.Error(err)
 		return
 	}
 
-	createUserReq, err := ah.AuthService.SingUp(ctx, signUpReq)
+	createUserReq, err := ah.AuthService.SingUp(ctx, signUpReq, ctx.ClientIP(), ctx.Request.UserAgent())
 	if err != nil {
 
 		log.Printf("[handler:auth][ERROR] SingUp failed: %v", err)
 
-		ctx.JSON(http.StatusInternalServerError, dtoErr.ErrorResponse{
-			Error: "Failed to create user",
-		})
+		ctx.Error(passwordError(err, "password"))
 
 		return
 	}
 
 	log.Printf("[handler:auth] registration successful for %q", createUserReq.Email)
 
+	// письмо можно запросить повторно через /auth/resend-verification, поэтому сбой отправки не отменяет регистрацию
+	if err := ah.EmailVerification.SendVerification(ctx, createUserReq); err != nil {
+		log.Printf("[handler:auth][ERROR] send verification email to %q: %v", createUserReq.Email, err)
+	}
+
 	response := dto.NewSignUpResponse(createUserReq)
 
 	ctx.JSON(http.StatusCreated, response)
@@ -77,96 +119,167 @@ func (ah *Handler) SignUp(ctx *gin.Context) {
 
 // Login godoc
 // @Summary      Вход в систему
-// @Description  Аутентификация пользователя и возврат токенов JWT
+// @Description  Аутентификация пользователя и возврат токенов JWT. Если у пользователя включена двухфакторная аутентификация, вместо токенов возвращается mfaToken для POST /auth/login/mfa
 // @Tags         auth
 // @Accept       json
 // @Produce      json
 // @Param        user_input  body      dto.LoginRequest      true  "User login payload"
 // @Success      200         {object}  dto.LoginResponse     "Access и Refresh токены"
+// @Success      200         {object}  dto.MFAChallengeResponse "Требуется второй фактор"
 // @Failure      400         {object}  dto.ErrorResponse     "Неверный запрос"
 // @Failure      401         {object}  dto.ErrorResponse     "Неверные учетные данные"
+// @Failure      403         {object}  dto.ErrorResponse     "Пользователь заблокирован"
+// @Failure      429         {object}  dto.ErrorResponse     "Слишком много неудачных попыток, повторить после Retry-After секунд"
 // @Failure      500         {object}  dto.ErrorResponse     "Внутренняя ошибка сервера"
 // @Router       /auth/login [post]
 func (ah *Handler) Login(ctx *gin.Context) {
 
 	var loginReq dto.LoginRequest
 
-	if err := ctx.ShouldBindJSON(&loginReq); err != nil {
+	if err := bind.JSON(ctx, &loginReq); err != nil {
 		log.Printf("[handler:auth][ERROR] bind body: %v", err)
 
-		ctx.JSON(http.StatusBadRequest, dtoErr.ErrorResponse{
-			Error:  "Invalid request body",
-			Detail: err.Error(),
-		})
+		ctx.Error(err)
 		return
 	}
 
-	existsUser, err := ah.AuthService.Login(ctx, loginReq)
+	existsUser, err := ah.AuthService.Login(ctx, loginReq, ctx.ClientIP(), ctx.Request.UserAgent())
 
 	if err != nil {
 
-		log.Printf("[handler:auth][ERROR] Email failed: %v", err)
+		log.Printf("[handler:auth][ERROR] Login failed for %q: %v", loginReq.Email, err)
 
-		ctx.JSON(http.StatusInternalServerError, dtoErr.ErrorResponse{
-			Error:  "Failed to login",
-			Detail: err.Error(),
-		})
+		ctx.Error(err)
 
 		return
 	}
 
-	if existsUser == nil {
+	log.Printf("[handler:auth] authentication succeeded for %q", loginReq.Email)
 
-		log.Printf("[handler:auth] invalid credentials for %q", loginReq.Email)
+	ah.completeLogin(ctx, existsUser, loginReq.DeviceName)
+}
 
-		ctx.JSON(http.StatusUnauthorized, dtoErr.ErrorResponse{
-			Error: "Invalid credentials",
-		})
+// completeLogin завершает вход уже опознанного пользователя: при включённой двухфакторной
+// аутентификации отвечает mfaToken, иначе выдаёт токены.
+func (ah *Handler) completeLogin(ctx *gin.Context, user *userEntity.User, deviceName string) {
+
+	mfaRequired, err := ah.AuthService.MFARequired(ctx, user.ID)
+
+	if err != nil {
+
+		log.Printf("[handler:auth][ERROR] MFARequired failed: %v", err)
+
+		ctx.Error(err)
 
 		return
 	}
 
-	log.Printf("[handler:auth] authentication succeeded for %q", loginReq.Email)
+	if mfaRequired {
+
+		mfaToken, err := ah.JWTManager.GenerateMFAToken(user.Email, user.ID)
+
+		if err != nil {
 
-	accessToken, err := ah.JWTManager.GenerateAccessToken(existsUser.Email, existsUser.ID)
+			log.Printf("[handler:auth][ERROR] GenerateMFAToken failed: %v", err)
+
+			ctx.Error(err)
+
+			return
+		}
+
+		log.Printf("[handler:auth] second factor required for %q", user.Email)
+
+		ctx.JSON(http.StatusOK, dto.NewMFAChallengeResponse(mfaToken))
+
+		return
+	}
+
+	ah.issueTokens(ctx, user, deviceName)
+}
+
+// passwordError переводит отказ политики паролей и превышение предела bcrypt в ошибку валидации,
+// привязав нарушения к полю field. Остальные ошибки возвращаются без изменений.
+func passwordError(err error, field string) error {
+	if errors.Is(err, password.ErrPasswordTooLong) {
+		key := password.MessageKey("too_long_bytes")
+		params := map[string]interface{}{"max": password.BcryptMaxBytes}
+		return errPasswordPolicy.WithViolations(apperror.Violation{
+			Field:   field,
+			Code:    password.CodeTooLong,
+			Key:     key,
+			Message: i18n.Message(i18n.Default, key, params),
+			Params:  params,
+		})
+	}
+
+	var policyErr *password.PolicyError
+	if !errors.As(err, &policyErr) {
+		return err
+	}
+
+	violations := make([]apperror.Violation, len(policyErr.Violations))
+	for i, v := range policyErr.Violations {
+		violations[i] = apperror.Violation{Field: field, Code: v.Code, Key: v.Key, Message: v.Message, Params: v.Params}
+	}
+	return errPasswordPolicy.WithViolations(violations...)
+}
+
+// issueTokens открывает новую сессию устройства и отвечает парой access/refresh токенов.
+func (ah *Handler) issueTokens(ctx *gin.Context, user *userEntity.User, deviceName string) {
+
+	session, err := entity.NewSession(
+		user.ID,
+		user.Email,
+		deviceName,
+		ctx.ClientIP(),
+		ctx.Request.UserAgent(),
+		time.Now(),
+	)
+
+	if err != nil {
+
+		log.Printf("[handler:auth][ERROR] NewSession failed: %v", err)
+
+		ctx.Error(err)
+
+		return
+	}
+
+	accessToken, err := ah.JWTManager.GenerateAccessToken(user.Email, user.ID, user.Role, session.ID)
 
 	if err != nil {
 
 		log.Printf("[handler:auth][ERROR] GenerateAccessToken failed: %v", err)
 
-		ctx.JSON(http.StatusInternalServerError, dtoErr.ErrorResponse{
-			Error: "Failed to generate access token",
-		})
+		ctx.Error(err)
 
 		return
 	}
 
-	refreshToken, err := ah.JWTManager.GenerateRefreshToken(existsUser.Email, existsUser.ID)
+	refreshToken, err := ah.JWTManager.GenerateRefreshToken(user.Email, user.ID, session.ID)
 
 	if err != nil {
 
 		log.Printf("[handler:auth][ERROR] GenerateRefreshToken failed: %v", err)
 
-		ctx.JSON(http.StatusInternalServerError, dtoErr.ErrorResponse{
-			Error: "Failed to generate refresh token",
-		})
+		ctx.Error(err)
 
 		return
 	}
 
-	session := entity.NewSession(loginReq.Email, refreshToken)
+	session.RefreshToken = refreshToken
 
-	if err = ah.AuthService.RedisRepo.Set(ctx, session); err != nil {
+	if err = ah.AuthService.SaveSession(ctx, session); err != nil {
 
-		log.Printf("[handler:auth][ERROR] Redis Set failed: %v", err)
+		log.Printf("[handler:auth][ERROR] SaveSession failed: %v", err)
 
-		ctx.JSON(http.StatusInternalServerError, dtoErr.ErrorResponse{
-			Error: "Failed to set session",
-		})
+		ctx.Error(err)
 
 		return
 	}
 
+	log.Printf("[handler:auth] tokens issued for userID=%d sid=%s", user.ID, session.ID)
+
 	response := dto.NewLoginResponse(accessToken, refreshToken)
 
 	ctx.JSON(http.StatusOK, response)
@@ -174,7 +287,7 @@ func (ah *Handler) Login(ctx *gin.Context) {
 
 // Refresh godoc
 // @Summary      Обновление токенов
-// @Description  При истечении срока действия access-токена позволяет получить новую пару токенов.
+// @Description  При истечении срока действия access-токена позволяет получить новую пару токенов. Каждый refresh-токен одноразовый: повторное предъявление уже заменённого токена закрывает сессию (кроме короткого окна для параллельных повторов)
 // @Tags         auth
 // @Accept       json
 // @Produce      json
@@ -183,6 +296,7 @@ func (ah *Handler) Login(ctx *gin.Context) {
 // @Success      200             {object}  dto.LoginResponse      "Новые access и refresh токены"
 // @Success      200             {object}  dto.StillValidResponse "Access токен ещё действует"
 // @Failure      401             {object}  dto.ErrorResponse      "Invalid or missing token"
+// @Failure      403             {object}  dto.ErrorResponse      "User is suspended"
 // @Failure      500             {object}  dto.ErrorResponse      "Server error"
 // @Router       /auth/refresh   [post]
 func (ah *Handler) Refresh(ctx *gin.Context) {
@@ -191,7 +305,7 @@ func (ah *Handler) Refresh(ctx *gin.Context) {
 	if authH := ctx.GetHeader("Authorization"); authH != "" {
 		parts := strings.SplitN(authH, " ", 2)
 		if len(parts) == 2 && parts[0] == "Bearer" {
-			if _, err := ah.JWTManager.ValidateAccessToken(parts[1]); err == nil {
+			if accessClaims, err := ah.JWTManager.ValidateAccessToken(ctx, parts[1]); err == nil && !ah.isRevoked(ctx, accessClaims) {
 				log.Printf("[handler:auth] Refresh: access token still valid")
 				ctx.JSON(http.StatusOK, dto.StillValidResponse{
 					StillValid: true,
@@ -205,82 +319,80 @@ func (ah *Handler) Refresh(ctx *gin.Context) {
 	raw := ctx.GetHeader("X-Refresh-Token")
 	if raw == "" {
 		log.Printf("[handler:auth][ERROR] Missing refresh token")
-		ctx.JSON(http.StatusUnauthorized, dtoErr.ErrorResponse{
-			Error: "Missing refresh token",
-		})
+		ctx.Error(errMissingRefreshToken)
 		return
 	}
 	parts := strings.SplitN(raw, " ", 2)
 	if len(parts) != 2 || parts[0] != "Bearer" {
 		log.Printf("[handler:auth][ERROR] Invalid refresh token format: %q", raw)
-		ctx.JSON(http.StatusUnauthorized, dtoErr.ErrorResponse{
-			Error: "Invalid refresh token",
-		})
+		ctx.Error(errInvalidRefreshToken)
 		return
 	}
 	refreshToken := parts[1]
 
-	claims, err := ah.JWTManager.ValidateRefreshToken(refreshToken)
+	claims, err := ah.JWTManager.ValidateRefreshToken(ctx, refreshToken)
 	if err != nil {
 		log.Printf("[handler:auth][ERROR] ValidateRefreshToken failed: %v", err)
-		ctx.JSON(http.StatusUnauthorized, dtoErr.ErrorResponse{
-			Error: "Invalid refresh token",
-		})
+		ctx.Error(errInvalidRefreshToken)
 		return
 	}
-	log.Printf("[handler:auth] Refresh: valid refresh token for email=%s", claims.Email)
+	log.Printf("[handler:auth] Refresh: valid refresh token for email=%s sid=%s", claims.Email, claims.SessionID)
 
-	session, err := ah.AuthService.RedisRepo.Get(ctx, claims.Email)
+	userId, err := strconv.Atoi(claims.Subject)
 	if err != nil {
-		log.Printf("[handler:auth][ERROR] Redis Get failed for email=%s: %v", claims.Email, err)
-		ctx.JSON(http.StatusUnauthorized, dtoErr.ErrorResponse{
-			Error: "Session not found or token mismatch",
-		})
+		log.Printf("[handler:auth][ERROR] strconv.Atoi failed: %v", err)
+		ctx.Error(err)
 		return
 	}
-	if session == nil || session.RefreshToken != refreshToken {
-		log.Printf("[handler:auth][ERROR] Session not found or token mismatch: session=%v", session)
-		ctx.JSON(http.StatusUnauthorized, dtoErr.ErrorResponse{
-			Error: "Session not found or token mismatch",
-		})
+
+	user, err := ah.AuthService.CheckActive(ctx, userId)
+	if err != nil {
+		log.Printf("[handler:auth][ERROR] CheckActive failed for userID=%d: %v", userId, err)
+		if errors.Is(err, use_cases.ErrUserNotFound) {
+			err = errInvalidRefreshToken
+		}
+		ctx.Error(err)
 		return
 	}
 
-	userId, err := strconv.Atoi(claims.Subject)
+	// email берётся из базы, а не из токена: после смены адреса сессии продолжают работать уже с новым
+	nextRefresh, err := ah.JWTManager.GenerateRefreshToken(user.Email, userId, claims.SessionID)
 	if err != nil {
-		log.Printf("[handler:auth][ERROR] strconv.Atoi failed: %v", err)
-		ctx.JSON(http.StatusInternalServerError, dtoErr.ErrorResponse{
-			Error: "Failed to parse user id",
-		})
+		log.Printf("[handler:auth][ERROR] GenerateRefreshToken failed: %v", err)
+		ctx.Error(err)
 		return
 	}
-	newAccess, err := ah.JWTManager.GenerateAccessToken(claims.Email, userId)
-	if err != nil {
-		log.Printf("[handler:auth][ERROR] GenerateAccessToken failed: %v", err)
-		ctx.JSON(http.StatusInternalServerError, dtoErr.ErrorResponse{
-			Error: "Failed to generate new access token",
-		})
+
+	session, err := ah.AuthService.RotateRefreshToken(
+		ctx,
+		userId,
+		claims.SessionID,
+		refreshToken,
+		nextRefresh,
+		ctx.ClientIP(),
+		ctx.Request.UserAgent(),
+	)
+	if errors.Is(err, use_cases.ErrSessionNotFound) || errors.Is(err, use_cases.ErrRefreshTokenReused) {
+		log.Printf("[handler:auth][ERROR] RotateRefreshToken rejected for sid=%s: %v", claims.SessionID, err)
+		ctx.Error(errSessionMismatch)
 		return
 	}
-	newRefresh, err := ah.JWTManager.GenerateRefreshToken(claims.Email, userId)
 	if err != nil {
-		log.Printf("[handler:auth][ERROR] GenerateRefreshToken failed: %v", err)
-		ctx.JSON(http.StatusInternalServerError, dtoErr.ErrorResponse{
-			Error: "Failed to generate new refresh token",
-		})
+		log.Printf("[handler:auth][ERROR] RotateRefreshToken failed: %v", err)
+		ctx.Error(err)
 		return
 	}
 
-	log.Printf("[handler:auth] Session updated in Redis for email=%s", claims.Email)
-
-	session.RefreshToken = newRefresh
-	if err := ah.AuthService.RedisRepo.Set(ctx, session); err != nil {
-		log.Printf("[handler:auth][ERROR] Redis Set failed: %v", err)
-		ctx.JSON(http.StatusInternalServerError, dtoErr.ErrorResponse{
-			Error: "Failed to update session in Redis",
-		})
+	newAccess, err := ah.JWTManager.GenerateAccessToken(user.Email, userId, user.Role, session.ID)
+	if err != nil {
+		log.Printf("[handler:auth][ERROR] GenerateAccessToken failed: %v", err)
+		ctx.Error(err)
 		return
 	}
+	// в окне grace клиент получает уже выданного преемника, а не только что сгенерированный токен
+	newRefresh := session.RefreshToken
+
+	log.Printf("[handler:auth] Session updated in Redis: sid=%s", session.ID)
 
 	response := dto.NewLoginResponse(newAccess, newRefresh)
 
@@ -288,3 +400,277 @@ func (ah *Handler) Refresh(ctx *gin.Context) {
 
 	log.Printf("[handler:auth] Refresh succeeded: userID=%d", userId)
 }
+
+// isRevoked считает токен отозванным и в том случае, когда проверить отзыв не удалось.
+func (ah *Handler) isRevoked(ctx *gin.Context, claims *jwtEntity.Claims) bool {
+	userId, err := strconv.Atoi(claims.Subject)
+	if err != nil {
+		return true
+	}
+
+	revoked, err := ah.AuthService.IsRevoked(ctx, userId, claims)
+	if err != nil {
+		log.Printf("[handler:auth][ERROR] IsRevoked failed for userID=%d: %v", userId, err)
+		return true
+	}
+	return revoked
+}
+
+// Logout godoc
+// @Summary      Выход из системы
+// @Description  Закрывает текущую сессию: её access- и refresh-токены перестают действовать, остальные устройства не затрагиваются
+// @Tags         auth
+// @Produce      json
+// @Param        Authorization header string true "JWT Access token"
+// @Success      204 "Сессия удалена"
+// @Failure      401 {object} dto.ErrorResponse "Неавторизован"
+// @Failure      500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
+// @Router       /auth/logout [post]
+func (ah *Handler) Logout(ctx *gin.Context) {
+	log.Printf("[handler:auth] Logout called")
+
+	userId := ctx.GetInt("userId")
+	sessionId := ctx.GetString("sessionId")
+
+	if err := ah.AuthService.Logout(ctx, userId, sessionId, ctx.ClientIP(), ctx.Request.UserAgent()); err != nil {
+		log.Printf("[handler:auth][ERROR] Logout failed: %v", err)
+		ctx.Error(err)
+		return
+	}
+
+	log.Printf("[handler:auth] Logout succeeded for userID=%d sid=%s", userId, sessionId)
+	ctx.Status(http.StatusNoContent)
+}
+
+// LogoutAll godoc
+// @Summary      Выход на всех устройствах
+// @Description  Закрывает все сессии и отзывает все access- и refresh-токены пользователя, выданные до этого момента
+// @Tags         auth
+// @Produce      json
+// @Param        Authorization header string true "JWT Access token"
+// @Success      204 "Все токены отозваны"
+// @Failure      401 {object} dto.ErrorResponse "Неавторизован"
+// @Failure      500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
+// @Router       /auth/logout-all [post]
+func (ah *Handler) LogoutAll(ctx *gin.Context) {
+	log.Printf("[handler:auth] LogoutAll called")
+
+	userId := ctx.GetInt("userId")
+
+	if err := ah.AuthService.LogoutAll(ctx, userId, ctx.ClientIP(), ctx.Request.UserAgent()); err != nil {
+		log.Printf("[handler:auth][ERROR] LogoutAll failed: %v", err)
+		ctx.Error(err)
+		return
+	}
+
+	log.Printf("[handler:auth] LogoutAll succeeded for userID=%d", userId)
+	ctx.Status(http.StatusNoContent)
+}
+
+// ListSessions godoc
+// @Summary      Активные сессии
+// @Description  Возвращает устройства, с которых пользователь сейчас вошёл в систему; текущее отмечено current
+// @Tags         auth
+// @Produce      json
+// @Param        Authorization header string true "JWT Access token"
+// @Success      200 {array}  dto.SessionResponse "Сессии пользователя"
+// @Failure      401 {object} dto.ErrorResponse   "Неавторизован"
+// @Failure      500 {object} dto.ErrorResponse   "Внутренняя ошибка сервера"
+// @Router       /auth/sessions [get]
+func (ah *Handler) ListSessions(ctx *gin.Context) {
+	log.Printf("[handler:auth] ListSessions called")
+
+	userId := ctx.GetInt("userId")
+
+	sessions, err := ah.AuthService.ListSessions(ctx, userId)
+	if err != nil {
+		log.Printf("[handler:auth][ERROR] ListSessions failed: %v", err)
+		ctx.Error(err)
+		return
+	}
+
+	log.Printf("[handler:auth] ListSessions succeeded for userID=%d: sessions=%d", userId, len(sessions))
+	ctx.JSON(http.StatusOK, dto.NewSessionsResponse(sessions, ctx.GetString("sessionId")))
+}
+
+// RevokeSession godoc
+// @Summary      Завершить сессию
+// @Description  Закрывает сессию на выбранном устройстве: её токены сразу перестают действовать
+// @Tags         auth
+// @Produce      json
+// @Param        Authorization header string true "JWT Access token"
+// @Param        id            path   string true "ID сессии"
+// @Success      204 "Сессия завершена"
+// @Failure      401 {object} dto.ErrorResponse "Неавторизован"
+// @Failure      404 {object} dto.ErrorResponse "Сессия не найдена"
+// @Failure      500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
+// @Router       /auth/sessions/{id} [delete]
+func (ah *Handler) RevokeSession(ctx *gin.Context) {
+	log.Printf("[handler:auth] RevokeSession called")
+
+	userId := ctx.GetInt("userId")
+	sessionId := ctx.Param("id")
+
+	err := ah.AuthService.RevokeSession(ctx, userId, sessionId)
+	if err != nil {
+		log.Printf("[handler:auth][ERROR] RevokeSession failed: %v", err)
+		ctx.Error(err)
+		return
+	}
+
+	log.Printf("[handler:auth] RevokeSession succeeded for userID=%d sid=%s", userId, sessionId)
+	ctx.Status(http.StatusNoContent)
+}
+
+// RevokeTokens godoc
+// @Summary      Отозвать токены
+// @Description  Вносит переданные access- и refresh-токены в denylist до истечения их срока действия; с user_id дополнительно закрывает все сессии пользователя и отзывает все его токены. Доступно только администраторам. Токены отзываются по порядку, повторный отзыв безопасен
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        Authorization header string                   true "JWT Access token"
+// @Param        request       body   dto.RevokeTokensRequest  true "Токены и/или ID пользователя"
+// @Success      200 {object} dto.RevokeTokensResponse "Отозванные токены"
+// @Failure      400 {object} dto.ErrorResponse        "Неверные данные запроса или токен"
+// @Failure      401 {object} dto.ErrorResponse        "Неавторизован"
+// @Failure      403 {object} dto.ErrorResponse        "Недостаточно прав"
+// @Failure      500 {object} dto.ErrorResponse        "Внутренняя ошибка сервера"
+// @Router       /admin/tokens/revoke [post]
+func (ah *Handler) RevokeTokens(ctx *gin.Context) {
+	log.Printf("[handler:auth] RevokeTokens called")
+
+	var req dto.RevokeTokensRequest
+	if err := bind.JSON(ctx, &req); err != nil {
+		log.Printf("[handler:auth][ERROR] bind body: %v", err)
+		ctx.Error(err)
+		return
+	}
+	if len(req.Tokens) == 0 && req.UserID == 0 {
+		ctx.Error(errNothingToRevoke)
+		return
+	}
+
+	resp := dto.RevokeTokensResponse{Revoked: make([]string, 0, len(req.Tokens))}
+	for i, token := range req.Tokens {
+		claims, err := ah.JWTManager.Revoke(ctx, token)
+		if errors.Is(err, jwt.ErrInvalidToken) {
+			log.Printf("[handler:auth][ERROR] Revoke tokens[%d] rejected: %v", i, err)
+			ctx.Error(errInvalidToken.WithDetail(fmt.Sprintf("tokens[%d]: %v", i, err)))
+			return
+		}
+		if err != nil {
+			log.Printf("[handler:auth][ERROR] Revoke tokens[%d] failed: %v", i, err)
+			ctx.Error(err)
+			return
+		}
+		resp.Revoked = append(resp.Revoked, claims.ID)
+	}
+
+	if req.UserID != 0 {
+		if err := ah.AuthService.LogoutAll(ctx, req.UserID, ctx.ClientIP(), ctx.Request.UserAgent()); err != nil {
+			log.Printf("[handler:auth][ERROR] LogoutAll failed for userID=%d: %v", req.UserID, err)
+			ctx.Error(err)
+			return
+		}
+		resp.UserID = req.UserID
+	}
+
+	log.Printf("[handler:auth] RevokeTokens succeeded: tokens=%d userID=%d by adminID=%d",
+		len(resp.Revoked), resp.UserID, ctx.GetInt("userId"),
+	)
+	ctx.JSON(http.StatusOK, resp)
+}
+
+// UnlockLogin godoc
+// @Summary      Снять блокировку входа
+// @Description  Снимает блокировку входа, наложенную после серии неудачных попыток, и сбрасывает счётчик неудач учётной записи. Доступно только администраторам
+// @Tags         admin
+// @Produce      json
+// @Param        Authorization header string true "JWT Access token"
+// @Param        id            path   int    true "ID пользователя"
+// @Success      204 "Блокировка снята"
+// @Failure      400 {object} dto.ErrorResponse "Неверный ID пользователя"
+// @Failure      401 {object} dto.ErrorResponse "Неавторизован"
+// @Failure      403 {object} dto.ErrorResponse "Недостаточно прав"
+// @Failure      404 {object} dto.ErrorResponse "Пользователь не найден"
+// @Failure      500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
+// @Router       /admin/users/{id}/unlock-login [post]
+func (ah *Handler) UnlockLogin(ctx *gin.Context) {
+	log.Printf("[handler:auth] UnlockLogin called")
+
+	userId, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil || userId < 1 {
+		log.Printf("[handler:auth][ERROR] invalid user id %q", ctx.Param("id"))
+		ctx.Error(errInvalidUserID)
+		return
+	}
+
+	err = ah.AuthService.UnlockLogin(ctx, userId, ctx.ClientIP(), ctx.Request.UserAgent())
+	if err != nil {
+		log.Printf("[handler:auth][ERROR] UnlockLogin failed: %v", err)
+		ctx.Error(err)
+		return
+	}
+
+	log.Printf("[handler:auth] UnlockLogin succeeded for userID=%d by adminID=%d", userId, ctx.GetInt("userId"))
+	ctx.Status(http.StatusNoContent)
+}
+
+// JWKS godoc
+// @Summary      Открытые ключи JWT
+// @Description  Набор открытых ключей (JWKS), которыми сервисы могут сами проверять access-токены по kid из заголовка. При подписи HS256 набор пуст
+// @Tags         auth
+// @Produce      json
+// @Success      200 {object} jwt.JWKS "Открытые ключи"
+// @Router       /.well-known/jwks.json [get]
+func (ah *Handler) JWKS(ctx *gin.Context) {
+	log.Printf("[handler:auth] JWKS called")
+
+	// при ротации новый ключ публикуется заранее, так что короткого кэширования достаточно
+	ctx.Header("Cache-Control", "public, max-age=300")
+	ctx.JSON(http.StatusOK, ah.JWTManager.JWKS())
+}
+
+// SetUserRole godoc
+// @Summary      Назначить роль (администраторы)
+// @Description  Назначает пользователю роль user, moderator или admin. При понижении роли все токены пользователя отзываются. Свою роль менять нельзя
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        Authorization header string             true "JWT Access token администратора"
+// @Param        id            path   int                true "ID пользователя"
+// @Param        request       body   dto.SetRoleRequest true "Новая роль"
+// @Success      200 {object} dto.UserResponse  "Пользователь с новой ролью"
+// @Failure      400 {object} dto.ErrorResponse "Неверный запрос"
+// @Failure      401 {object} dto.ErrorResponse "Неавторизован"
+// @Failure      403 {object} dto.ErrorResponse "Нет прав или попытка изменить свою роль"
+// @Failure      404 {object} dto.ErrorResponse "Пользователь не найден"
+// @Failure      500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
+// @Router       /admin/users/{id}/role [put]
+func (ah *Handler) SetUserRole(ctx *gin.Context) {
+	log.Printf("[handler:auth] SetUserRole called")
+
+	userId, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil || userId < 1 {
+		log.Printf("[handler:auth][ERROR] invalid user id %q", ctx.Param("id"))
+		ctx.Error(errInvalidUserID)
+		return
+	}
+
+	var req dto.SetRoleRequest
+	if err := bind.JSON(ctx, &req); err != nil {
+		log.Printf("[handler:auth][ERROR] bind body: %v", err)
+		ctx.Error(err)
+		return
+	}
+
+	user, err := ah.AuthService.SetRole(ctx, ctx.GetInt("userId"), userId, req.Role, ctx.ClientIP(), ctx.Request.UserAgent())
+	if err != nil {
+		log.Printf("[handler:auth][ERROR] SetRole failed: %v", err)
+		ctx.Error(err)
+		return
+	}
+
+	log.Printf("[handler:auth] SetUserRole succeeded: userID=%d role=%s", userId, user.Role)
+	ctx.JSON(http.StatusOK, userDto.NewUserResponse(user))
+}
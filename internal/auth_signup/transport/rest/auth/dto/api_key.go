@@ -0,0 +1,55 @@
+package dto
+
+import (
+	"github.com/1URose/marketplace/internal/auth_signup/domain/api_key/entity"
+	"time"
+)
+
+type CreateAPIKeyRequest struct {
+	Name string `json:"name" binding:"required,max=100"`
+	// Scopes — права ключа: ads:read, ads:write, reports:write.
+	Scopes []string `json:"scopes" binding:"required,min=1,max=10,dive,required"`
+	// ExpiresAt — срок действия в RFC 3339; без него ключ бессрочный.
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+type APIKeyResponse struct {
+	ID         int      `json:"id"`
+	Name       string   `json:"name"`
+	Prefix     string   `json:"prefix"`
+	Scopes     []string `json:"scopes"`
+	ExpiresAt  string   `json:"expires_at,omitempty"`
+	LastUsedAt string   `json:"last_used_at,omitempty"`
+	CreatedAt  string   `json:"created_at"`
+}
+
+// CreateAPIKeyResponse содержит сам ключ; он показывается один раз.
+type CreateAPIKeyResponse struct {
+	APIKeyResponse
+	Key string `json:"key"`
+}
+
+func NewAPIKeyResponse(k *entity.APIKey) APIKeyResponse {
+	resp := APIKeyResponse{
+		ID:        k.ID,
+		Name:      k.Name,
+		Prefix:    k.Prefix,
+		Scopes:    k.Scopes,
+		CreatedAt: k.CreatedAt.Format(time.RFC3339),
+	}
+	if k.ExpiresAt != nil {
+		resp.ExpiresAt = k.ExpiresAt.Format(time.RFC3339)
+	}
+	if k.LastUsedAt != nil {
+		resp.LastUsedAt = k.LastUsedAt.Format(time.RFC3339)
+	}
+	return resp
+}
+
+func NewAPIKeysResponse(keys []*entity.APIKey) []APIKeyResponse {
+	resp := make([]APIKeyResponse, len(keys))
+	for i, k := range keys {
+		resp[i] = NewAPIKeyResponse(k)
+	}
+	return resp
+}
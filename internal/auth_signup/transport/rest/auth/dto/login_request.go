@@ -2,5 +2,7 @@ package dto
 
 type LoginRequest struct {
 	Email    string `json:"email" binding:"required,email"`
-	Password string `json:"password" binding:"required,min=8"`
+	Password string `json:"password" binding:"required"`
+	// DeviceName — название устройства для списка сессий, например «iPhone Анны».
+	DeviceName string `json:"device_name" binding:"max=100"`
 }
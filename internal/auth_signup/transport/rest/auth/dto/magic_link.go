@@ -0,0 +1,18 @@
+package dto
+
+type MagicLinkRequest struct {
+	Email string `json:"email" binding:"required,email"`
+	// BindDevice — выдать секрет устройства, без которого ссылка из письма не сработает.
+	BindDevice bool `json:"bind_device"`
+}
+
+// MagicLinkResponse содержит секрет устройства, только если он запрошен; клиент хранит его до перехода по ссылке.
+type MagicLinkResponse struct {
+	DeviceSecret string `json:"device_secret,omitempty"`
+}
+
+type MagicLinkConsumeRequest struct {
+	Token        string `json:"token" binding:"required"`
+	DeviceSecret string `json:"device_secret"`
+	DeviceName   string `json:"device_name" binding:"max=100"`
+}
@@ -0,0 +1,41 @@
+package dto
+
+// MFAChallengeResponse возвращается вместо токенов, когда после пароля нужен второй фактор.
+type MFAChallengeResponse struct {
+	MFARequired bool   `json:"mfaRequired"`
+	MFAToken    string `json:"mfaToken"`
+}
+
+func NewMFAChallengeResponse(mfaToken string) *MFAChallengeResponse {
+	return &MFAChallengeResponse{
+		MFARequired: true,
+		MFAToken:    mfaToken,
+	}
+}
+
+// MFALoginRequest — второй шаг входа: нужен код TOTP или один из кодов восстановления.
+type MFALoginRequest struct {
+	MFAToken     string `json:"mfa_token" binding:"required"`
+	Code         string `json:"code" binding:"required_without=RecoveryCode,omitempty,len=6,numeric"`
+	RecoveryCode string `json:"recovery_code" binding:"required_without=Code,max=32"`
+	DeviceName   string `json:"device_name" binding:"max=100"`
+}
+
+type TOTPEnrollmentResponse struct {
+	Secret     string `json:"secret"`
+	OtpauthURI string `json:"otpauth_uri"`
+}
+
+type TOTPConfirmRequest struct {
+	Code string `json:"code" binding:"required,len=6,numeric"`
+}
+
+// RecoveryCodesResponse — коды восстановления; показываются один раз, сохранить их должен пользователь.
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+type TOTPDisableRequest struct {
+	Code         string `json:"code" binding:"required_without=RecoveryCode,omitempty,len=6,numeric"`
+	RecoveryCode string `json:"recovery_code" binding:"required_without=Code,max=32"`
+}
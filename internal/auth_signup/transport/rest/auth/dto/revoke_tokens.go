@@ -0,0 +1,14 @@
+package dto
+
+// RevokeTokensRequest — что отозвать: конкретные токены (access или refresh) и/или все токены пользователя.
+type RevokeTokensRequest struct {
+	Tokens []string `json:"tokens" binding:"max=100,dive,required"`
+	UserID int      `json:"user_id" binding:"omitempty,min=1"`
+}
+
+type RevokeTokensResponse struct {
+	// Revoked — jti отозванных токенов в порядке запроса.
+	Revoked []string `json:"revoked"`
+	// UserID — пользователь, все токены которого отозваны; 0, если такой отзыв не запрашивался.
+	UserID int `json:"user_id,omitempty"`
+}
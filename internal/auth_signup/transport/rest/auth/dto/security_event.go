@@ -0,0 +1,61 @@
+package dto
+
+import (
+	"github.com/1URose/marketplace/internal/auth_signup/domain/security_event/entity"
+	"time"
+)
+
+type SecurityEventsRequest struct {
+	Page int `form:"page,default=1" binding:"min=1"`
+}
+
+// AdminSecurityEventsRequest — фильтр журнала для администраторов; type можно передать несколько раз.
+type AdminSecurityEventsRequest struct {
+	Page   int        `form:"page,default=1" binding:"min=1"`
+	UserID *int       `form:"user_id" binding:"omitempty,min=1"`
+	Types  []string   `form:"type"`
+	IP     string     `form:"ip" binding:"omitempty,ip"`
+	From   *time.Time `form:"from"`
+	To     *time.Time `form:"to"`
+}
+
+func (r *AdminSecurityEventsRequest) Filter() *entity.Filter {
+	return &entity.Filter{
+		UserID: r.UserID,
+		Types:  r.Types,
+		IP:     r.IP,
+		From:   r.From,
+		To:     r.To,
+	}
+}
+
+type SecurityEventResponse struct {
+	ID        int64  `json:"id"`
+	Type      string `json:"type"`
+	UserID    int    `json:"user_id"`
+	SessionID string `json:"session_id,omitempty"`
+	IP        string `json:"ip"`
+	UserAgent string `json:"user_agent"`
+	CreatedAt string `json:"created_at"`
+}
+
+type SecurityEventsResponse struct {
+	Events     []SecurityEventResponse `json:"events"`
+	CountPages int                     `json:"count_pages"`
+}
+
+func NewSecurityEventsResponse(events []*entity.SecurityEvent, countPages int) *SecurityEventsResponse {
+	resp := make([]SecurityEventResponse, len(events))
+	for i, e := range events {
+		resp[i] = SecurityEventResponse{
+			ID:        e.ID,
+			Type:      e.Type,
+			UserID:    e.UserID,
+			SessionID: e.SessionID,
+			IP:        e.IP,
+			UserAgent: e.UserAgent,
+			CreatedAt: e.CreatedAt.Format(time.RFC3339),
+		}
+	}
+	return &SecurityEventsResponse{Events: resp, CountPages: countPages}
+}
@@ -0,0 +1,5 @@
+package dto
+
+type SetRoleRequest struct {
+	Role string `json:"role" binding:"required,oneof=user moderator admin"`
+}
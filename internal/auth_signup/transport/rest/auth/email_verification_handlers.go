@@ -0,0 +1,76 @@
+package auth
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/1URose/marketplace/internal/auth_signup/transport/rest/auth/dto"
+	"github.com/1URose/marketplace/internal/auth_signup/use_cases"
+	"github.com/1URose/marketplace/internal/common/transport/rest/bind"
+	"github.com/gin-gonic/gin"
+)
+
+// VerifyEmail godoc
+// @Summary      Подтверждение email
+// @Description  Принимает токен из письма и отмечает email пользователя подтверждённым; токен из письма смены адреса (PUT /me/email) переключает учётную запись на новый email. Токен одноразовый и действует ограниченное время
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        request body dto.VerifyEmailRequest true "Токен из письма"
+// @Success      200 {object} dto.VerifyEmailResponse "Email подтверждён"
+// @Failure      400 {object} dto.ErrorResponse "Неверный, истёкший или уже использованный токен"
+// @Failure      409 {object} dto.ErrorResponse "Новый адрес уже занят"
+// @Failure      500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
+// @Router       /auth/verify-email [post]
+func (ah *Handler) VerifyEmail(ctx *gin.Context) {
+	log.Printf("[handler:auth] VerifyEmail called")
+
+	var req dto.VerifyEmailRequest
+	if err := bind.JSON(ctx, &req); err != nil {
+		log.Printf("[handler:auth][ERROR] bind body: %v", err)
+		ctx.Error(err)
+		return
+	}
+
+	user, err := ah.EmailVerification.Verify(ctx, req.Token)
+	if err != nil {
+		log.Printf("[handler:auth][ERROR] VerifyEmail failed: %v", err)
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.VerifyEmailResponse{
+		Email:         user.Email,
+		EmailVerified: true,
+	})
+}
+
+// ResendVerification godoc
+// @Summary      Повторная отправка письма подтверждения
+// @Description  Отправляет новое письмо со ссылкой подтверждения текущему пользователю. Не чаще одного письма за EMAIL_VERIFICATION_RESEND_COOLDOWN_SECONDS
+// @Tags         auth
+// @Security     BearerAuth
+// @Success      202 "Письмо отправлено"
+// @Failure      401 {object} dto.ErrorResponse "Не авторизован"
+// @Failure      409 {object} dto.ErrorResponse "Email уже подтверждён"
+// @Failure      429 {object} dto.ErrorResponse "Письмо недавно отправлялось, повторить после Retry-After секунд"
+// @Failure      500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
+// @Router       /auth/resend-verification [post]
+func (ah *Handler) ResendVerification(ctx *gin.Context) {
+	userId := ctx.GetInt("userId")
+
+	log.Printf("[handler:auth] ResendVerification called: userID=%d", userId)
+
+	err := ah.EmailVerification.Resend(ctx, userId)
+	if errors.Is(err, use_cases.ErrUserNotFound) {
+		err = errAccountNotFound
+	}
+	if err != nil {
+		log.Printf("[handler:auth][ERROR] ResendVerification failed for userID=%d: %v", userId, err)
+		ctx.Error(err)
+		return
+	}
+
+	ctx.Status(http.StatusAccepted)
+}
@@ -0,0 +1,87 @@
+package auth
+
+import (
+	"log"
+	"net/http"
+
+	magicEntity "github.com/1URose/marketplace/internal/auth_signup/domain/magic_link/entity"
+	"github.com/1URose/marketplace/internal/auth_signup/transport/rest/auth/dto"
+	"github.com/1URose/marketplace/internal/common/transport/rest/bind"
+	"github.com/gin-gonic/gin"
+)
+
+// RequestMagicLink godoc
+// @Summary      Запрос ссылки для входа без пароля
+// @Description  Отправляет на email одноразовую ссылку входа. Ответ всегда 202, даже если такого пользователя нет. С bind_device=true в ответе приходит device_secret: ссылка сработает, только если его предъявит то же устройство
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        request body dto.MagicLinkRequest true "Email и привязка к устройству"
+// @Success      202 {object} dto.MagicLinkResponse "Запрос принят"
+// @Failure      400 {object} dto.ErrorResponse "Неверный запрос"
+// @Failure      500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
+// @Router       /auth/magic-link [post]
+func (ah *Handler) RequestMagicLink(ctx *gin.Context) {
+	log.Printf("[handler:auth] RequestMagicLink called")
+
+	var req dto.MagicLinkRequest
+	if err := bind.JSON(ctx, &req); err != nil {
+		log.Printf("[handler:auth][ERROR] bind body: %v", err)
+		ctx.Error(err)
+		return
+	}
+
+	var response dto.MagicLinkResponse
+	var deviceHash string
+	if req.BindDevice {
+		// секрет выдаётся и для несуществующего email, чтобы ответ ничем не отличался
+		secret, hash, err := magicEntity.NewDeviceSecret()
+		if err != nil {
+			log.Printf("[handler:auth][ERROR] NewDeviceSecret failed: %v", err)
+			ctx.Error(err)
+			return
+		}
+		response.DeviceSecret = secret
+		deviceHash = hash
+	}
+
+	if err := ah.MagicLink.Request(ctx, req.Email, deviceHash); err != nil {
+		log.Printf("[handler:auth][ERROR] RequestMagicLink failed: %v", err)
+	}
+
+	ctx.JSON(http.StatusAccepted, response)
+}
+
+// ConsumeMagicLink godoc
+// @Summary      Вход по ссылке из письма
+// @Description  Обменивает токен из ссылки на access и refresh токены, как POST /auth/login; при включённой двухфакторной аутентификации возвращает mfaToken. Ссылка одноразовая; для привязанной к устройству ссылки нужен device_secret из ответа POST /auth/magic-link
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        request body dto.MagicLinkConsumeRequest true "Токен из ссылки"
+// @Success      200 {object} dto.LoginResponse "Access и Refresh токены"
+// @Success      200 {object} dto.MFAChallengeResponse "Требуется второй фактор"
+// @Failure      400 {object} dto.ErrorResponse "Неверный запрос"
+// @Failure      401 {object} dto.ErrorResponse "Неверная, истёкшая или уже использованная ссылка"
+// @Failure      403 {object} dto.ErrorResponse "Ссылка открыта на другом устройстве или пользователь заблокирован"
+// @Failure      500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
+// @Router       /auth/magic-link/consume [post]
+func (ah *Handler) ConsumeMagicLink(ctx *gin.Context) {
+	log.Printf("[handler:auth] ConsumeMagicLink called")
+
+	var req dto.MagicLinkConsumeRequest
+	if err := bind.JSON(ctx, &req); err != nil {
+		log.Printf("[handler:auth][ERROR] bind body: %v", err)
+		ctx.Error(err)
+		return
+	}
+
+	user, err := ah.MagicLink.Consume(ctx, req.Token, req.DeviceSecret)
+	if err != nil {
+		log.Printf("[handler:auth][ERROR] ConsumeMagicLink failed: %v", err)
+		ctx.Error(err)
+		return
+	}
+
+	ah.completeLogin(ctx, user, req.DeviceName)
+}
@@ -0,0 +1,188 @@
+package auth
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	mfaEntity "github.com/1URose/marketplace/internal/auth_signup/domain/mfa/entity"
+	"github.com/1URose/marketplace/internal/auth_signup/transport/rest/auth/dto"
+	"github.com/1URose/marketplace/internal/auth_signup/use_cases"
+	"github.com/1URose/marketplace/internal/common/apperror"
+	"github.com/1URose/marketplace/internal/common/transport/rest/bind"
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	errInvalidMFAToken = apperror.Unauthorized("mfa.invalid_token")
+	// errInvalidMFALogin не раскрывает при входе, почему код не подошёл.
+	errInvalidMFALogin = apperror.Unauthorized("mfa.invalid_code")
+)
+
+// LoginMFA godoc
+// @Summary      Второй шаг входа
+// @Description  Принимает mfaToken из POST /auth/login и код TOTP либо одноразовый код восстановления; возвращает access и refresh токены. mfaToken одноразовый
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        request body dto.MFALoginRequest true "MFA-токен и код"
+// @Success      200 {object} dto.LoginResponse "Access и Refresh токены"
+// @Failure      400 {object} dto.ErrorResponse "Неверный запрос"
+// @Failure      401 {object} dto.ErrorResponse "Неверный код или MFA-токен"
+// @Failure      403 {object} dto.ErrorResponse "Пользователь заблокирован"
+// @Failure      429 {object} dto.ErrorResponse "Слишком много неудачных попыток"
+// @Failure      500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
+// @Router       /auth/login/mfa [post]
+func (ah *Handler) LoginMFA(ctx *gin.Context) {
+	log.Printf("[handler:auth] LoginMFA called")
+
+	var req dto.MFALoginRequest
+	if err := bind.JSON(ctx, &req); err != nil {
+		log.Printf("[handler:auth][ERROR] bind body: %v", err)
+		ctx.Error(err)
+		return
+	}
+
+	claims, err := ah.JWTManager.ValidateMFAToken(ctx, req.MFAToken)
+	if err != nil {
+		log.Printf("[handler:auth][ERROR] ValidateMFAToken failed: %v", err)
+		ctx.Error(errInvalidMFAToken)
+		return
+	}
+
+	userId, err := strconv.Atoi(claims.Subject)
+	if err != nil {
+		log.Printf("[handler:auth][ERROR] invalid subject %q: %v", claims.Subject, err)
+		ctx.Error(errInvalidMFAToken)
+		return
+	}
+
+	user, err := ah.AuthService.CompleteMFALogin(ctx, userId, req.Code, req.RecoveryCode, ctx.ClientIP(), ctx.Request.UserAgent())
+	if errors.Is(err, mfaEntity.ErrInvalidMFACode) ||
+		errors.Is(err, mfaEntity.ErrMFANotEnabled) ||
+		errors.Is(err, use_cases.ErrUserNotFound) {
+		log.Printf("[handler:auth][ERROR] CompleteMFALogin rejected for userID=%d: %v", userId, err)
+		err = errInvalidMFALogin
+	}
+	if err != nil {
+		log.Printf("[handler:auth][ERROR] CompleteMFALogin failed: %v", err)
+		ctx.Error(err)
+		return
+	}
+
+	// MFA-токен одноразовый: иначе его можно было бы переиграть со следующим кодом
+	if _, err := ah.JWTManager.Revoke(ctx, req.MFAToken); err != nil {
+		log.Printf("[handler:auth][ERROR] revoke MFA token failed: %v", err)
+		ctx.Error(err)
+		return
+	}
+
+	log.Printf("[handler:auth] second factor accepted for userID=%d", userId)
+
+	ah.issueTokens(ctx, user, req.DeviceName)
+}
+
+// EnrollTOTP godoc
+// @Summary      Подключить TOTP
+// @Description  Создаёт секрет для приложения-аутентификатора и возвращает его вместе с otpauth URI для QR-кода. Второй фактор начнёт действовать после подтверждения кодом
+// @Tags         auth
+// @Produce      json
+// @Param        Authorization header string true "JWT Access token"
+// @Success      200 {object} dto.TOTPEnrollmentResponse "Секрет и otpauth URI"
+// @Failure      401 {object} dto.ErrorResponse          "Неавторизован"
+// @Failure      409 {object} dto.ErrorResponse          "TOTP уже включён"
+// @Failure      500 {object} dto.ErrorResponse          "Внутренняя ошибка сервера"
+// @Router       /auth/mfa/totp [post]
+func (ah *Handler) EnrollTOTP(ctx *gin.Context) {
+	log.Printf("[handler:auth] EnrollTOTP called")
+
+	userId := ctx.GetInt("userId")
+
+	secret, uri, err := ah.AuthService.BeginTOTPEnrollment(ctx, userId, ctx.GetString("userEmail"))
+	if err != nil {
+		log.Printf("[handler:auth][ERROR] BeginTOTPEnrollment failed: %v", err)
+		ctx.Error(err)
+		return
+	}
+
+	log.Printf("[handler:auth] EnrollTOTP succeeded for userID=%d", userId)
+	ctx.JSON(http.StatusOK, dto.TOTPEnrollmentResponse{
+		Secret:     secret,
+		OtpauthURI: uri,
+	})
+}
+
+// ConfirmTOTP godoc
+// @Summary      Подтвердить TOTP
+// @Description  Включает двухфакторную аутентификацию по коду из приложения и возвращает одноразовые коды восстановления. Коды показываются только один раз
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        Authorization header string                 true "JWT Access token"
+// @Param        request       body   dto.TOTPConfirmRequest true "Код из приложения"
+// @Success      200 {object} dto.RecoveryCodesResponse "Коды восстановления"
+// @Failure      400 {object} dto.ErrorResponse         "Неверный код или подключение не начато"
+// @Failure      401 {object} dto.ErrorResponse         "Неавторизован"
+// @Failure      409 {object} dto.ErrorResponse         "TOTP уже включён"
+// @Failure      500 {object} dto.ErrorResponse         "Внутренняя ошибка сервера"
+// @Router       /auth/mfa/totp/confirm [post]
+func (ah *Handler) ConfirmTOTP(ctx *gin.Context) {
+	log.Printf("[handler:auth] ConfirmTOTP called")
+
+	userId := ctx.GetInt("userId")
+
+	var req dto.TOTPConfirmRequest
+	if err := bind.JSON(ctx, &req); err != nil {
+		log.Printf("[handler:auth][ERROR] bind body: %v", err)
+		ctx.Error(err)
+		return
+	}
+
+	codes, err := ah.AuthService.ConfirmTOTP(ctx, userId, req.Code, ctx.ClientIP(), ctx.Request.UserAgent())
+	if err != nil {
+		log.Printf("[handler:auth][ERROR] ConfirmTOTP failed: %v", err)
+		ctx.Error(err)
+		return
+	}
+
+	log.Printf("[handler:auth] ConfirmTOTP succeeded for userID=%d", userId)
+	ctx.JSON(http.StatusOK, dto.RecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+// DisableTOTP godoc
+// @Summary      Выключить TOTP
+// @Description  Выключает двухфакторную аутентификацию и удаляет коды восстановления. Нужен действующий код TOTP или код восстановления
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        Authorization header string                 true "JWT Access token"
+// @Param        request       body   dto.TOTPDisableRequest true "Код TOTP или код восстановления"
+// @Success      204 "TOTP выключен"
+// @Failure      400 {object} dto.ErrorResponse "Неверный код или TOTP не включён"
+// @Failure      401 {object} dto.ErrorResponse "Неавторизован"
+// @Failure      429 {object} dto.ErrorResponse "Слишком много неудачных попыток"
+// @Failure      500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
+// @Router       /auth/mfa/totp [delete]
+func (ah *Handler) DisableTOTP(ctx *gin.Context) {
+	log.Printf("[handler:auth] DisableTOTP called")
+
+	userId := ctx.GetInt("userId")
+
+	var req dto.TOTPDisableRequest
+	if err := bind.JSON(ctx, &req); err != nil {
+		log.Printf("[handler:auth][ERROR] bind body: %v", err)
+		ctx.Error(err)
+		return
+	}
+
+	err := ah.AuthService.DisableTOTP(ctx, userId, req.Code, req.RecoveryCode, ctx.ClientIP(), ctx.Request.UserAgent())
+	if err != nil {
+		log.Printf("[handler:auth][ERROR] DisableTOTP failed: %v", err)
+		ctx.Error(err)
+		return
+	}
+
+	log.Printf("[handler:auth] DisableTOTP succeeded for userID=%d", userId)
+	ctx.Status(http.StatusNoContent)
+}
@@ -1,36 +1,75 @@
 package auth
 
 import (
+	"errors"
 	"fmt"
 	"log"
-	"net/http"
 	"strconv"
 	"strings"
 
+	apiKeyEntity "github.com/1URose/marketplace/internal/auth_signup/domain/api_key/entity"
+	redisRepo "github.com/1URose/marketplace/internal/auth_signup/domain/redis/repository"
+	"github.com/1URose/marketplace/internal/auth_signup/use_cases"
+	"github.com/1URose/marketplace/internal/common/apperror"
 	"github.com/1URose/marketplace/internal/common/jwt"
+	userEntity "github.com/1URose/marketplace/internal/user_profile/domain/user/entity"
+	userRepo "github.com/1URose/marketplace/internal/user_profile/domain/user/repository"
 	"github.com/gin-gonic/gin"
 )
 
+// APIKeyHeader — альтернатива Authorization для API-ключей.
+const APIKeyHeader = "X-API-Key"
+
+var (
+	// errScopeDenied — запрос с API-ключом на маршрут, к которому ключ не допущен.
+	errScopeDenied      = apperror.Forbidden("auth.insufficient_scope")
+	errUnauthorized     = apperror.Unauthorized("auth.unauthorized")
+	errForbidden        = apperror.Forbidden("auth.forbidden")
+	errEmailNotVerified = apperror.Forbidden("auth.email_not_verified")
+)
+
 type Middleware struct {
 	BearerPrefix string
 	jwtManager   *jwt.Manager
+	sessions     redisRepo.RedisRepository
+	apiKeys      *use_cases.APIKeyService
+	users        userRepo.UserRepository
 }
 
-func NewMiddleware(bearerPrefix string, jwtManager *jwt.Manager) *Middleware {
+func NewMiddleware(
+	bearerPrefix string,
+	jwtManager *jwt.Manager,
+	sessions redisRepo.RedisRepository,
+	apiKeys *use_cases.APIKeyService,
+	users userRepo.UserRepository,
+) *Middleware {
 	log.Println("[middleware:auth] NewMiddleware initialized")
+
 	return &Middleware{
 		BearerPrefix: bearerPrefix,
 		jwtManager:   jwtManager,
+		sessions:     sessions,
+		apiKeys:      apiKeys,
+		users:        users,
 	}
 }
 
-func (m *Middleware) Optional() gin.HandlerFunc {
+// Optional пропускает и анонимные запросы. API-ключи принимаются, только если группе маршрутов
+// заданы scopes и ключу выданы они все.
+func (m *Middleware) Optional(scopes ...string) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		log.Printf("[middleware:auth] OptionalAuth called: path=%s method=%s",
 			ctx.Request.URL.Path, ctx.Request.Method,
 		)
 
-		if err := m.parseAndSetClaims(ctx, m.jwtManager); err != nil {
+		err := m.authenticate(ctx, scopes)
+		if errors.Is(err, errScopeDenied) {
+			log.Printf("[middleware:auth][ERROR] OptionalAuth: %v", err)
+			abort(ctx, err)
+			return
+		}
+
+		if err != nil {
 			log.Printf("[middleware:auth][INFO] OptionalAuth: unauthenticated user (%v)", err)
 			ctx.Set("isAuthenticated", false)
 		} else {
@@ -43,15 +82,24 @@ func (m *Middleware) Optional() gin.HandlerFunc {
 	}
 }
 
-func (m *Middleware) Require() gin.HandlerFunc {
+// Require пропускает только аутентифицированные запросы. API-ключи принимаются, только если группе маршрутов
+// заданы scopes и ключу выданы они все; маршруты без scopes доступны лишь по JWT.
+func (m *Middleware) Require(scopes ...string) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		log.Printf("[middleware:auth] RequireAuth called: path=%s method=%s",
 			ctx.Request.URL.Path, ctx.Request.Method,
 		)
 
-		if err := m.parseAndSetClaims(ctx, m.jwtManager); err != nil {
+		err := m.authenticate(ctx, scopes)
+		if errors.Is(err, errScopeDenied) {
+			log.Printf("[middleware:auth][ERROR] RequireAuth: %v", err)
+			abort(ctx, err)
+			return
+		}
+
+		if err != nil {
 			log.Printf("[middleware:auth][ERROR] RequireAuth failed: %v", err)
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			abort(ctx, errUnauthorized.Wrap(err))
 			return
 		}
 
@@ -60,6 +108,97 @@ func (m *Middleware) Require() gin.HandlerFunc {
 	}
 }
 
+// RequireAdmin — RequireRole для роли администратора.
+func (m *Middleware) RequireAdmin() gin.HandlerFunc {
+	return m.RequireRole(userEntity.RoleAdmin)
+}
+
+// RequireRole пропускает только пользователей с одной из ролей. Роль берётся из access-токена,
+// поэтому API-ключи здесь не принимаются.
+func (m *Middleware) RequireRole(roles ...string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		log.Printf("[middleware:auth] RequireRole called: path=%s method=%s roles=%v",
+			ctx.Request.URL.Path, ctx.Request.Method, roles,
+		)
+
+		if err := m.parseAndSetClaims(ctx, m.jwtManager); err != nil {
+			log.Printf("[middleware:auth][ERROR] RequireRole failed: %v", err)
+			abort(ctx, errUnauthorized.Wrap(err))
+			return
+		}
+
+		role := ctx.GetString("userRole")
+		for _, allowed := range roles {
+			if role == allowed {
+				log.Printf("[middleware:auth] RequireRole: authenticated userId=%d role=%s", ctx.GetInt("userId"), role)
+				ctx.Next()
+				return
+			}
+		}
+
+		log.Printf("[middleware:auth][ERROR] RequireRole: userId=%d has role %q", ctx.GetInt("userId"), role)
+		abort(ctx, errForbidden)
+	}
+}
+
+// RequireVerifiedEmail ставится после Require и пропускает только пользователей с подтверждённым email.
+// Флаг читается из базы, а не из токена, чтобы подтверждение действовало сразу.
+func (m *Middleware) RequireVerifiedEmail() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		uid := ctx.GetInt("userId")
+
+		log.Printf("[middleware:auth] RequireVerifiedEmail called: userId=%d", uid)
+
+		user, err := m.users.GetUserByID(ctx, uid)
+		if err != nil {
+			log.Printf("[middleware:auth][ERROR] RequireVerifiedEmail: get user by id: %v", err)
+			abort(ctx, err)
+			return
+		}
+
+		if user == nil || !user.IsEmailVerified() {
+			log.Printf("[middleware:auth][ERROR] RequireVerifiedEmail: userId=%d has unverified email", uid)
+			abort(ctx, errEmailNotVerified)
+			return
+		}
+
+		ctx.Next()
+	}
+}
+
+// authenticate принимает API-ключ из X-API-Key или Authorization либо JWT из Authorization.
+func (m *Middleware) authenticate(ctx *gin.Context, scopes []string) error {
+	if key := ctx.GetHeader(APIKeyHeader); key != "" {
+		return m.setAPIKey(ctx, key, scopes)
+	}
+	if token := m.bearerToken(ctx); apiKeyEntity.IsAPIKey(token) {
+		return m.setAPIKey(ctx, token, scopes)
+	}
+	return m.parseAndSetClaims(ctx, m.jwtManager)
+}
+
+func (m *Middleware) bearerToken(ctx *gin.Context) string {
+	return strings.TrimPrefix(ctx.GetHeader("Authorization"), m.BearerPrefix)
+}
+
+func (m *Middleware) setAPIKey(ctx *gin.Context, raw string, scopes []string) error {
+	key, user, err := m.apiKeys.Authenticate(ctx, raw)
+	if err != nil {
+		return err
+	}
+
+	if len(scopes) == 0 || !key.HasScopes(scopes...) {
+		return errScopeDenied.Wrap(fmt.Errorf("keyID=%d has %v, route requires %v", key.ID, key.Scopes, scopes))
+	}
+
+	ctx.Set("userId", user.ID)
+	ctx.Set("userEmail", user.Email)
+	ctx.Set("apiKeyId", key.ID)
+	// ключ не даёт прав модератора или администратора, даже если его выпустил администратор
+	setRole(ctx, userEntity.RoleUser)
+	return nil
+}
+
 func (m *Middleware) parseAndSetClaims(ctx *gin.Context, jwtManager *jwt.Manager) error {
 	raw := ctx.GetHeader("Authorization")
 	if raw == "" {
@@ -74,14 +213,46 @@ func (m *Middleware) parseAndSetClaims(ctx *gin.Context, jwtManager *jwt.Manager
 		log.Printf("[middleware:auth] parseAndSetClaims: no Bearer prefix, using raw token")
 	}
 
-	claims, err := jwtManager.ValidateAccessToken(token)
+	claims, err := jwtManager.ValidateAccessToken(ctx, token)
 	if err != nil {
 		return err
 	}
 
-	if uid, err := strconv.Atoi(claims.Subject); err == nil {
-		ctx.Set("userId", uid)
+	uid, err := strconv.Atoi(claims.Subject)
+	if err != nil {
+		return fmt.Errorf("invalid subject %q: %w", claims.Subject, err)
+	}
+
+	// токены закрытых сессий уже отклонены в ValidateAccessToken: sid сверяется с denylist в Redis одним запросом,
+	// без кэша на узле. Сессия и tokens-valid-after читаются только для токенов без sid, выданных до появления
+	// сессий устройств
+	if claims.SessionID == "" {
+		if err := use_cases.CheckNotRevoked(ctx, m.sessions, uid, claims); err != nil {
+			return err
+		}
 	}
+
+	ctx.Set("userId", uid)
 	ctx.Set("userEmail", claims.Email)
+	ctx.Set("sessionId", claims.SessionID)
+
+	role := claims.Role
+	if role == "" {
+		// токены, выданные до появления ролей
+		role = userEntity.RoleUser
+	}
+	setRole(ctx, role)
 	return nil
 }
+
+// abort прерывает цепочку обработчиков; ответ по ошибке формирует middleware.ErrorHandler.
+func abort(ctx *gin.Context, err error) {
+	ctx.Error(err)
+	ctx.Abort()
+}
+
+func setRole(ctx *gin.Context, role string) {
+	ctx.Set("userRole", role)
+	ctx.Set("isAdmin", role == userEntity.RoleAdmin)
+	ctx.Set("isModerator", userEntity.IsModeratorRole(role))
+}
@@ -0,0 +1,84 @@
+package auth
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/1URose/marketplace/internal/common/apperror"
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	errProviderRejected   = apperror.Unauthorized("oidc.provider_rejected")
+	errMissingCodeOrState = apperror.Validation("oidc.missing_code_or_state")
+)
+
+// OIDCLogin godoc
+// @Summary      Вход через внешнего провайдера
+// @Description  Перенаправляет на страницу входа OpenID Connect провайдера (authorization code flow с PKCE). После входа провайдер возвращает пользователя на /auth/oidc/{provider}/callback
+// @Tags         auth
+// @Param        provider     path   string  true   "Имя провайдера из OIDC_PROVIDERS"
+// @Param        device_name  query  string  false  "Название устройства для новой сессии"
+// @Success      302 "Перенаправление к провайдеру"
+// @Failure      404 {object} dto.ErrorResponse "Провайдер не настроен"
+// @Failure      500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
+// @Router       /auth/oidc/{provider}/login [get]
+func (ah *Handler) OIDCLogin(ctx *gin.Context) {
+	provider := ctx.Param("provider")
+
+	log.Printf("[handler:auth] OIDCLogin called: provider=%s", provider)
+
+	authURL, err := ah.OIDCService.BeginLogin(ctx, provider, ctx.Query("device_name"))
+	if err != nil {
+		log.Printf("[handler:auth][ERROR] BeginLogin failed: %v", err)
+		ctx.Error(err)
+		return
+	}
+
+	ctx.Redirect(http.StatusFound, authURL)
+}
+
+// OIDCCallback godoc
+// @Summary      Возврат от внешнего провайдера
+// @Description  Обменивает код авторизации на id_token, проверяет подпись, state и nonce. Внешняя учётная запись привязывается к пользователю с тем же email, если адрес подтвердили и провайдер, и сам пользователь, либо создаётся новый пользователь без пароля. Ответ такой же, как у POST /auth/login
+// @Tags         auth
+// @Produce      json
+// @Param        provider  path   string  true  "Имя провайдера"
+// @Param        code      query  string  true  "Код авторизации"
+// @Param        state     query  string  true  "state из запроса входа"
+// @Success      200 {object} dto.LoginResponse "Access и Refresh токены"
+// @Success      200 {object} dto.MFAChallengeResponse "Требуется второй фактор"
+// @Failure      400 {object} dto.ErrorResponse "Неверный запрос"
+// @Failure      401 {object} dto.ErrorResponse "Провайдер отказал во входе, state или id_token недействительны"
+// @Failure      403 {object} dto.ErrorResponse "Email не подтверждён провайдером или пользователем маркетплейса, либо пользователь заблокирован"
+// @Failure      404 {object} dto.ErrorResponse "Провайдер не настроен"
+// @Failure      500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
+// @Router       /auth/oidc/{provider}/callback [get]
+func (ah *Handler) OIDCCallback(ctx *gin.Context) {
+	provider := ctx.Param("provider")
+
+	log.Printf("[handler:auth] OIDCCallback called: provider=%s", provider)
+
+	if providerErr := ctx.Query("error"); providerErr != "" {
+		log.Printf("[handler:auth][ERROR] provider %s returned error %q", provider, providerErr)
+		ctx.Error(errProviderRejected.WithDetail(providerErr))
+		return
+	}
+
+	code, state := ctx.Query("code"), ctx.Query("state")
+	if code == "" || state == "" {
+		ctx.Error(errMissingCodeOrState)
+		return
+	}
+
+	user, deviceName, err := ah.OIDCService.CompleteLogin(ctx, provider, code, state)
+	if err != nil {
+		log.Printf("[handler:auth][ERROR] CompleteLogin failed: %v", err)
+		ctx.Error(err)
+		return
+	}
+
+	log.Printf("[handler:auth] external login succeeded: provider=%s userID=%d", provider, user.ID)
+
+	ah.completeLogin(ctx, user, deviceName)
+}
@@ -0,0 +1,67 @@
+package auth
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/1URose/marketplace/internal/auth_signup/transport/rest/auth/dto"
+	"github.com/1URose/marketplace/internal/common/transport/rest/bind"
+	"github.com/gin-gonic/gin"
+)
+
+// ForgotPassword godoc
+// @Summary      Запрос сброса пароля
+// @Description  Отправляет на email ссылку для сброса пароля. Ответ всегда 202, даже если такого пользователя нет, чтобы запрос нельзя было использовать для поиска учётных записей
+// @Tags         auth
+// @Accept       json
+// @Param        request body dto.ForgotPasswordRequest true "Email учётной записи"
+// @Success      202 "Запрос принят"
+// @Failure      400 {object} dto.ErrorResponse "Неверный запрос"
+// @Router       /auth/password/forgot [post]
+func (ah *Handler) ForgotPassword(ctx *gin.Context) {
+	log.Printf("[handler:auth] ForgotPassword called")
+
+	var req dto.ForgotPasswordRequest
+	if err := bind.JSON(ctx, &req); err != nil {
+		log.Printf("[handler:auth][ERROR] bind body: %v", err)
+		ctx.Error(err)
+		return
+	}
+
+	// сбой отправки тоже не раскрывается: ответ не должен отличаться для существующих и несуществующих адресов
+	if err := ah.PasswordReset.Forgot(ctx, req.Email); err != nil {
+		log.Printf("[handler:auth][ERROR] ForgotPassword failed: %v", err)
+	}
+
+	ctx.Status(http.StatusAccepted)
+}
+
+// ResetPassword godoc
+// @Summary      Сброс пароля
+// @Description  Задаёт новый пароль по токену из письма. Токен одноразовый и действует PASSWORD_RESET_TTL_MINUTES; после сброса все сессии пользователя закрываются, а на email приходит уведомление
+// @Tags         auth
+// @Accept       json
+// @Param        request body dto.ResetPasswordRequest true "Токен из письма и новый пароль"
+// @Success      204 "Пароль изменён"
+// @Failure      400 {object} dto.ErrorResponse "Неверный, истёкший или уже использованный токен либо пароль нарушает политику (violations)"
+// @Failure      500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
+// @Router       /auth/password/reset [post]
+func (ah *Handler) ResetPassword(ctx *gin.Context) {
+	log.Printf("[handler:auth] ResetPassword called")
+
+	var req dto.ResetPasswordRequest
+	if err := bind.JSON(ctx, &req); err != nil {
+		log.Printf("[handler:auth][ERROR] bind body: %v", err)
+		ctx.Error(err)
+		return
+	}
+
+	err := ah.PasswordReset.Reset(ctx, req.Token, req.Password, ctx.ClientIP(), ctx.Request.UserAgent())
+	if err != nil {
+		log.Printf("[handler:auth][ERROR] ResetPassword failed: %v", err)
+		ctx.Error(passwordError(err, "password"))
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
@@ -0,0 +1,83 @@
+package auth
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/1URose/marketplace/internal/auth_signup/domain/security_event/entity"
+	"github.com/1URose/marketplace/internal/auth_signup/transport/rest/auth/dto"
+	"github.com/1URose/marketplace/internal/common/transport/rest/bind"
+	"github.com/gin-gonic/gin"
+)
+
+// ListMySecurityEvents godoc
+// @Summary      Журнал безопасности
+// @Description  Возвращает события безопасности текущего пользователя, начиная с самых новых: входы и неудачные попытки, обновления токенов, выходы, смены пароля и роли — с IP, user agent и ID сессии
+// @Tags         account
+// @Produce      json
+// @Security     BearerAuth
+// @Param        page query int false "Номер страницы" default(1)
+// @Success      200 {object} dto.SecurityEventsResponse "События и количество страниц"
+// @Failure      400 {object} dto.ErrorResponse "Неверные параметры запроса"
+// @Failure      401 {object} dto.ErrorResponse "Не авторизован"
+// @Failure      500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
+// @Router       /me/security-events [get]
+func (ah *Handler) ListMySecurityEvents(ctx *gin.Context) {
+	userId := ctx.GetInt("userId")
+
+	log.Printf("[handler:auth] ListMySecurityEvents called: userID=%d", userId)
+
+	var req dto.SecurityEventsRequest
+	if err := bind.Query(ctx, &req); err != nil {
+		log.Printf("[handler:auth][ERROR] bind query: %v", err)
+		ctx.Error(err)
+		return
+	}
+
+	events, countPages, err := ah.SecurityEvents.List(ctx, &entity.Filter{UserID: &userId}, req.Page)
+	if err != nil {
+		log.Printf("[handler:auth][ERROR] ListMySecurityEvents failed: %v", err)
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.NewSecurityEventsResponse(events, countPages))
+}
+
+// ListSecurityEvents godoc
+// @Summary      Журнал безопасности (администраторы)
+// @Description  Возвращает события безопасности всех пользователей, начиная с самых новых. Фильтры необязательны и объединяются через AND; type можно передать несколько раз. Доступно только администраторам
+// @Tags         admin
+// @Produce      json
+// @Security     BearerAuth
+// @Param        page    query int      false "Номер страницы" default(1)
+// @Param        user_id query int      false "ID пользователя"
+// @Param        type    query []string false "Тип события" collectionFormat(multi)
+// @Param        ip      query string   false "IP-адрес"
+// @Param        from    query string   false "Не раньше этого момента, RFC 3339"
+// @Param        to      query string   false "Раньше этого момента, RFC 3339"
+// @Success      200 {object} dto.SecurityEventsResponse "События и количество страниц"
+// @Failure      400 {object} dto.ErrorResponse "Неверные параметры запроса или неизвестный тип события"
+// @Failure      401 {object} dto.ErrorResponse "Не авторизован"
+// @Failure      403 {object} dto.ErrorResponse "Недостаточно прав"
+// @Failure      500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
+// @Router       /admin/security-events [get]
+func (ah *Handler) ListSecurityEvents(ctx *gin.Context) {
+	log.Printf("[handler:auth] ListSecurityEvents called by adminID=%d", ctx.GetInt("userId"))
+
+	var req dto.AdminSecurityEventsRequest
+	if err := bind.Query(ctx, &req); err != nil {
+		log.Printf("[handler:auth][ERROR] bind query: %v", err)
+		ctx.Error(err)
+		return
+	}
+
+	events, countPages, err := ah.SecurityEvents.List(ctx, req.Filter(), req.Page)
+	if err != nil {
+		log.Printf("[handler:auth][ERROR] ListSecurityEvents failed: %v", err)
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.NewSecurityEventsResponse(events, countPages))
+}
@@ -2,58 +2,194 @@ package routers
 
 import (
 	"context"
+	attemptEntity "github.com/1URose/marketplace/internal/auth_signup/domain/login_attempt/entity"
+	oidcRepo "github.com/1URose/marketplace/internal/auth_signup/domain/oidc/repository"
+	oidcProvider "github.com/1URose/marketplace/internal/auth_signup/infrastructure/oidc"
+	authPostgres "github.com/1URose/marketplace/internal/auth_signup/infrastructure/repository/postgresql"
 	"github.com/1URose/marketplace/internal/auth_signup/infrastructure/repository/redis"
 	"github.com/1URose/marketplace/internal/auth_signup/transport/rest/auth"
 	"github.com/1URose/marketplace/internal/auth_signup/use_cases"
 	"github.com/1URose/marketplace/internal/common/app"
+	"github.com/1URose/marketplace/internal/common/clock"
+	"github.com/1URose/marketplace/internal/common/config/common"
+	oidcConfig "github.com/1URose/marketplace/internal/common/config/oidc"
 	"github.com/1URose/marketplace/internal/common/db"
 	"github.com/1URose/marketplace/internal/common/jwt"
+	"github.com/1URose/marketplace/internal/common/mailer"
+	"github.com/1URose/marketplace/internal/common/password"
 	"github.com/1URose/marketplace/internal/user_profile/infrastructure/repository/postgresql"
 	"github.com/gin-gonic/gin"
 	"log"
+	"time"
 )
 
 type AuthRouter struct {
-	engine      *gin.Engine
-	connections *db.Connections
-	jwtMgr      *jwt.Manager
-	ctx         context.Context
+	engine         *gin.Engine
+	connections    *db.Connections
+	jwtMgr         *jwt.Manager
+	ctx            context.Context
+	authMiddleware *auth.Middleware
+	revocationTTL  time.Duration
+	maxSessions    int
+	reuseGrace     time.Duration
+	throttle       attemptEntity.ThrottlePolicy
+	mfaIssuer      string
+	oidc           *oidcConfig.Config
+	mailer         mailer.Mailer
+	verification   common.EmailVerification
+	passwordReset  common.PasswordReset
+	passwordPolicy *password.Policy
+	magicLink      common.MagicLink
+	securityEvents common.SecurityEvents
 }
 
 func NewAuthRouter(deps *app.Deps) *AuthRouter {
 	log.Println("[routers:auth] initializing AuthRouter")
 
 	return &AuthRouter{
-		engine:      deps.Engine,
-		connections: deps.DB,
-		jwtMgr:      deps.JWTManager,
-		ctx:         deps.Ctx,
+		engine:         deps.Engine,
+		connections:    deps.DB,
+		jwtMgr:         deps.JWTManager,
+		ctx:            deps.Ctx,
+		authMiddleware: deps.AuthMiddleware,
+		revocationTTL:  deps.GeneralConfig.CommonConfig.RefreshTTL,
+		maxSessions:    deps.GeneralConfig.CommonConfig.MaxSessionsPerUser,
+		reuseGrace:     deps.GeneralConfig.CommonConfig.RefreshReuseGrace,
+		throttle:       newThrottlePolicy(deps.GeneralConfig.CommonConfig.LoginThrottle),
+		mfaIssuer:      deps.GeneralConfig.CommonConfig.MFAIssuer,
+		oidc:           deps.GeneralConfig.OIDCConfig,
+		mailer:         deps.Mailer,
+		verification:   deps.GeneralConfig.CommonConfig.EmailVerification,
+		passwordReset:  deps.GeneralConfig.CommonConfig.PasswordReset,
+		passwordPolicy: deps.PasswordPolicy,
+		magicLink:      deps.GeneralConfig.CommonConfig.MagicLink,
+		securityEvents: deps.GeneralConfig.CommonConfig.SecurityEvents,
 	}
 }
 
-func initRedisServer(connections *db.Connections) *use_cases.AuthService {
+func newThrottlePolicy(cfg common.LoginThrottle) attemptEntity.ThrottlePolicy {
+	return attemptEntity.ThrottlePolicy{
+		FailureWindow:   cfg.FailureWindow,
+		BackoffBase:     cfg.BackoffBase,
+		BackoffMax:      cfg.BackoffMax,
+		LockoutDuration: cfg.LockoutDuration,
+		Account: attemptEntity.Threshold{
+			BackoffAfter: cfg.AccountBackoffAfter,
+			LockoutAfter: cfg.AccountLockoutAfter,
+		},
+		IP: attemptEntity.Threshold{
+			BackoffAfter: cfg.IPBackoffAfter,
+			LockoutAfter: cfg.IPLockoutAfter,
+		},
+	}
+}
+
+func initRedisServer(
+	connections *db.Connections,
+	revocationTTL time.Duration,
+	maxSessions int,
+	reuseGrace time.Duration,
+	throttle attemptEntity.ThrottlePolicy,
+	mfaIssuer string,
+	passwordPolicy *password.Policy,
+	jwtMgr *jwt.Manager,
+) *use_cases.AuthService {
 
 	log.Println("[routers:auth] initializing AuthService with Redis and Postgres repositories")
 
-	redisR := redis.NewRedisRepository(connections.RedisConn)
+	redisR := redis.NewRedisRepository(connections.RedisConn, revocationTTL)
 
 	userR := postgresql.NewUserRepository(connections.PostgresConn)
 
-	svc := use_cases.NewAccountService(redisR, userR)
+	eventsR := authPostgres.NewSecurityEventRepository(connections.PostgresConn)
+
+	attemptsR := redis.NewLoginAttemptRepository(connections.RedisConn)
+
+	mfaR := authPostgres.NewMFARepository(connections.PostgresConn)
+
+	svc := use_cases.NewAccountService(
+		redisR, userR, revocationTTL, maxSessions, reuseGrace, eventsR, attemptsR, throttle, mfaR, mfaIssuer, passwordPolicy, jwtMgr,
+	)
 
 	log.Println("[routers:auth] AuthService initialized")
 
 	return svc
 }
 
+func initOIDCService(connections *db.Connections, cfg *oidcConfig.Config) *use_cases.OIDCService {
+
+	log.Println("[routers:auth] initializing OIDCService")
+
+	providers := make([]oidcRepo.IdentityProvider, 0, len(cfg.Providers))
+	for _, p := range cfg.Providers {
+		providers = append(providers, oidcProvider.NewProvider(p))
+	}
+
+	requestsR := redis.NewOIDCRequestRepository(connections.RedisConn)
+
+	identitiesR := authPostgres.NewIdentityRepository(connections.PostgresConn)
+
+	userR := postgresql.NewUserRepository(connections.PostgresConn)
+
+	return use_cases.NewOIDCService(providers, requestsR, identitiesR, userR, cfg.StateTTL)
+}
+
+func initEmailVerificationService(
+	auth *use_cases.AuthService,
+	connections *db.Connections,
+	jwtMgr *jwt.Manager,
+	m mailer.Mailer,
+	cfg common.EmailVerification,
+) *use_cases.EmailVerificationService {
+
+	log.Println("[routers:auth] initializing EmailVerificationService")
+
+	resendsR := redis.NewVerificationResendRepository(connections.RedisConn)
+
+	userR := postgresql.NewUserRepository(connections.PostgresConn)
+
+	return use_cases.NewEmailVerificationService(auth, userR, jwtMgr, m, resendsR, cfg)
+}
+
 func (ar *AuthRouter) RegisterRoutes() {
 
 	log.Println("[routers:auth] registering /auth endpoints")
 
 	apiGroup := ar.engine.Group("/auth")
 
-	service := initRedisServer(ar.connections)
-	handler := auth.NewAuthHandler(service, ar.jwtMgr)
+	service := initRedisServer(
+		ar.connections, ar.revocationTTL, ar.maxSessions, ar.reuseGrace, ar.throttle, ar.mfaIssuer, ar.passwordPolicy, ar.jwtMgr,
+	)
+	oidcService := initOIDCService(ar.connections, ar.oidc)
+	apiKeyService := use_cases.NewAPIKeyService(
+		authPostgres.NewAPIKeyRepository(ar.connections.PostgresConn),
+		postgresql.NewUserRepository(ar.connections.PostgresConn),
+	)
+	verificationService := initEmailVerificationService(service, ar.connections, ar.jwtMgr, ar.mailer, ar.verification)
+	passwordResetService := use_cases.NewPasswordResetService(
+		service,
+		redis.NewPasswordResetRepository(ar.connections.RedisConn),
+		ar.mailer,
+		ar.passwordReset,
+	)
+	magicLinkService := use_cases.NewMagicLinkService(
+		postgresql.NewUserRepository(ar.connections.PostgresConn),
+		ar.jwtMgr,
+		ar.mailer,
+		redis.NewMagicLinkRepository(ar.connections.RedisConn),
+		ar.magicLink,
+	)
+	securityEventService := use_cases.NewSecurityEventService(
+		authPostgres.NewSecurityEventRepository(ar.connections.PostgresConn),
+		ar.securityEvents,
+		clock.NewSystem(),
+	)
+	go securityEventService.Run(ar.ctx)
+
+	handler := auth.NewAuthHandler(
+		service, oidcService, apiKeyService, verificationService, passwordResetService, magicLinkService,
+		securityEventService, ar.jwtMgr,
+	)
 
 	{
 
@@ -61,8 +197,86 @@ func (ar *AuthRouter) RegisterRoutes() {
 
 		apiGroup.POST("/login/", handler.Login)
 
+		apiGroup.POST("/login/mfa", handler.LoginMFA)
+
+		apiGroup.POST("/magic-link", handler.RequestMagicLink)
+
+		apiGroup.POST("/magic-link/consume", handler.ConsumeMagicLink)
+
 		apiGroup.POST("/refresh", handler.Refresh)
 
+		apiGroup.POST("/verify-email", handler.VerifyEmail)
+
+		apiGroup.POST("/resend-verification", ar.authMiddleware.Require(), handler.ResendVerification)
+
+		apiGroup.POST("/password/forgot", handler.ForgotPassword)
+
+		apiGroup.POST("/password/reset", handler.ResetPassword)
+
+		apiGroup.POST("/logout", ar.authMiddleware.Require(), handler.Logout)
+
+		apiGroup.POST("/logout-all", ar.authMiddleware.Require(), handler.LogoutAll)
+
+		apiGroup.GET("/sessions", ar.authMiddleware.Require(), handler.ListSessions)
+
+		apiGroup.DELETE("/sessions/:id", ar.authMiddleware.Require(), handler.RevokeSession)
+
+		apiGroup.POST("/mfa/totp", ar.authMiddleware.Require(), handler.EnrollTOTP)
+
+		apiGroup.POST("/mfa/totp/confirm", ar.authMiddleware.Require(), handler.ConfirmTOTP)
+
+		apiGroup.DELETE("/mfa/totp", ar.authMiddleware.Require(), handler.DisableTOTP)
+
+		apiGroup.POST("/api-keys", ar.authMiddleware.Require(), handler.CreateAPIKey)
+
+		apiGroup.GET("/api-keys", ar.authMiddleware.Require(), handler.ListAPIKeys)
+
+		apiGroup.DELETE("/api-keys/:id", ar.authMiddleware.Require(), handler.RevokeAPIKey)
+
+		apiGroup.GET("/oidc/:provider/login", handler.OIDCLogin)
+
+		apiGroup.GET("/oidc/:provider/callback", handler.OIDCCallback)
+
+	}
+
+	ar.engine.GET("/.well-known/jwks.json", handler.JWKS)
+
+	meApiGroup := ar.engine.Group("/me").Use(ar.authMiddleware.Require())
+
+	{
+
+		meApiGroup.PUT("/password", handler.ChangePassword)
+
+		meApiGroup.PUT("/email", handler.ChangeEmail)
+
+		meApiGroup.GET("/security-events", handler.ListMySecurityEvents)
+
+	}
+
+	adminApiGroup := ar.engine.Group("/admin/tokens").Use(ar.authMiddleware.RequireAdmin())
+
+	{
+
+		adminApiGroup.POST("/revoke", handler.RevokeTokens)
+
+	}
+
+	adminUsersApiGroup := ar.engine.Group("/admin/users").Use(ar.authMiddleware.RequireAdmin())
+
+	{
+
+		adminUsersApiGroup.POST("/:id/unlock-login", handler.UnlockLogin)
+
+		adminUsersApiGroup.PUT("/:id/role", handler.SetUserRole)
+
+	}
+
+	adminEventsApiGroup := ar.engine.Group("/admin/security-events").Use(ar.authMiddleware.RequireAdmin())
+
+	{
+
+		adminEventsApiGroup.GET("", handler.ListSecurityEvents)
+
 	}
 
 	log.Println("[routers:auth] all auth routers registered")
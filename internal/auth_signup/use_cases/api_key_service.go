@@ -0,0 +1,118 @@
+package use_cases
+
+import (
+	"context"
+	"github.com/1URose/marketplace/internal/auth_signup/domain/api_key/entity"
+	"github.com/1URose/marketplace/internal/auth_signup/domain/api_key/repository"
+	userEntity "github.com/1URose/marketplace/internal/user_profile/domain/user/entity"
+	userRepo "github.com/1URose/marketplace/internal/user_profile/domain/user/repository"
+	"log"
+	"time"
+)
+
+// APIKeyService выпускает и проверяет API-ключи, которыми интеграции ходят в API вместо пароля пользователя.
+type APIKeyService struct {
+	keys  repository.APIKeyRepository
+	users userRepo.UserRepository
+}
+
+func NewAPIKeyService(keys repository.APIKeyRepository, users userRepo.UserRepository) *APIKeyService {
+	log.Println("[api_key] initializing APIKeyService")
+
+	return &APIKeyService{
+		keys:  keys,
+		users: users,
+	}
+}
+
+// Create выпускает ключ и возвращает его открытое значение; повторно получить его нельзя.
+func (ks *APIKeyService) Create(ctx context.Context, userID int, name string, scopes []string, expiresAt *time.Time) (*entity.APIKey, string, error) {
+	log.Printf("[api_key] Create called: userID=%d scopes=%v", userID, scopes)
+
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		return nil, "", entity.ErrExpiryInPast
+	}
+
+	key, raw, err := entity.NewAPIKey(userID, name, scopes, expiresAt)
+	if err != nil {
+		log.Printf("[api_key][ERROR] new api key: %v", err)
+		return nil, "", err
+	}
+
+	if err := ks.keys.Create(ctx, key); err != nil {
+		log.Printf("[api_key][ERROR] save api key: %v", err)
+		return nil, "", err
+	}
+
+	log.Printf("[api_key] Create succesful: userID=%d keyID=%d prefix=%s", userID, key.ID, key.Prefix)
+	return key, raw, nil
+}
+
+func (ks *APIKeyService) List(ctx context.Context, userID int) ([]*entity.APIKey, error) {
+	log.Printf("[api_key] List called: userID=%d", userID)
+
+	keys, err := ks.keys.ListByUser(ctx, userID)
+	if err != nil {
+		log.Printf("[api_key][ERROR] list api keys: %v", err)
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+func (ks *APIKeyService) Revoke(ctx context.Context, userID, keyID int) error {
+	log.Printf("[api_key] Revoke called: userID=%d keyID=%d", userID, keyID)
+
+	deleted, err := ks.keys.Delete(ctx, userID, keyID)
+	if err != nil {
+		log.Printf("[api_key][ERROR] delete api key: %v", err)
+		return err
+	}
+	if !deleted {
+		return entity.ErrAPIKeyNotFound
+	}
+
+	log.Printf("[api_key] Revoke succesful: userID=%d keyID=%d", userID, keyID)
+	return nil
+}
+
+// Authenticate проверяет предъявленный ключ и возвращает его вместе с владельцем.
+// Любая причина отказа сводится к entity.ErrInvalidAPIKey.
+func (ks *APIKeyService) Authenticate(ctx context.Context, raw string) (*entity.APIKey, *userEntity.User, error) {
+	prefix, ok := entity.ParsePrefix(raw)
+	if !ok {
+		return nil, nil, entity.ErrInvalidAPIKey
+	}
+
+	key, err := ks.keys.GetByPrefix(ctx, prefix)
+	if err != nil {
+		log.Printf("[api_key][ERROR] get api key: %v", err)
+		return nil, nil, err
+	}
+	if key == nil || !key.Matches(raw) {
+		log.Printf("[api_key][ERROR] unknown api key prefix=%s", prefix)
+		return nil, nil, entity.ErrInvalidAPIKey
+	}
+
+	now := time.Now()
+	if key.IsExpired(now) {
+		log.Printf("[api_key][ERROR] api key expired: keyID=%d", key.ID)
+		return nil, nil, entity.ErrInvalidAPIKey
+	}
+
+	user, err := ks.users.GetUserByID(ctx, key.UserID)
+	if err != nil {
+		log.Printf("[api_key][ERROR] get key owner: %v", err)
+		return nil, nil, err
+	}
+	if user == nil || user.IsSuspended() {
+		log.Printf("[api_key][ERROR] key owner userID=%d is missing or suspended", key.UserID)
+		return nil, nil, entity.ErrInvalidAPIKey
+	}
+
+	if err := ks.keys.TouchLastUsed(ctx, key.ID, now); err != nil {
+		log.Printf("[api_key][ERROR] touch last used: %v", err)
+	}
+
+	return key, user, nil
+}
@@ -2,30 +2,90 @@ package use_cases
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	attemptEntity "github.com/1URose/marketplace/internal/auth_signup/domain/login_attempt/entity"
+	attemptRepo "github.com/1URose/marketplace/internal/auth_signup/domain/login_attempt/repository"
+	mfaRepo "github.com/1URose/marketplace/internal/auth_signup/domain/mfa/repository"
+	sessionEntity "github.com/1URose/marketplace/internal/auth_signup/domain/redis/entity"
 	redisRepo "github.com/1URose/marketplace/internal/auth_signup/domain/redis/repository"
+	eventEntity "github.com/1URose/marketplace/internal/auth_signup/domain/security_event/entity"
+	eventRepo "github.com/1URose/marketplace/internal/auth_signup/domain/security_event/repository"
 	"github.com/1URose/marketplace/internal/auth_signup/transport/rest/auth/dto"
+	"github.com/1URose/marketplace/internal/common/apperror"
+	jwtEntity "github.com/1URose/marketplace/internal/common/jwt/entity"
 	"github.com/1URose/marketplace/internal/common/password"
 	"github.com/1URose/marketplace/internal/user_profile/domain/user/entity"
 	userRepo "github.com/1URose/marketplace/internal/user_profile/domain/user/repository"
 	"log"
+	"time"
 )
 
+var (
+	ErrUserSuspended   = apperror.Forbidden("auth.user_suspended")
+	ErrTokenRevoked    = apperror.Unauthorized("auth.token_revoked")
+	ErrSessionNotFound = apperror.NotFound("auth.session_not_found")
+	// ErrRefreshTokenReused — предъявлен уже заменённый refresh-токен; семейство токенов отозвано.
+	ErrRefreshTokenReused = apperror.Unauthorized("auth.refresh_token_reused")
+	ErrInvalidCredentials = apperror.Unauthorized("auth.invalid_credentials")
+	// ErrInvalidCurrentPassword — пользователь уже вошёл, но не подтвердил операцию текущим паролем.
+	ErrInvalidCurrentPassword = apperror.Forbidden("auth.invalid_current_password")
+	ErrUserNotFound           = apperror.NotFound("auth.user_not_found")
+	ErrInvalidRole            = apperror.Validation("auth.invalid_role")
+	// ErrOwnRoleChange — администратор не может менять собственную роль, чтобы не остаться без администраторов.
+	ErrOwnRoleChange = apperror.Forbidden("auth.own_role_change")
+)
+
+// SessionTokenRevoker отзывает токены закрытой сессии, не дожидаясь их истечения; реализуется jwt.Manager.
+type SessionTokenRevoker interface {
+	RevokeSession(ctx context.Context, sessionID string) error
+}
+
 type AuthService struct {
 	RedisRepo redisRepo.RedisRepository
 	UserRepo  userRepo.UserRepository
+	// revoker закрывает сессии и вносит их в denylist: middleware проверяет по нему access-токены без чтения сессий.
+	revoker     *SessionRevoker
+	maxSessions int
+	reuseGrace  time.Duration
+	events      eventRepo.SecurityEventRepository
+	attempts    attemptRepo.LoginAttemptRepository
+	throttle    attemptEntity.ThrottlePolicy
+	mfa         mfaRepo.MFARepository
+	mfaIssuer   string
+	// passwordPolicy проверяет новые пароли при регистрации, сбросе и смене.
+	passwordPolicy *password.Policy
 }
 
 func NewAccountService(
 	redisRepo redisRepo.RedisRepository,
 	userRepo userRepo.UserRepository,
+	revocationTTL time.Duration,
+	maxSessions int,
+	reuseGrace time.Duration,
+	events eventRepo.SecurityEventRepository,
+	attempts attemptRepo.LoginAttemptRepository,
+	throttle attemptEntity.ThrottlePolicy,
+	mfa mfaRepo.MFARepository,
+	mfaIssuer string,
+	passwordPolicy *password.Policy,
+	tokens SessionTokenRevoker,
 ) *AuthService {
 
 	log.Println("[auth] initializing AuthService")
 
 	svc := &AuthService{
-		RedisRepo: redisRepo,
-		UserRepo:  userRepo,
+		RedisRepo:      redisRepo,
+		UserRepo:       userRepo,
+		maxSessions:    maxSessions,
+		reuseGrace:     reuseGrace,
+		events:         events,
+		attempts:       attempts,
+		throttle:       throttle,
+		mfa:            mfa,
+		mfaIssuer:      mfaIssuer,
+		passwordPolicy: passwordPolicy,
+		revoker:        NewSessionRevoker(redisRepo, tokens, events, revocationTTL),
 	}
 
 	log.Println("[auth] AuthService initialized")
@@ -33,7 +93,7 @@ func NewAccountService(
 	return svc
 }
 
-func (as *AuthService) SingUp(ctx context.Context, req dto.SignUpRequest) (*entity.User, error) {
+func (as *AuthService) SingUp(ctx context.Context, req dto.SignUpRequest, ip, userAgent string) (*entity.User, error) {
 	log.Printf("[auth] SingUp called: req=%+v", req)
 
 	exists, err := as.UserRepo.GetUserByEmail(ctx, req.Email)
@@ -43,7 +103,11 @@ func (as *AuthService) SingUp(ctx context.Context, req dto.SignUpRequest) (*enti
 	}
 	if exists != nil {
 		log.Printf("[auth][ERROR] user with email=%s already exists", req.Email)
-		return nil, fmt.Errorf("user with email=%s already exists", req.Email)
+		return nil, entity.ErrEmailTaken
+	}
+
+	if err := as.passwordPolicy.Validate(ctx, req.Password, req.Email); err != nil {
+		return nil, err
 	}
 
 	passwordHash, err := password.HashPassword(req.Password)
@@ -55,14 +119,26 @@ func (as *AuthService) SingUp(ctx context.Context, req dto.SignUpRequest) (*enti
 	user := entity.NewUser(req.Email, passwordHash)
 
 	createdUser, err := as.UserRepo.CreateUser(ctx, user)
+	if err != nil {
+		log.Printf("[auth][ERROR] create user: %v", err)
+		return nil, err
+	}
+
+	as.recordEvent(ctx, eventEntity.NewSecurityEvent(eventEntity.TypeSignUp, createdUser.ID, "", ip, userAgent, time.Now()))
 
 	log.Printf("[auth] SingUp succesful: user=%+v", createdUser)
 	return createdUser, nil
 }
 
-func (as *AuthService) Login(ctx context.Context, req dto.LoginRequest) (*entity.User, error) {
+// Login проверяет учётные данные. Пока вход для учётной записи или IP закрыт после неудачных попыток,
+// возвращается ErrLoginThrottled; каждая неудача увеличивает задержку вплоть до временной блокировки.
+func (as *AuthService) Login(ctx context.Context, req dto.LoginRequest, ip, userAgent string) (*entity.User, error) {
+
+	log.Printf("[auth] Email called: email=%s ip=%s", req.Email, ip)
 
-	log.Printf("[auth] Email called: req=%+v", req)
+	if err := as.checkLoginAllowed(ctx, req.Email, ip); err != nil {
+		return nil, err
+	}
 
 	existsUser, err := as.UserRepo.GetUserByEmail(ctx, req.Email)
 	if err != nil {
@@ -70,18 +146,261 @@ func (as *AuthService) Login(ctx context.Context, req dto.LoginRequest) (*entity
 		return nil, err
 	}
 
-	if existsUser == nil {
-		log.Printf("[auth][ERROR] user with email=%s not found", req.Email)
-		return nil, fmt.Errorf("user with email=%s not found", req.Email)
+	// неизвестный email считается такой же неудачей, чтобы перебор не отличал существующие учётные записи
+	if existsUser == nil || !password.CheckPasswordHash(req.Password, existsUser.PasswordHash) {
+		log.Printf("[auth][ERROR] invalid credentials for email=%s", req.Email)
+		if existsUser != nil {
+			as.recordEvent(ctx, eventEntity.NewSecurityEvent(eventEntity.TypeLoginFailed, existsUser.ID, "", ip, userAgent, time.Now()))
+		}
+		if err := as.registerLoginFailure(ctx, req.Email, ip, userAgent, existsUser); err != nil {
+			return nil, err
+		}
+		return nil, ErrInvalidCredentials
 	}
 
-	ok := password.CheckPasswordHash(req.Password, existsUser.PasswordHash)
-	if !ok {
-		log.Printf("[auth][ERROR] password verification failed")
-		return nil, fmt.Errorf("password verification failed")
+	if existsUser.IsSuspended() {
+		log.Printf("[auth][ERROR] user with email=%s is suspended", req.Email)
+		return nil, ErrUserSuspended
+	}
+
+	as.rehashPassword(ctx, existsUser, req.Password)
+
+	// счётчик IP не сбрасывается: иначе атакующий обнулял бы его входом в собственную учётную запись
+	if err := as.attempts.Reset(ctx, attemptEntity.AccountKey(req.Email)); err != nil {
+		log.Printf("[auth][ERROR] reset login failures: %v", err)
+		return nil, err
 	}
 
 	log.Printf("[auth] Email succesful")
 
 	return existsUser, nil
 }
+
+// rehashPassword пересчитывает хеш, созданный устаревшим алгоритмом или параметрами; открытый пароль
+// доступен только в момент входа. Ошибка не мешает входу: хеш пересчитается в следующий раз.
+func (as *AuthService) rehashPassword(ctx context.Context, user *entity.User, plain string) {
+	if !password.NeedsRehash(user.PasswordHash) {
+		return
+	}
+
+	hash, err := password.HashPassword(plain)
+	if err != nil {
+		log.Printf("[auth][ERROR] rehash password for userID=%d: %v", user.ID, err)
+		return
+	}
+	if err := as.UserRepo.UpdatePassword(ctx, user.ID, hash); err != nil {
+		log.Printf("[auth][ERROR] store rehashed password for userID=%d: %v", user.ID, err)
+		return
+	}
+
+	user.PasswordHash = hash
+	log.Printf("[auth] password hash upgraded for userID=%d", user.ID)
+}
+
+// CheckActive проверяет, что пользователь существует и не заблокирован, и возвращает его с актуальной ролью.
+func (as *AuthService) CheckActive(ctx context.Context, userID int) (*entity.User, error) {
+	log.Printf("[auth] CheckActive called: userID=%d", userID)
+
+	user, err := as.UserRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		log.Printf("[auth][ERROR] get user by id: %v", err)
+		return nil, err
+	}
+	if user == nil {
+		log.Printf("[auth][ERROR] user with id=%d not found", userID)
+		return nil, ErrUserNotFound
+	}
+	if user.IsSuspended() {
+		log.Printf("[auth][ERROR] user with id=%d is suspended", userID)
+		return nil, ErrUserSuspended
+	}
+
+	return user, nil
+}
+
+// SaveSession сохраняет новую сессию устройства и записывает вход в журнал безопасности.
+// Если у пользователя оказывается больше maxSessions сессий, самые давно неиспользуемые закрываются.
+func (as *AuthService) SaveSession(ctx context.Context, session *sessionEntity.Session) error {
+	log.Printf("[auth] SaveSession called: userID=%d sid=%s", session.UserID, session.ID)
+
+	evicted, err := as.RedisRepo.Set(ctx, session, as.maxSessions)
+	if err != nil {
+		log.Printf("[auth][ERROR] save session: %v", err)
+		return err
+	}
+	if err := as.revokeSessionTokens(ctx, evicted...); err != nil {
+		return err
+	}
+
+	as.recordEvent(ctx, eventEntity.NewSecurityEvent(
+		eventEntity.TypeLoginSucceeded, session.UserID, session.ID, session.IP, session.UserAgent, session.CreatedAt,
+	))
+
+	return nil
+}
+
+// RotateRefreshToken обменивает предъявленный refresh-токен сессии на newRefreshToken и возвращает обновлённую сессию:
+// её RefreshToken клиент должен получить в ответ.
+// Если предъявлен только что заменённый токен и окно grace не истекло, это параллельный повтор клиента —
+// сессия возвращается без изменений, с уже выданным преемником. Любой другой заменённый токен считается
+// украденным: сессия вместе со всем семейством токенов закрывается, а событие записывается в журнал безопасности.
+func (as *AuthService) RotateRefreshToken(ctx context.Context, userID int, sessionID, presented, newRefreshToken, ip, userAgent string) (*sessionEntity.Session, error) {
+	log.Printf("[auth] RotateRefreshToken called: userID=%d sid=%s", userID, sessionID)
+
+	// вторая попытка нужна, когда параллельный запрос успел заменить токен между чтением и записью:
+	// тогда предъявленный токен оказывается предыдущим и проходит по окну grace
+	for attempt := 0; attempt < 2; attempt++ {
+		session, err := as.RedisRepo.Get(ctx, sessionID)
+		if err != nil {
+			log.Printf("[auth][ERROR] get session: %v", err)
+			return nil, err
+		}
+		if session == nil || session.UserID != userID {
+			return nil, ErrSessionNotFound
+		}
+
+		now := time.Now()
+		switch {
+		case session.RefreshToken == presented:
+			session.Rotate(newRefreshToken, ip, userAgent, now)
+			replaced, err := as.RedisRepo.Replace(ctx, session, presented)
+			if err != nil {
+				log.Printf("[auth][ERROR] replace session: %v", err)
+				return nil, err
+			}
+			if replaced {
+				as.recordEvent(ctx, eventEntity.NewSecurityEvent(eventEntity.TypeTokenRefreshed, userID, sessionID, ip, userAgent, now))
+				log.Printf("[auth] RotateRefreshToken succesful: sid=%s", sessionID)
+				return session, nil
+			}
+		case session.InGrace(presented, as.reuseGrace, now):
+			log.Printf("[auth] RotateRefreshToken: concurrent retry within grace window, sid=%s", sessionID)
+			return session, nil
+		default:
+			as.revokeFamily(ctx, session, ip, userAgent, now)
+			return nil, ErrRefreshTokenReused
+		}
+	}
+
+	return nil, fmt.Errorf("rotate refresh token: session %s keeps changing concurrently", sessionID)
+}
+
+// revokeFamily закрывает сессию, в которой обнаружено повторное использование refresh-токена,
+// и записывает событие безопасности. Ошибки только логируются: клиенту в любом случае отказано.
+func (as *AuthService) revokeFamily(ctx context.Context, session *sessionEntity.Session, ip, userAgent string, now time.Time) {
+	log.Printf("[auth][SECURITY] refresh token reuse detected: userID=%d sid=%s ip=%s", session.UserID, session.ID, ip)
+
+	if err := as.closeSession(ctx, session.UserID, session.ID); err != nil {
+		log.Printf("[auth][ERROR] revoke token family: %v", err)
+	}
+
+	as.recordEvent(ctx, eventEntity.NewSecurityEvent(eventEntity.TypeRefreshTokenReuse, session.UserID, session.ID, ip, userAgent, now))
+}
+
+func (as *AuthService) ListSessions(ctx context.Context, userID int) ([]*sessionEntity.Session, error) {
+	log.Printf("[auth] ListSessions called: userID=%d", userID)
+
+	sessions, err := as.RedisRepo.List(ctx, userID)
+	if err != nil {
+		log.Printf("[auth][ERROR] list sessions: %v", err)
+		return nil, err
+	}
+
+	return sessions, nil
+}
+
+// RevokeSession закрывает одну сессию пользователя; чужие сессии считаются ненайденными.
+func (as *AuthService) RevokeSession(ctx context.Context, userID int, sessionID string) error {
+	log.Printf("[auth] RevokeSession called: userID=%d sid=%s", userID, sessionID)
+
+	session, err := as.RedisRepo.Get(ctx, sessionID)
+	if err != nil {
+		log.Printf("[auth][ERROR] get session: %v", err)
+		return err
+	}
+	if session == nil || session.UserID != userID {
+		return ErrSessionNotFound
+	}
+
+	if err := as.closeSession(ctx, userID, sessionID); err != nil {
+		return err
+	}
+
+	log.Printf("[auth] RevokeSession succesful")
+	return nil
+}
+
+func (as *AuthService) Logout(ctx context.Context, userID int, sessionID, ip, userAgent string) error {
+	log.Printf("[auth] Logout called: userID=%d sid=%s", userID, sessionID)
+
+	if sessionID == "" {
+		return nil
+	}
+
+	if err := as.closeSession(ctx, userID, sessionID); err != nil {
+		return err
+	}
+
+	as.recordEvent(ctx, eventEntity.NewSecurityEvent(eventEntity.TypeLogout, userID, sessionID, ip, userAgent, time.Now()))
+
+	log.Printf("[auth] Logout succesful")
+	return nil
+}
+
+// LogoutAll закрывает все сессии и отзывает все токены пользователя, выданные до текущего момента.
+// ip и userAgent — того, кто выполнил выход: самого пользователя или администратора.
+func (as *AuthService) LogoutAll(ctx context.Context, userID int, ip, userAgent string) error {
+	return as.revoker.LogoutAll(ctx, userID, ip, userAgent)
+}
+
+// closeSession удаляет сессию и вносит её токены в denylist.
+func (as *AuthService) closeSession(ctx context.Context, userID int, sessionID string) error {
+	if err := as.RedisRepo.Delete(ctx, userID, sessionID); err != nil {
+		log.Printf("[auth][ERROR] delete session sid=%s: %v", sessionID, err)
+		return err
+	}
+	return as.revokeSessionTokens(ctx, sessionID)
+}
+
+func (as *AuthService) revokeSessionTokens(ctx context.Context, sessionIDs ...string) error {
+	return as.revoker.revokeSessionTokens(ctx, sessionIDs...)
+}
+
+// IsRevoked сообщает, отозван ли токен.
+func (as *AuthService) IsRevoked(ctx context.Context, userID int, claims *jwtEntity.Claims) (bool, error) {
+	err := CheckNotRevoked(ctx, as.RedisRepo, userID, claims)
+	if errors.Is(err, ErrTokenRevoked) {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return false, nil
+}
+
+// CheckNotRevoked проверяет, что токен не отозван: не выдан до logout-all и принадлежит ещё открытой сессии.
+// Токены без sid выданы до появления сессий устройств и действуют до своего истечения. Проверка читает Redis,
+// поэтому на каждом запросе её заменяет denylist закрытых сессий, а здесь она нужна refresh-токенам.
+func CheckNotRevoked(ctx context.Context, sessions redisRepo.RedisRepository, userID int, claims *jwtEntity.Claims) error {
+	validAfter, err := sessions.GetTokensValidAfter(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("get tokens valid after: %w", err)
+	}
+	if !validAfter.IsZero() && claims.IssuedNotAfter(validAfter) {
+		return ErrTokenRevoked
+	}
+
+	if claims.SessionID == "" {
+		return nil
+	}
+
+	session, err := sessions.Get(ctx, claims.SessionID)
+	if err != nil {
+		return fmt.Errorf("get session: %w", err)
+	}
+	if session == nil || session.UserID != userID {
+		return ErrTokenRevoked
+	}
+
+	return nil
+}
@@ -0,0 +1,75 @@
+package use_cases
+
+import (
+	"context"
+	eventEntity "github.com/1URose/marketplace/internal/auth_signup/domain/security_event/entity"
+	"github.com/1URose/marketplace/internal/common/password"
+	"log"
+	"time"
+)
+
+// ChangePassword меняет пароль после проверки текущего и закрывает все сессии пользователя, кроме текущей.
+func (as *AuthService) ChangePassword(ctx context.Context, userID int, sessionID, currentPassword, newPassword, ip, userAgent string) error {
+	log.Printf("[auth] ChangePassword called: userID=%d sid=%s", userID, sessionID)
+
+	user, err := as.UserRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		log.Printf("[auth][ERROR] get user by id: %v", err)
+		return err
+	}
+	if user == nil {
+		return ErrUserNotFound
+	}
+
+	// у пользователей, вошедших через внешнего провайдера, пароля нет: задать его можно через сброс пароля
+	if err := as.checkCurrentPassword(ctx, user, currentPassword, ip, userAgent); err != nil {
+		return err
+	}
+
+	if err := as.passwordPolicy.Validate(ctx, newPassword, user.Email); err != nil {
+		return err
+	}
+
+	passwordHash, err := password.HashPassword(newPassword)
+	if err != nil {
+		log.Printf("[auth][ERROR] hashing password: %v", err)
+		return err
+	}
+	if err := as.UserRepo.UpdatePassword(ctx, user.ID, passwordHash); err != nil {
+		log.Printf("[auth][ERROR] update password: %v", err)
+		return err
+	}
+
+	if err := as.revokeOtherSessions(ctx, user.ID, sessionID); err != nil {
+		return err
+	}
+
+	as.recordEvent(ctx, eventEntity.NewSecurityEvent(eventEntity.TypePasswordChanged, user.ID, sessionID, ip, userAgent, time.Now()))
+
+	log.Printf("[auth] ChangePassword succesful: userID=%d", user.ID)
+	return nil
+}
+
+// revokeOtherSessions закрывает все сессии пользователя, кроме keepSessionID; access-токены закрытых сессий
+// перестают приниматься вместе с ними.
+func (as *AuthService) revokeOtherSessions(ctx context.Context, userID int, keepSessionID string) error {
+	sessions, err := as.RedisRepo.List(ctx, userID)
+	if err != nil {
+		log.Printf("[auth][ERROR] list sessions: %v", err)
+		return err
+	}
+
+	closed := 0
+	for _, session := range sessions {
+		if session.ID == keepSessionID {
+			continue
+		}
+		if err := as.closeSession(ctx, userID, session.ID); err != nil {
+			return err
+		}
+		closed++
+	}
+
+	log.Printf("[auth] revokeOtherSessions: userID=%d closed %d session(s)", userID, closed)
+	return nil
+}
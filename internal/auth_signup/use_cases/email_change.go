@@ -0,0 +1,119 @@
+package use_cases
+
+import (
+	"context"
+	"fmt"
+	"github.com/1URose/marketplace/internal/common/apperror"
+	"github.com/1URose/marketplace/internal/common/mailer"
+	"github.com/1URose/marketplace/internal/user_profile/domain/user/entity"
+	"log"
+	"strings"
+	"time"
+)
+
+// ErrSameEmail — новый адрес совпадает с текущим.
+var ErrSameEmail = apperror.Validation("auth.same_email")
+
+// RequestEmailChange отправляет на новый адрес ссылку подтверждения. Учётная запись переключается на него,
+// только когда пользователь перейдёт по ссылке; до этого вход и письма идут на прежний адрес.
+func (vs *EmailVerificationService) RequestEmailChange(ctx context.Context, userID int, newEmail, currentPassword, ip, userAgent string) error {
+	log.Printf("[email_verification] RequestEmailChange called: userID=%d", userID)
+
+	user, err := vs.users.GetUserByID(ctx, userID)
+	if err != nil {
+		log.Printf("[email_verification][ERROR] get user by id: %v", err)
+		return err
+	}
+	if user == nil {
+		return ErrUserNotFound
+	}
+	if err := vs.auth.checkCurrentPassword(ctx, user, currentPassword, ip, userAgent); err != nil {
+		return err
+	}
+	if strings.EqualFold(user.Email, newEmail) {
+		return ErrSameEmail
+	}
+
+	taken, err := vs.users.GetUserByEmail(ctx, newEmail)
+	if err != nil {
+		log.Printf("[email_verification][ERROR] get user by email: %v", err)
+		return err
+	}
+	if taken != nil {
+		return entity.ErrEmailTaken
+	}
+
+	wait, err := vs.resends.Acquire(ctx, user.ID, vs.cfg.ResendCooldown)
+	if err != nil {
+		log.Printf("[email_verification][ERROR] acquire resend window: %v", err)
+		return err
+	}
+	if wait > 0 {
+		return ErrResendThrottled.WithRetryAfter(wait)
+	}
+
+	token, err := vs.tokens.GenerateEmailChangeToken(newEmail, user.ID)
+	if err != nil {
+		log.Printf("[email_verification][ERROR] generate email change token: %v", err)
+		return err
+	}
+
+	link, err := linkWithToken(vs.cfg.LinkURL, token)
+	if err != nil {
+		return err
+	}
+
+	msg := mailer.Message{
+		To:      newEmail,
+		Subject: "Подтвердите новый email на Marketplace",
+		Body: fmt.Sprintf(
+			"Здравствуйте!\n\nЧтобы сменить email учётной записи на %s, перейдите по ссылке:\n%s\n\n"+
+				"Ссылка действует %d ч. Если вы не запрашивали смену адреса, просто проигнорируйте это письмо.\n",
+			newEmail, link, int(vs.cfg.TokenTTL.Hours()),
+		),
+	}
+	if err := vs.mailer.Send(ctx, msg); err != nil {
+		log.Printf("[email_verification][ERROR] send email change confirmation: %v", err)
+		return err
+	}
+
+	log.Printf("[email_verification] RequestEmailChange: confirmation sent for userID=%d", user.ID)
+	return nil
+}
+
+// confirmEmailChange переключает пользователя на подтверждённый адрес и уведомляет прежний.
+// Сессии привязаны к идентификатору пользователя и продолжают действовать; новый email попадает
+// в токены при следующем обновлении.
+func (vs *EmailVerificationService) confirmEmailChange(ctx context.Context, user *entity.User, newEmail string) error {
+	if strings.EqualFold(user.Email, newEmail) {
+		log.Printf("[email_verification] confirmEmailChange: userID=%d already switched", user.ID)
+		return nil
+	}
+
+	oldEmail := user.Email
+	now := time.Now()
+
+	if err := vs.users.UpdateEmail(ctx, user.ID, newEmail, now); err != nil {
+		log.Printf("[email_verification][ERROR] update email: %v", err)
+		return err
+	}
+	user.Email = newEmail
+	user.EmailVerifiedAt = &now
+
+	msg := mailer.Message{
+		To:      oldEmail,
+		Subject: "Email на Marketplace изменён",
+		Body: fmt.Sprintf(
+			"Здравствуйте!\n\nEmail вашей учётной записи изменён на %s. Письма теперь будут приходить на новый адрес.\n\n"+
+				"Если это были не вы, срочно свяжитесь с поддержкой.\n",
+			newEmail,
+		),
+	}
+	if err := vs.mailer.Send(ctx, msg); err != nil {
+		// адрес уже изменён, письмо лишь уведомление
+		log.Printf("[email_verification][ERROR] send email change notice: %v", err)
+	}
+
+	log.Printf("[email_verification] email changed for userID=%d", user.ID)
+	return nil
+}
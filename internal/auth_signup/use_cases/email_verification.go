@@ -0,0 +1,198 @@
+package use_cases
+
+import (
+	"context"
+	"fmt"
+	"github.com/1URose/marketplace/internal/auth_signup/domain/email_verification/repository"
+	"github.com/1URose/marketplace/internal/common/apperror"
+	"github.com/1URose/marketplace/internal/common/config/common"
+	"github.com/1URose/marketplace/internal/common/jwt"
+	"github.com/1URose/marketplace/internal/common/mailer"
+	"github.com/1URose/marketplace/internal/user_profile/domain/user/entity"
+	userRepo "github.com/1URose/marketplace/internal/user_profile/domain/user/repository"
+	"log"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrInvalidVerificationToken — токен подтверждения подделан, истёк, уже использован или выдан для прежнего email.
+	ErrInvalidVerificationToken = apperror.Validation("auth.invalid_verification_token")
+	ErrEmailAlreadyVerified     = apperror.Conflict("auth.email_already_verified")
+	// ErrResendThrottled — письмо с подтверждением уже недавно отправлялось.
+	ErrResendThrottled = apperror.RateLimited("auth.verification_throttled", 0)
+)
+
+// EmailVerificationService подтверждает владение email по одноразовой подписанной ссылке из письма.
+type EmailVerificationService struct {
+	auth    *AuthService
+	users   userRepo.UserRepository
+	tokens  *jwt.Manager
+	mailer  mailer.Mailer
+	resends repository.ResendRepository
+	cfg     common.EmailVerification
+}
+
+func NewEmailVerificationService(
+	auth *AuthService,
+	users userRepo.UserRepository,
+	tokens *jwt.Manager,
+	mailer mailer.Mailer,
+	resends repository.ResendRepository,
+	cfg common.EmailVerification,
+) *EmailVerificationService {
+	log.Println("[email_verification] initializing EmailVerificationService")
+
+	return &EmailVerificationService{
+		auth:    auth,
+		users:   users,
+		tokens:  tokens,
+		mailer:  mailer,
+		resends: resends,
+		cfg:     cfg,
+	}
+}
+
+// SendVerification отправляет письмо новому пользователю. Окно повторной отправки начинается с этого письма.
+func (vs *EmailVerificationService) SendVerification(ctx context.Context, user *entity.User) error {
+	log.Printf("[email_verification] SendVerification called: userID=%d", user.ID)
+
+	if _, err := vs.resends.Acquire(ctx, user.ID, vs.cfg.ResendCooldown); err != nil {
+		log.Printf("[email_verification][ERROR] acquire resend window: %v", err)
+		return err
+	}
+
+	return vs.send(ctx, user)
+}
+
+// Resend повторно отправляет письмо, но не чаще раза в ResendCooldown.
+func (vs *EmailVerificationService) Resend(ctx context.Context, userID int) error {
+	log.Printf("[email_verification] Resend called: userID=%d", userID)
+
+	user, err := vs.users.GetUserByID(ctx, userID)
+	if err != nil {
+		log.Printf("[email_verification][ERROR] get user by id: %v", err)
+		return err
+	}
+	if user == nil {
+		return ErrUserNotFound
+	}
+	if user.IsEmailVerified() {
+		return ErrEmailAlreadyVerified
+	}
+
+	wait, err := vs.resends.Acquire(ctx, user.ID, vs.cfg.ResendCooldown)
+	if err != nil {
+		log.Printf("[email_verification][ERROR] acquire resend window: %v", err)
+		return err
+	}
+	if wait > 0 {
+		return ErrResendThrottled.WithRetryAfter(wait)
+	}
+
+	return vs.send(ctx, user)
+}
+
+// Verify подтверждает email по токену из письма. Токен одноразовый; повторное подтверждение
+// уже подтверждённого адреса не считается ошибкой. Токен смены адреса переключает учётную запись на новый email.
+func (vs *EmailVerificationService) Verify(ctx context.Context, token string) (*entity.User, error) {
+	log.Printf("[email_verification] Verify called")
+
+	claims, err := vs.tokens.ValidateEmailToken(ctx, token)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidVerificationToken, err)
+	}
+
+	userID, err := strconv.Atoi(claims.Subject)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid subject %q", ErrInvalidVerificationToken, claims.Subject)
+	}
+
+	user, err := vs.users.GetUserByID(ctx, userID)
+	if err != nil {
+		log.Printf("[email_verification][ERROR] get user by id: %v", err)
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrInvalidVerificationToken
+	}
+
+	if claims.TokenType == jwt.TokenTypeEmailChange {
+		err = vs.confirmEmailChange(ctx, user, claims.Email)
+	} else {
+		err = vs.confirmEmail(ctx, user, claims.Email)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := vs.tokens.Revoke(ctx, token); err != nil {
+		log.Printf("[email_verification][ERROR] revoke verification token: %v", err)
+		return nil, err
+	}
+
+	log.Printf("[email_verification] Verify succesful: userID=%d", user.ID)
+	return user, nil
+}
+
+func (vs *EmailVerificationService) confirmEmail(ctx context.Context, user *entity.User, email string) error {
+	if !strings.EqualFold(user.Email, email) {
+		log.Printf("[email_verification][ERROR] token for userID=%d does not match current email", user.ID)
+		return ErrInvalidVerificationToken
+	}
+	if user.IsEmailVerified() {
+		return nil
+	}
+
+	now := time.Now()
+	if _, err := vs.users.MarkEmailVerified(ctx, user.ID, user.Email, now); err != nil {
+		log.Printf("[email_verification][ERROR] mark email verified: %v", err)
+		return err
+	}
+	user.EmailVerifiedAt = &now
+	return nil
+}
+
+func (vs *EmailVerificationService) send(ctx context.Context, user *entity.User) error {
+	token, err := vs.tokens.GenerateEmailVerificationToken(user.Email, user.ID)
+	if err != nil {
+		log.Printf("[email_verification][ERROR] generate token: %v", err)
+		return err
+	}
+
+	link, err := linkWithToken(vs.cfg.LinkURL, token)
+	if err != nil {
+		return err
+	}
+
+	msg := mailer.Message{
+		To:      user.Email,
+		Subject: "Подтвердите email на Marketplace",
+		Body: fmt.Sprintf(
+			"Здравствуйте!\n\nЧтобы подтвердить адрес %s, перейдите по ссылке:\n%s\n\n"+
+				"Ссылка действует %d ч. Если вы не регистрировались на Marketplace, просто проигнорируйте это письмо.\n",
+			user.Email, link, int(vs.cfg.TokenTTL.Hours()),
+		),
+	}
+	if err := vs.mailer.Send(ctx, msg); err != nil {
+		log.Printf("[email_verification][ERROR] send verification email: %v", err)
+		return err
+	}
+
+	log.Printf("[email_verification] verification email sent: userID=%d", user.ID)
+	return nil
+}
+
+// linkWithToken добавляет токен к адресу страницы фронтенда параметром ?token=.
+func linkWithToken(base, token string) (string, error) {
+	link, err := url.Parse(base)
+	if err != nil {
+		return "", fmt.Errorf("parse link url %q: %w", base, err)
+	}
+	q := link.Query()
+	q.Set("token", token)
+	link.RawQuery = q.Encode()
+	return link.String(), nil
+}
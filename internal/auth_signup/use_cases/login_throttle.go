@@ -0,0 +1,123 @@
+package use_cases
+
+import (
+	"context"
+	attemptEntity "github.com/1URose/marketplace/internal/auth_signup/domain/login_attempt/entity"
+	eventEntity "github.com/1URose/marketplace/internal/auth_signup/domain/security_event/entity"
+	"github.com/1URose/marketplace/internal/common/apperror"
+	"github.com/1URose/marketplace/internal/common/password"
+	"github.com/1URose/marketplace/internal/user_profile/domain/user/entity"
+	"log"
+	"time"
+)
+
+// ErrLoginThrottled — вход временно закрыт после серии неудачных попыток.
+var ErrLoginThrottled = apperror.RateLimited("auth.login_throttled", 0)
+
+func (as *AuthService) checkLoginAllowed(ctx context.Context, email, ip string) error {
+	now := time.Now()
+
+	var retryAfter time.Duration
+	for _, key := range []string{attemptEntity.AccountKey(email), attemptEntity.IPKey(ip)} {
+		until, err := as.attempts.BlockedUntil(ctx, key)
+		if err != nil {
+			log.Printf("[auth][ERROR] get login block: %v", err)
+			return err
+		}
+		if wait := until.Sub(now); wait > retryAfter {
+			retryAfter = wait
+		}
+	}
+
+	if retryAfter > 0 {
+		log.Printf("[auth][ERROR] login throttled: email=%s ip=%s retryAfter=%s", email, ip, retryAfter)
+		return ErrLoginThrottled.WithRetryAfter(retryAfter)
+	}
+	return nil
+}
+
+// registerLoginFailure учитывает неудачную попытку для учётной записи и IP и при необходимости закрывает вход.
+// user равен nil, если учётной записи с таким email нет.
+func (as *AuthService) registerLoginFailure(ctx context.Context, email, ip, userAgent string, user *entity.User) error {
+	now := time.Now()
+
+	counters := []struct {
+		key       string
+		threshold attemptEntity.Threshold
+	}{
+		{attemptEntity.AccountKey(email), as.throttle.Account},
+		{attemptEntity.IPKey(ip), as.throttle.IP},
+	}
+
+	for i, c := range counters {
+		failures, err := as.attempts.RegisterFailure(ctx, c.key, as.throttle.FailureWindow)
+		if err != nil {
+			log.Printf("[auth][ERROR] register login failure: %v", err)
+			return err
+		}
+
+		delay, locked := as.throttle.Delay(c.threshold, failures)
+		if delay == 0 {
+			continue
+		}
+		if err := as.attempts.Block(ctx, c.key, now.Add(delay)); err != nil {
+			log.Printf("[auth][ERROR] block login: %v", err)
+			return err
+		}
+		log.Printf("[auth] login blocked: key=%s failures=%d delay=%s locked=%t", c.key, failures, delay, locked)
+
+		if locked && i == 0 && user != nil {
+			as.recordEvent(ctx, eventEntity.NewSecurityEvent(eventEntity.TypeAccountLocked, user.ID, "", ip, userAgent, now))
+		}
+	}
+
+	return nil
+}
+
+// checkCurrentPassword проверяет текущий пароль перед изменением учётной записи. Неверные пароли учитываются
+// той же защитой от перебора, что и при входе: действующий токен не должен давать подбирать пароль без ограничений.
+func (as *AuthService) checkCurrentPassword(ctx context.Context, user *entity.User, currentPassword, ip, userAgent string) error {
+	if err := as.checkLoginAllowed(ctx, user.Email, ip); err != nil {
+		return err
+	}
+
+	if password.CheckPasswordHash(currentPassword, user.PasswordHash) {
+		return nil
+	}
+
+	log.Printf("[auth][ERROR] wrong current password for userID=%d", user.ID)
+	if err := as.registerLoginFailure(ctx, user.Email, ip, userAgent, user); err != nil {
+		return err
+	}
+	return ErrInvalidCurrentPassword
+}
+
+// UnlockLogin снимает блокировку входа с учётной записи и сбрасывает её счётчик неудач.
+func (as *AuthService) UnlockLogin(ctx context.Context, userID int, ip, userAgent string) error {
+	log.Printf("[auth] UnlockLogin called: userID=%d", userID)
+
+	user, err := as.UserRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		log.Printf("[auth][ERROR] get user by id: %v", err)
+		return err
+	}
+	if user == nil {
+		return ErrUserNotFound
+	}
+
+	if err := as.attempts.Reset(ctx, attemptEntity.AccountKey(user.Email)); err != nil {
+		log.Printf("[auth][ERROR] reset login failures: %v", err)
+		return err
+	}
+
+	as.recordEvent(ctx, eventEntity.NewSecurityEvent(eventEntity.TypeAccountUnlocked, user.ID, "", ip, userAgent, time.Now()))
+
+	log.Printf("[auth] UnlockLogin succesful: userID=%d", userID)
+	return nil
+}
+
+func (as *AuthService) recordEvent(ctx context.Context, event *eventEntity.SecurityEvent) {
+	if err := as.events.Record(ctx, event); err != nil {
+		log.Printf("[auth][ERROR] record security event: %v", err)
+	}
+}
@@ -0,0 +1,161 @@
+package use_cases
+
+import (
+	"context"
+	"fmt"
+	"github.com/1URose/marketplace/internal/auth_signup/domain/magic_link/entity"
+	"github.com/1URose/marketplace/internal/auth_signup/domain/magic_link/repository"
+	"github.com/1URose/marketplace/internal/common/config/common"
+	"github.com/1URose/marketplace/internal/common/jwt"
+	"github.com/1URose/marketplace/internal/common/mailer"
+	userEntity "github.com/1URose/marketplace/internal/user_profile/domain/user/entity"
+	userRepo "github.com/1URose/marketplace/internal/user_profile/domain/user/repository"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MagicLinkService выполняет вход без пароля по одноразовой подписанной ссылке из письма.
+type MagicLinkService struct {
+	users  userRepo.UserRepository
+	tokens *jwt.Manager
+	mailer mailer.Mailer
+	links  repository.MagicLinkRepository
+	cfg    common.MagicLink
+}
+
+func NewMagicLinkService(
+	users userRepo.UserRepository,
+	tokens *jwt.Manager,
+	mailer mailer.Mailer,
+	links repository.MagicLinkRepository,
+	cfg common.MagicLink,
+) *MagicLinkService {
+	log.Println("[magic_link] initializing MagicLinkService")
+
+	return &MagicLinkService{
+		users:  users,
+		tokens: tokens,
+		mailer: mailer,
+		links:  links,
+		cfg:    cfg,
+	}
+}
+
+// Request отправляет ссылку входа, если активный пользователь с таким email существует. Как и при сбросе пароля,
+// об отсутствии пользователя не сообщается, а повторные запросы в пределах Cooldown молча пропускаются.
+// Непустой deviceHash привязывает ссылку к запросившему её устройству.
+func (ms *MagicLinkService) Request(ctx context.Context, email, deviceHash string) error {
+	log.Printf("[magic_link] Request called: email=%s deviceBound=%t", email, deviceHash != "")
+
+	user, err := ms.users.GetUserByEmail(ctx, email)
+	if err != nil {
+		log.Printf("[magic_link][ERROR] get user by email: %v", err)
+		return err
+	}
+	if user == nil || user.IsSuspended() {
+		log.Printf("[magic_link] Request: no active user with email=%s, nothing sent", email)
+		return nil
+	}
+
+	acquired, err := ms.links.AcquireSendWindow(ctx, user.ID, ms.cfg.Cooldown)
+	if err != nil {
+		log.Printf("[magic_link][ERROR] acquire send window: %v", err)
+		return err
+	}
+	if !acquired {
+		log.Printf("[magic_link] Request: link recently sent to userID=%d, skipping", user.ID)
+		return nil
+	}
+
+	token, err := ms.tokens.GenerateMagicLinkToken(user.Email, user.ID, deviceHash)
+	if err != nil {
+		log.Printf("[magic_link][ERROR] generate token: %v", err)
+		return err
+	}
+
+	link, err := linkWithToken(ms.cfg.LinkURL, token)
+	if err != nil {
+		return err
+	}
+
+	note := ""
+	if deviceHash != "" {
+		note = "Ссылка сработает только в браузере, где вы запросили вход.\n"
+	}
+
+	msg := mailer.Message{
+		To:      user.Email,
+		Subject: "Вход в Marketplace",
+		Body: fmt.Sprintf(
+			"Здравствуйте!\n\nЧтобы войти в Marketplace, перейдите по ссылке:\n%s\n\n"+
+				"Ссылка действует %d мин. и сработает один раз. %s"+
+				"Если вы не запрашивали вход, просто проигнорируйте это письмо.\n",
+			link, int(ms.cfg.TokenTTL.Minutes()), note,
+		),
+	}
+	if err := ms.mailer.Send(ctx, msg); err != nil {
+		log.Printf("[magic_link][ERROR] send magic link: %v", err)
+		return err
+	}
+
+	log.Printf("[magic_link] magic link sent: userID=%d", user.ID)
+	return nil
+}
+
+// Consume проверяет ссылку и возвращает пользователя, которому нужно выдать токены. Переход по ссылке
+// подтверждает и владение email. Ссылка, привязанная к устройству, без его секрета отвергается, но не
+// расходуется, чтобы пересланное письмо не мешало войти с правильного устройства.
+func (ms *MagicLinkService) Consume(ctx context.Context, token, deviceSecret string) (*userEntity.User, error) {
+	log.Printf("[magic_link] Consume called")
+
+	claims, err := ms.tokens.ValidateMagicLinkToken(ctx, token)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", entity.ErrInvalidMagicLink, err)
+	}
+
+	if claims.DeviceHash != "" && !entity.DeviceMatches(deviceSecret, claims.DeviceHash) {
+		log.Printf("[magic_link][ERROR] Consume: link for subject=%s presented from another device", claims.Subject)
+		return nil, entity.ErrDeviceMismatch
+	}
+
+	userID, err := strconv.Atoi(claims.Subject)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid subject %q", entity.ErrInvalidMagicLink, claims.Subject)
+	}
+
+	user, err := ms.users.GetUserByID(ctx, userID)
+	if err != nil {
+		log.Printf("[magic_link][ERROR] get user by id: %v", err)
+		return nil, err
+	}
+	if user == nil || !strings.EqualFold(user.Email, claims.Email) {
+		return nil, entity.ErrInvalidMagicLink
+	}
+	if user.IsSuspended() {
+		return nil, ErrUserSuspended
+	}
+
+	first, err := ms.links.MarkUsed(ctx, claims.ID, time.Until(claims.ExpiresAt.Time))
+	if err != nil {
+		log.Printf("[magic_link][ERROR] mark link used: %v", err)
+		return nil, err
+	}
+	if !first {
+		log.Printf("[magic_link][ERROR] Consume: link for userID=%d already used", user.ID)
+		return nil, entity.ErrInvalidMagicLink
+	}
+
+	if !user.IsEmailVerified() {
+		now := time.Now()
+		if _, err := ms.users.MarkEmailVerified(ctx, user.ID, user.Email, now); err != nil {
+			log.Printf("[magic_link][ERROR] mark email verified: %v", err)
+		} else {
+			user.EmailVerifiedAt = &now
+		}
+	}
+
+	log.Printf("[magic_link] Consume succesful: userID=%d", user.ID)
+	return user, nil
+}
@@ -0,0 +1,208 @@
+package use_cases
+
+import (
+	"context"
+	"errors"
+	mfaEntity "github.com/1URose/marketplace/internal/auth_signup/domain/mfa/entity"
+	eventEntity "github.com/1URose/marketplace/internal/auth_signup/domain/security_event/entity"
+	"github.com/1URose/marketplace/internal/user_profile/domain/user/entity"
+	"log"
+	"time"
+)
+
+// MFARequired сообщает, нужен ли пользователю второй фактор при входе.
+func (as *AuthService) MFARequired(ctx context.Context, userID int) (bool, error) {
+	totp, err := as.mfa.GetTOTP(ctx, userID)
+	if err != nil {
+		log.Printf("[auth][ERROR] get totp: %v", err)
+		return false, err
+	}
+	return totp.IsEnabled(), nil
+}
+
+// BeginTOTPEnrollment выдаёт новый секрет и otpauth URI. TOTP начинает действовать только после ConfirmTOTP.
+func (as *AuthService) BeginTOTPEnrollment(ctx context.Context, userID int, email string) (string, string, error) {
+	log.Printf("[auth] BeginTOTPEnrollment called: userID=%d", userID)
+
+	secret, err := mfaEntity.NewTOTPSecret()
+	if err != nil {
+		log.Printf("[auth][ERROR] generate totp secret: %v", err)
+		return "", "", err
+	}
+
+	if err := as.mfa.SaveTOTPSecret(ctx, userID, secret); err != nil {
+		log.Printf("[auth][ERROR] save totp secret: %v", err)
+		return "", "", err
+	}
+
+	log.Printf("[auth] BeginTOTPEnrollment succesful: userID=%d", userID)
+	return secret, mfaEntity.TOTPURI(as.mfaIssuer, email, secret), nil
+}
+
+// ConfirmTOTP включает TOTP по первому коду из приложения и возвращает коды восстановления.
+// Коды показываются один раз: в базе хранятся только их хеши.
+func (as *AuthService) ConfirmTOTP(ctx context.Context, userID int, code, ip, userAgent string) ([]string, error) {
+	log.Printf("[auth] ConfirmTOTP called: userID=%d", userID)
+
+	totp, err := as.mfa.GetTOTP(ctx, userID)
+	if err != nil {
+		log.Printf("[auth][ERROR] get totp: %v", err)
+		return nil, err
+	}
+	if totp == nil {
+		return nil, mfaEntity.ErrMFANotEnrolled
+	}
+	if totp.IsEnabled() {
+		return nil, mfaEntity.ErrMFAAlreadyEnabled
+	}
+
+	step, ok := mfaEntity.MatchTOTP(totp.Secret, code, time.Now(), 0)
+	if !ok {
+		log.Printf("[auth][ERROR] ConfirmTOTP: invalid code for userID=%d", userID)
+		return nil, mfaEntity.ErrInvalidMFACode
+	}
+
+	codes, err := mfaEntity.NewRecoveryCodes()
+	if err != nil {
+		log.Printf("[auth][ERROR] generate recovery codes: %v", err)
+		return nil, err
+	}
+	hashes := make([]string, len(codes))
+	for i, c := range codes {
+		hashes[i] = mfaEntity.HashRecoveryCode(c)
+	}
+
+	if err := as.mfa.EnableTOTP(ctx, userID, step, hashes); err != nil {
+		log.Printf("[auth][ERROR] enable totp: %v", err)
+		return nil, err
+	}
+
+	as.recordEvent(ctx, eventEntity.NewSecurityEvent(eventEntity.TypeMFAEnabled, userID, "", ip, userAgent, time.Now()))
+
+	log.Printf("[auth] ConfirmTOTP succesful: userID=%d", userID)
+	return codes, nil
+}
+
+// DisableTOTP выключает второй фактор; для этого нужен действующий код TOTP или код восстановления.
+// Неверные коды учитываются той же защитой от перебора, что и при входе.
+func (as *AuthService) DisableTOTP(ctx context.Context, userID int, code, recoveryCode, ip, userAgent string) error {
+	log.Printf("[auth] DisableTOTP called: userID=%d", userID)
+
+	user, err := as.UserRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		log.Printf("[auth][ERROR] get user by id: %v", err)
+		return err
+	}
+	if user == nil {
+		return ErrUserNotFound
+	}
+
+	if err := as.checkLoginAllowed(ctx, user.Email, ip); err != nil {
+		return err
+	}
+
+	err = as.verifySecondFactor(ctx, userID, code, recoveryCode, ip, userAgent)
+	if errors.Is(err, mfaEntity.ErrInvalidMFACode) {
+		if err := as.registerLoginFailure(ctx, user.Email, ip, userAgent, user); err != nil {
+			return err
+		}
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := as.mfa.DisableTOTP(ctx, userID); err != nil {
+		log.Printf("[auth][ERROR] disable totp: %v", err)
+		return err
+	}
+
+	as.recordEvent(ctx, eventEntity.NewSecurityEvent(eventEntity.TypeMFADisabled, userID, "", ip, userAgent, time.Now()))
+
+	log.Printf("[auth] DisableTOTP succesful: userID=%d", userID)
+	return nil
+}
+
+// CompleteMFALogin завершает вход, начатый паролем: проверяет второй фактор пользователя из MFA-токена.
+// Неверные коды учитываются той же защитой от перебора, что и неверные пароли.
+func (as *AuthService) CompleteMFALogin(ctx context.Context, userID int, code, recoveryCode, ip, userAgent string) (*entity.User, error) {
+	log.Printf("[auth] CompleteMFALogin called: userID=%d ip=%s", userID, ip)
+
+	user, err := as.UserRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		log.Printf("[auth][ERROR] get user by id: %v", err)
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+
+	if err := as.checkLoginAllowed(ctx, user.Email, ip); err != nil {
+		return nil, err
+	}
+
+	err = as.verifySecondFactor(ctx, userID, code, recoveryCode, ip, userAgent)
+	if errors.Is(err, mfaEntity.ErrInvalidMFACode) {
+		as.recordEvent(ctx, eventEntity.NewSecurityEvent(eventEntity.TypeLoginFailed, userID, "", ip, userAgent, time.Now()))
+		if err := as.registerLoginFailure(ctx, user.Email, ip, userAgent, user); err != nil {
+			return nil, err
+		}
+		return nil, err
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if user.IsSuspended() {
+		log.Printf("[auth][ERROR] user with id=%d is suspended", userID)
+		return nil, ErrUserSuspended
+	}
+
+	log.Printf("[auth] CompleteMFALogin succesful: userID=%d", userID)
+	return user, nil
+}
+
+// verifySecondFactor принимает либо код TOTP, либо код восстановления. Каждый из них срабатывает один раз.
+func (as *AuthService) verifySecondFactor(ctx context.Context, userID int, code, recoveryCode, ip, userAgent string) error {
+	totp, err := as.mfa.GetTOTP(ctx, userID)
+	if err != nil {
+		log.Printf("[auth][ERROR] get totp: %v", err)
+		return err
+	}
+	if !totp.IsEnabled() {
+		return mfaEntity.ErrMFANotEnabled
+	}
+
+	if code != "" {
+		step, ok := mfaEntity.MatchTOTP(totp.Secret, code, time.Now(), totp.LastUsedStep)
+		if !ok {
+			log.Printf("[auth][ERROR] invalid totp code for userID=%d", userID)
+			return mfaEntity.ErrInvalidMFACode
+		}
+		// шаг отмечается условно: параллельный запрос с тем же кодом его уже не пройдёт
+		used, err := as.mfa.UseTOTPStep(ctx, userID, step)
+		if err != nil {
+			return err
+		}
+		if !used {
+			log.Printf("[auth][ERROR] totp code replayed for userID=%d", userID)
+			return mfaEntity.ErrInvalidMFACode
+		}
+		return nil
+	}
+
+	// код ищется по хешу одним запросом: неверный код не стоит перебора всех хешей пользователя
+	used, err := as.mfa.UseRecoveryCode(ctx, userID, mfaEntity.HashRecoveryCode(recoveryCode))
+	if err != nil {
+		log.Printf("[auth][ERROR] use recovery code: %v", err)
+		return err
+	}
+	if !used {
+		log.Printf("[auth][ERROR] invalid recovery code for userID=%d", userID)
+		return mfaEntity.ErrInvalidMFACode
+	}
+
+	log.Printf("[auth] recovery code used: userID=%d", userID)
+	as.recordEvent(ctx, eventEntity.NewSecurityEvent(eventEntity.TypeRecoveryCodeUsed, userID, "", ip, userAgent, time.Now()))
+	return nil
+}
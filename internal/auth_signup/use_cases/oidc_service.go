@@ -0,0 +1,185 @@
+package use_cases
+
+import (
+	"context"
+	"errors"
+	oidcEntity "github.com/1URose/marketplace/internal/auth_signup/domain/oidc/entity"
+	oidcRepo "github.com/1URose/marketplace/internal/auth_signup/domain/oidc/repository"
+	"github.com/1URose/marketplace/internal/user_profile/domain/user/entity"
+	userRepo "github.com/1URose/marketplace/internal/user_profile/domain/user/repository"
+	"log"
+	"time"
+)
+
+// OIDCService — вход через внешних OpenID Connect провайдеров по authorization code flow с PKCE, state и nonce.
+type OIDCService struct {
+	providers  map[string]oidcRepo.IdentityProvider
+	requests   oidcRepo.AuthRequestRepository
+	identities oidcRepo.IdentityRepository
+	users      userRepo.UserRepository
+	stateTTL   time.Duration
+}
+
+func NewOIDCService(
+	providers []oidcRepo.IdentityProvider,
+	requests oidcRepo.AuthRequestRepository,
+	identities oidcRepo.IdentityRepository,
+	users userRepo.UserRepository,
+	stateTTL time.Duration,
+) *OIDCService {
+	log.Printf("[oidc] initializing OIDCService: providers=%d", len(providers))
+
+	byName := make(map[string]oidcRepo.IdentityProvider, len(providers))
+	for _, p := range providers {
+		byName[p.Name()] = p
+	}
+
+	return &OIDCService{
+		providers:  byName,
+		requests:   requests,
+		identities: identities,
+		users:      users,
+		stateTTL:   stateTTL,
+	}
+}
+
+// BeginLogin запоминает state, nonce и PKCE-верификатор и возвращает адрес страницы входа провайдера.
+func (os *OIDCService) BeginLogin(ctx context.Context, providerName, deviceName string) (string, error) {
+	log.Printf("[oidc] BeginLogin called: provider=%s", providerName)
+
+	provider, ok := os.providers[providerName]
+	if !ok {
+		return "", oidcEntity.ErrUnknownProvider
+	}
+
+	req, err := oidcEntity.NewAuthRequest(providerName, deviceName, time.Now())
+	if err != nil {
+		log.Printf("[oidc][ERROR] new auth request: %v", err)
+		return "", err
+	}
+
+	authURL, err := provider.AuthCodeURL(ctx, req.State, req.Nonce, req.CodeChallenge())
+	if err != nil {
+		log.Printf("[oidc][ERROR] build auth code url: %v", err)
+		return "", err
+	}
+
+	if err := os.requests.Save(ctx, req, os.stateTTL); err != nil {
+		log.Printf("[oidc][ERROR] save auth request: %v", err)
+		return "", err
+	}
+
+	log.Printf("[oidc] BeginLogin succesful: provider=%s", providerName)
+	return authURL, nil
+}
+
+// CompleteLogin обрабатывает возврат от провайдера и возвращает пользователя маркетплейса и имя устройства,
+// указанное при начале входа. Новая внешняя учётная запись привязывается к пользователю с тем же
+// подтверждённым провайдером email, только если и сам пользователь его подтвердил; если такого
+// пользователя нет — создаётся пользователь без пароля.
+func (os *OIDCService) CompleteLogin(ctx context.Context, providerName, code, state string) (*entity.User, string, error) {
+	log.Printf("[oidc] CompleteLogin called: provider=%s", providerName)
+
+	provider, ok := os.providers[providerName]
+	if !ok {
+		return nil, "", oidcEntity.ErrUnknownProvider
+	}
+
+	req, err := os.requests.Take(ctx, state)
+	if err != nil {
+		log.Printf("[oidc][ERROR] take auth request: %v", err)
+		return nil, "", err
+	}
+	if req == nil || req.Provider != providerName {
+		log.Printf("[oidc][ERROR] unknown state for provider=%s", providerName)
+		return nil, "", oidcEntity.ErrInvalidState
+	}
+
+	claims, err := provider.Exchange(ctx, code, req.CodeVerifier)
+	if err != nil {
+		log.Printf("[oidc][ERROR] exchange code: %v", err)
+		return nil, "", err
+	}
+	if claims.Nonce != req.Nonce {
+		log.Printf("[oidc][ERROR] nonce mismatch for provider=%s sub=%s", providerName, claims.Subject)
+		return nil, "", oidcEntity.ErrNonceMismatch
+	}
+
+	userID, err := os.resolveUser(ctx, providerName, claims)
+	if err != nil {
+		return nil, "", err
+	}
+
+	user, err := os.users.GetUserByID(ctx, userID)
+	if err != nil {
+		log.Printf("[oidc][ERROR] get user by id: %v", err)
+		return nil, "", err
+	}
+	if user == nil {
+		return nil, "", ErrUserNotFound
+	}
+	if user.IsSuspended() {
+		log.Printf("[oidc][ERROR] user with id=%d is suspended", userID)
+		return nil, "", ErrUserSuspended
+	}
+
+	if err := os.identities.TouchLogin(ctx, providerName, claims.Subject); err != nil {
+		log.Printf("[oidc][ERROR] touch identity login: %v", err)
+	}
+
+	log.Printf("[oidc] CompleteLogin succesful: provider=%s userID=%d", providerName, userID)
+	return user, req.DeviceName, nil
+}
+
+// resolveUser находит или создаёт пользователя для внешней учётной записи.
+// Одновременные первые входы одной учётной записи упираются в уникальность (provider, subject):
+// проигравший запрос перечитывает уже созданную привязку.
+func (os *OIDCService) resolveUser(ctx context.Context, providerName string, claims *oidcEntity.ExternalClaims) (int, error) {
+	for attempt := 0; attempt < 2; attempt++ {
+		userID, err := os.identities.GetUserID(ctx, providerName, claims.Subject)
+		if err != nil {
+			log.Printf("[oidc][ERROR] get identity: %v", err)
+			return 0, err
+		}
+		if userID != 0 {
+			return userID, nil
+		}
+
+		// непроверенный email мог указать кто угодно: по нему нельзя ни привязать, ни завести учётную запись
+		if !claims.EmailVerified || claims.Email == "" {
+			log.Printf("[oidc][ERROR] email not verified: provider=%s sub=%s", providerName, claims.Subject)
+			return 0, oidcEntity.ErrEmailNotVerified
+		}
+
+		identity := oidcEntity.NewIdentity(0, providerName, claims.Subject, claims.Email)
+
+		existing, err := os.users.GetUserByEmail(ctx, claims.Email)
+		if err != nil {
+			log.Printf("[oidc][ERROR] get user by email: %v", err)
+			return 0, err
+		}
+		// учётную запись с неподтверждённым email мог заранее завести злоумышленник и сохранить доступ по своему паролю
+		if existing != nil && !existing.IsEmailVerified() {
+			log.Printf("[oidc][ERROR] local user with id=%d has unverified email: provider=%s sub=%s", existing.ID, providerName, claims.Subject)
+			return 0, oidcEntity.ErrLocalAccountNotVerified
+		}
+		if existing != nil {
+			identity.UserID = existing.ID
+			err = os.identities.Link(ctx, identity)
+		} else {
+			_, err = os.identities.CreateUserWithIdentity(ctx, claims.Email, identity)
+		}
+		if errors.Is(err, oidcEntity.ErrIdentityExists) {
+			continue
+		}
+		if err != nil {
+			log.Printf("[oidc][ERROR] link identity: %v", err)
+			return 0, err
+		}
+
+		log.Printf("[oidc] identity linked: provider=%s sub=%s userID=%d", providerName, claims.Subject, identity.UserID)
+		return identity.UserID, nil
+	}
+
+	return 0, oidcEntity.ErrIdentityExists
+}
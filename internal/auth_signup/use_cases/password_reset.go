@@ -0,0 +1,169 @@
+package use_cases
+
+import (
+	"context"
+	"fmt"
+	"github.com/1URose/marketplace/internal/auth_signup/domain/password_reset/entity"
+	"github.com/1URose/marketplace/internal/auth_signup/domain/password_reset/repository"
+	eventEntity "github.com/1URose/marketplace/internal/auth_signup/domain/security_event/entity"
+	"github.com/1URose/marketplace/internal/common/config/common"
+	"github.com/1URose/marketplace/internal/common/mailer"
+	"github.com/1URose/marketplace/internal/common/password"
+	"log"
+	"time"
+)
+
+// PasswordResetService восстанавливает доступ по одноразовой ссылке, отправленной на email пользователя.
+type PasswordResetService struct {
+	auth   *AuthService
+	tokens repository.ResetTokenRepository
+	mailer mailer.Mailer
+	cfg    common.PasswordReset
+}
+
+func NewPasswordResetService(
+	auth *AuthService,
+	tokens repository.ResetTokenRepository,
+	mailer mailer.Mailer,
+	cfg common.PasswordReset,
+) *PasswordResetService {
+	log.Println("[password_reset] initializing PasswordResetService")
+
+	return &PasswordResetService{
+		auth:   auth,
+		tokens: tokens,
+		mailer: mailer,
+		cfg:    cfg,
+	}
+}
+
+// Forgot отправляет ссылку сброса, если учётная запись с таким email существует и не заблокирована.
+// Об отсутствии пользователя не сообщается, чтобы запрос нельзя было использовать для проверки email;
+// повторные запросы в пределах Cooldown тоже молча пропускаются.
+func (ps *PasswordResetService) Forgot(ctx context.Context, email string) error {
+	log.Printf("[password_reset] Forgot called: email=%s", email)
+
+	user, err := ps.auth.UserRepo.GetUserByEmail(ctx, email)
+	if err != nil {
+		log.Printf("[password_reset][ERROR] get user by email: %v", err)
+		return err
+	}
+	if user == nil || user.IsSuspended() {
+		log.Printf("[password_reset] Forgot: no active user with email=%s, nothing sent", email)
+		return nil
+	}
+
+	acquired, err := ps.tokens.AcquireSendWindow(ctx, user.ID, ps.cfg.Cooldown)
+	if err != nil {
+		log.Printf("[password_reset][ERROR] acquire send window: %v", err)
+		return err
+	}
+	if !acquired {
+		log.Printf("[password_reset] Forgot: reset email recently sent to userID=%d, skipping", user.ID)
+		return nil
+	}
+
+	raw, hash, err := entity.NewResetToken()
+	if err != nil {
+		log.Printf("[password_reset][ERROR] generate token: %v", err)
+		return err
+	}
+	if err := ps.tokens.Save(ctx, user.ID, hash, ps.cfg.TokenTTL); err != nil {
+		log.Printf("[password_reset][ERROR] save token: %v", err)
+		return err
+	}
+
+	link, err := linkWithToken(ps.cfg.LinkURL, raw)
+	if err != nil {
+		return err
+	}
+
+	msg := mailer.Message{
+		To:      user.Email,
+		Subject: "Восстановление пароля на Marketplace",
+		Body: fmt.Sprintf(
+			"Здравствуйте!\n\nМы получили запрос на сброс пароля для %s. Чтобы задать новый пароль, перейдите по ссылке:\n%s\n\n"+
+				"Ссылка действует %d мин. и сработает один раз. Если вы не запрашивали сброс, просто проигнорируйте это письмо — пароль останется прежним.\n",
+			user.Email, link, int(ps.cfg.TokenTTL.Minutes()),
+		),
+	}
+	if err := ps.mailer.Send(ctx, msg); err != nil {
+		log.Printf("[password_reset][ERROR] send reset email: %v", err)
+		return err
+	}
+
+	log.Printf("[password_reset] reset email sent: userID=%d", user.ID)
+	return nil
+}
+
+// Reset задаёт новый пароль по токену из письма, закрывает все сессии пользователя и отзывает выданные токены.
+func (ps *PasswordResetService) Reset(ctx context.Context, token, newPassword, ip, userAgent string) error {
+	log.Printf("[password_reset] Reset called")
+
+	tokenHash := entity.HashToken(token)
+
+	// токен расходуется только после проверки пароля политикой, чтобы отказ не заставлял запрашивать новое письмо
+	userID, err := ps.tokens.Peek(ctx, tokenHash)
+	if err != nil {
+		log.Printf("[password_reset][ERROR] peek token: %v", err)
+		return err
+	}
+	if userID == 0 {
+		return entity.ErrInvalidResetToken
+	}
+
+	user, err := ps.auth.UserRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		log.Printf("[password_reset][ERROR] get user by id: %v", err)
+		return err
+	}
+	if user == nil {
+		return entity.ErrInvalidResetToken
+	}
+
+	if err := ps.auth.passwordPolicy.Validate(ctx, newPassword, user.Email); err != nil {
+		return err
+	}
+
+	takenBy, err := ps.tokens.Take(ctx, tokenHash)
+	if err != nil {
+		log.Printf("[password_reset][ERROR] take token: %v", err)
+		return err
+	}
+	// параллельный запрос с тем же токеном уже успел его израсходовать
+	if takenBy != user.ID {
+		return entity.ErrInvalidResetToken
+	}
+
+	passwordHash, err := password.HashPassword(newPassword)
+	if err != nil {
+		log.Printf("[password_reset][ERROR] hashing password: %v", err)
+		return err
+	}
+	if err := ps.auth.UserRepo.UpdatePassword(ctx, user.ID, passwordHash); err != nil {
+		log.Printf("[password_reset][ERROR] update password: %v", err)
+		return err
+	}
+
+	// старый пароль мог попасть в чужие руки, поэтому все открытые сессии закрываются
+	if err := ps.auth.LogoutAll(ctx, user.ID, ip, userAgent); err != nil {
+		log.Printf("[password_reset][ERROR] revoke sessions: %v", err)
+		return err
+	}
+
+	ps.auth.recordEvent(ctx, eventEntity.NewSecurityEvent(eventEntity.TypePasswordReset, user.ID, "", ip, userAgent, time.Now()))
+
+	msg := mailer.Message{
+		To:      user.Email,
+		Subject: "Пароль на Marketplace изменён",
+		Body: "Здравствуйте!\n\nПароль вашей учётной записи был сброшен, на всех устройствах выполнен выход.\n\n" +
+			"Если это были не вы, немедленно восстановите доступ через «Забыли пароль?» и свяжитесь с поддержкой.\n",
+	}
+	if err := ps.mailer.Send(ctx, msg); err != nil {
+		// пароль уже изменён, письмо лишь уведомление
+		log.Printf("[password_reset][ERROR] send confirmation email: %v", err)
+	}
+
+	log.Printf("[password_reset] Reset succesful: userID=%d", user.ID)
+	return nil
+}
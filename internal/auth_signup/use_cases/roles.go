@@ -0,0 +1,52 @@
+package use_cases
+
+import (
+	"context"
+	eventEntity "github.com/1URose/marketplace/internal/auth_signup/domain/security_event/entity"
+	"github.com/1URose/marketplace/internal/user_profile/domain/user/entity"
+	"log"
+	"time"
+)
+
+// SetRole назначает пользователю роль. Роль хранится в access-токене, поэтому при понижении
+// все токены пользователя отзываются; повышение вступает в силу при следующем refresh.
+func (as *AuthService) SetRole(ctx context.Context, adminID, userID int, role, ip, userAgent string) (*entity.User, error) {
+	log.Printf("[auth] SetRole called: adminID=%d userID=%d role=%s", adminID, userID, role)
+
+	if !entity.IsValidRole(role) {
+		return nil, ErrInvalidRole
+	}
+	if adminID == userID {
+		return nil, ErrOwnRoleChange
+	}
+
+	user, err := as.UserRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		log.Printf("[auth][ERROR] get user by id: %v", err)
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+	if user.Role == role {
+		return user, nil
+	}
+
+	if err := as.UserRepo.SetRole(ctx, userID, role); err != nil {
+		log.Printf("[auth][ERROR] set role: %v", err)
+		return nil, err
+	}
+
+	if entity.RoleRank(role) < entity.RoleRank(user.Role) {
+		if err := as.LogoutAll(ctx, userID, ip, userAgent); err != nil {
+			log.Printf("[auth][ERROR] revoke tokens after demotion: %v", err)
+			return nil, err
+		}
+	}
+
+	as.recordEvent(ctx, eventEntity.NewSecurityEvent(eventEntity.TypeRoleChanged, userID, "", ip, userAgent, time.Now()))
+
+	log.Printf("[auth] SetRole succesful: userID=%d %s -> %s", userID, user.Role, role)
+	user.Role = role
+	return user, nil
+}
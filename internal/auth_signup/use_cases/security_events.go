@@ -0,0 +1,95 @@
+package use_cases
+
+import (
+	"context"
+	"github.com/1URose/marketplace/internal/auth_signup/domain/security_event/entity"
+	"github.com/1URose/marketplace/internal/auth_signup/domain/security_event/repository"
+	"github.com/1URose/marketplace/internal/common/clock"
+	"github.com/1URose/marketplace/internal/common/config/common"
+	"log"
+	"math"
+	"time"
+)
+
+// SecurityEventService отдаёт журнал событий безопасности и удаляет события старше срока хранения.
+type SecurityEventService struct {
+	events        repository.SecurityEventRepository
+	pageSize      int
+	retention     time.Duration
+	purgeInterval time.Duration
+	clock         clock.Clock
+}
+
+func NewSecurityEventService(events repository.SecurityEventRepository, cfg common.SecurityEvents, clk clock.Clock) *SecurityEventService {
+	log.Printf("[usecase:security_events] NewSecurityEventService initialized: retention=%s pageSize=%d",
+		cfg.Retention, cfg.PageSize,
+	)
+	return &SecurityEventService{
+		events:        events,
+		pageSize:      cfg.PageSize,
+		retention:     cfg.Retention,
+		purgeInterval: cfg.PurgeInterval,
+		clock:         clk,
+	}
+}
+
+// List возвращает страницу событий по фильтру, начиная с самых новых, и общее количество страниц.
+// Страница за пределами журнала пуста.
+func (ss *SecurityEventService) List(ctx context.Context, filter *entity.Filter, page int) ([]*entity.SecurityEvent, int, error) {
+	log.Printf("[usecase:security_events] List called: filter=%+v page=%d", filter, page)
+
+	for _, t := range filter.Types {
+		if !entity.IsKnownType(t) {
+			return nil, 0, entity.ErrUnknownType.WithDetail(t)
+		}
+	}
+
+	total, err := ss.events.Count(ctx, filter)
+	if err != nil {
+		log.Printf("[usecase:security_events][ERROR] Count failed: %v", err)
+		return nil, 0, err
+	}
+	countPages := int(math.Ceil(float64(total) / float64(ss.pageSize)))
+	if page > countPages {
+		return []*entity.SecurityEvent{}, countPages, nil
+	}
+
+	events, err := ss.events.List(ctx, filter, (page-1)*ss.pageSize, ss.pageSize)
+	if err != nil {
+		log.Printf("[usecase:security_events][ERROR] List failed: %v", err)
+		return nil, 0, err
+	}
+
+	log.Printf("[usecase:security_events] List succeeded: events=%d countPages=%d", len(events), countPages)
+	return events, countPages, nil
+}
+
+// Run периодически удаляет события старше срока хранения, пока не отменён ctx.
+func (ss *SecurityEventService) Run(ctx context.Context) {
+	log.Println("[usecase:security_events] retention purge started")
+
+	ticker := time.NewTicker(ss.purgeInterval)
+	defer ticker.Stop()
+
+	for {
+		ss.Purge(ctx)
+
+		select {
+		case <-ctx.Done():
+			log.Println("[usecase:security_events] retention purge stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (ss *SecurityEventService) Purge(ctx context.Context) {
+	deleted, err := ss.events.DeleteBefore(ctx, ss.clock.Now().Add(-ss.retention))
+	if err != nil {
+		log.Printf("[usecase:security_events][ERROR] DeleteBefore failed: %v", err)
+		return
+	}
+	if deleted > 0 {
+		log.Printf("[usecase:security_events] expired events purged: deleted=%d", deleted)
+	}
+}
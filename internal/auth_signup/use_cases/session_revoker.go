@@ -0,0 +1,79 @@
+package use_cases
+
+import (
+	"context"
+	"fmt"
+	redisRepo "github.com/1URose/marketplace/internal/auth_signup/domain/redis/repository"
+	eventEntity "github.com/1URose/marketplace/internal/auth_signup/domain/security_event/entity"
+	eventRepo "github.com/1URose/marketplace/internal/auth_signup/domain/security_event/repository"
+	"log"
+	"time"
+)
+
+// SessionRevoker закрывает сессии пользователя и отзывает их токены. Это единственная часть аутентификации,
+// нужная другим модулям: например, очередь жалоб закрывает сессии автора при его блокировке.
+type SessionRevoker struct {
+	sessions redisRepo.RedisRepository
+	tokens   SessionTokenRevoker
+	events   eventRepo.SecurityEventRepository
+	// revocationTTL — сколько хранить отметку logout-all: не меньше срока жизни самого долгого токена.
+	revocationTTL time.Duration
+}
+
+func NewSessionRevoker(
+	sessions redisRepo.RedisRepository,
+	tokens SessionTokenRevoker,
+	events eventRepo.SecurityEventRepository,
+	revocationTTL time.Duration,
+) *SessionRevoker {
+	log.Printf("[auth:sessions] NewSessionRevoker initialized: revocationTTL=%s", revocationTTL)
+
+	return &SessionRevoker{
+		sessions:      sessions,
+		tokens:        tokens,
+		events:        events,
+		revocationTTL: revocationTTL,
+	}
+}
+
+// LogoutAll закрывает все сессии и отзывает все токены пользователя, выданные до текущего момента.
+// ip и userAgent — того, кто выполнил выход: самого пользователя, администратора или модератора.
+func (sr *SessionRevoker) LogoutAll(ctx context.Context, userID int, ip, userAgent string) error {
+	log.Printf("[auth:sessions] LogoutAll called: userID=%d", userID)
+
+	// отзываются ровно удалённые сессии: сессия, созданная параллельным входом, не останется без отзыва
+	ids, err := sr.sessions.DeleteAll(ctx, userID)
+	if err != nil {
+		log.Printf("[auth:sessions][ERROR] delete sessions: %v", err)
+		return err
+	}
+
+	// отметка нужна refresh-токенам и токенам без sid; access-токены сессий отзываются через denylist
+	if err := sr.sessions.SetTokensValidAfter(ctx, userID, time.Now(), sr.revocationTTL); err != nil {
+		log.Printf("[auth:sessions][ERROR] set tokens valid after: %v", err)
+		return err
+	}
+
+	if err := sr.revokeSessionTokens(ctx, ids...); err != nil {
+		return err
+	}
+
+	event := eventEntity.NewSecurityEvent(eventEntity.TypeLogoutAll, userID, "", ip, userAgent, time.Now())
+	if err := sr.events.Record(ctx, event); err != nil {
+		log.Printf("[auth:sessions][ERROR] record security event: %v", err)
+	}
+
+	log.Printf("[auth:sessions] LogoutAll succesful: userID=%d sessions=%d", userID, len(ids))
+	return nil
+}
+
+// revokeSessionTokens вносит закрытые сессии в denylist.
+func (sr *SessionRevoker) revokeSessionTokens(ctx context.Context, sessionIDs ...string) error {
+	for _, id := range sessionIDs {
+		if err := sr.tokens.RevokeSession(ctx, id); err != nil {
+			log.Printf("[auth:sessions][ERROR] revoke tokens of session sid=%s: %v", id, err)
+			return fmt.Errorf("revoke session tokens: %w", err)
+		}
+	}
+	return nil
+}
@@ -2,10 +2,17 @@ package app
 
 import (
 	"context"
+	"fmt"
+	authPostgres "github.com/1URose/marketplace/internal/auth_signup/infrastructure/repository/postgresql"
+	"github.com/1URose/marketplace/internal/auth_signup/infrastructure/repository/redis"
 	"github.com/1URose/marketplace/internal/auth_signup/transport/rest/auth"
+	"github.com/1URose/marketplace/internal/auth_signup/use_cases"
 	"github.com/1URose/marketplace/internal/common/config"
 	"github.com/1URose/marketplace/internal/common/db"
 	"github.com/1URose/marketplace/internal/common/jwt"
+	"github.com/1URose/marketplace/internal/common/mailer"
+	"github.com/1URose/marketplace/internal/common/password"
+	"github.com/1URose/marketplace/internal/user_profile/infrastructure/repository/postgresql"
 	"github.com/gin-gonic/gin"
 )
 
@@ -16,12 +23,52 @@ type Deps struct {
 	GeneralConfig  *config.GeneralConfig
 	JWTManager     *jwt.Manager
 	AuthMiddleware *auth.Middleware
+	Mailer         mailer.Mailer
+	PasswordPolicy *password.Policy
+	// SessionRevoker закрывает все сессии пользователя; нужен модулям вне аутентификации (блокировка автора).
+	SessionRevoker *use_cases.SessionRevoker
 }
 
-func NewDeps(ctx context.Context, engine *gin.Engine, connections *db.Connections, generalCfg *config.GeneralConfig) *Deps {
-	jwtMgr := jwt.NewManager(generalCfg.CommonConfig)
+func NewDeps(ctx context.Context, engine *gin.Engine, connections *db.Connections, generalCfg *config.GeneralConfig) (*Deps, error) {
+	keyset, err := jwt.LoadKeyset(generalCfg.CommonConfig)
+	if err != nil {
+		return nil, fmt.Errorf("load JWT keyset: %w", err)
+	}
+
+	denylist := jwt.NewCachedDenylist(
+		redis.NewDenylistRepository(connections.RedisConn),
+		generalCfg.CommonConfig.DenylistCacheTTL,
+		generalCfg.CommonConfig.DenylistCacheSize,
+	)
+	jwtMgr := jwt.NewManager(generalCfg.CommonConfig, keyset, denylist)
+
+	if err := ConfigurePasswordHashing(generalCfg.PasswordConfig); err != nil {
+		return nil, fmt.Errorf("configure password hashing: %w", err)
+	}
+
+	policy, err := NewPasswordPolicy(generalCfg.PolicyConfig)
+	if err != nil {
+		return nil, fmt.Errorf("init password policy: %w", err)
+	}
+
+	m, err := mailer.New(generalCfg.MailConfig)
+	if err != nil {
+		return nil, fmt.Errorf("init mailer: %w", err)
+	}
 
-	authMiddleware := auth.NewMiddleware(generalCfg.CommonConfig.BearerPrefix, jwtMgr)
+	userR := postgresql.NewUserRepository(connections.PostgresConn)
+	sessionsR := redis.NewRedisRepository(connections.RedisConn, generalCfg.CommonConfig.RefreshTTL)
+
+	authMiddleware := auth.NewMiddleware(
+		generalCfg.CommonConfig.BearerPrefix,
+		jwtMgr,
+		sessionsR,
+		use_cases.NewAPIKeyService(
+			authPostgres.NewAPIKeyRepository(connections.PostgresConn),
+			userR,
+		),
+		userR,
+	)
 
 	return &Deps{
 		Ctx:            ctx,
@@ -30,5 +77,13 @@ func NewDeps(ctx context.Context, engine *gin.Engine, connections *db.Connection
 		GeneralConfig:  generalCfg,
 		JWTManager:     jwtMgr,
 		AuthMiddleware: authMiddleware,
-	}
+		Mailer:         m,
+		PasswordPolicy: policy,
+		SessionRevoker: use_cases.NewSessionRevoker(
+			sessionsR,
+			jwtMgr,
+			authPostgres.NewSecurityEventRepository(connections.PostgresConn),
+			generalCfg.CommonConfig.RefreshTTL,
+		),
+	}, nil
 }
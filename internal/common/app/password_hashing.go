@@ -0,0 +1,23 @@
+package app
+
+import (
+	"github.com/1URose/marketplace/internal/common/config/password_hashing"
+	"github.com/1URose/marketplace/internal/common/password"
+)
+
+// ConfigurePasswordHashing задаёт алгоритм, которым хешируются новые пароли.
+func ConfigurePasswordHashing(cfg *password_hashing.Config) error {
+	hasher, err := password.NewHasher(password.Params{
+		Algorithm:     cfg.Algorithm,
+		Argon2Memory:  cfg.Argon2Memory,
+		Argon2Time:    cfg.Argon2Time,
+		Argon2Threads: cfg.Argon2Threads,
+		BcryptCost:    cfg.BcryptCost,
+	})
+	if err != nil {
+		return err
+	}
+
+	password.SetDefault(hasher)
+	return nil
+}
@@ -0,0 +1,25 @@
+package app
+
+import (
+	"github.com/1URose/marketplace/internal/common/config/password_policy"
+	"github.com/1URose/marketplace/internal/common/password"
+)
+
+// NewPasswordPolicy собирает политику паролей и загружает набор утёкших паролей, если он задан.
+func NewPasswordPolicy(cfg *password_policy.Config) (*password.Policy, error) {
+	var breached password.BreachedSource
+	if cfg.BreachedDataset != "" {
+		source, err := password.NewFileBreachedSource(cfg.BreachedDataset)
+		if err != nil {
+			return nil, err
+		}
+		breached = source
+	}
+
+	return password.NewPolicy(password.PolicyRules{
+		MinLength:       cfg.MinLength,
+		MaxLength:       cfg.MaxLength,
+		RequiredClasses: cfg.RequiredClasses,
+		RejectEmail:     cfg.RejectEmail,
+	}, breached)
+}
@@ -0,0 +1,154 @@
+// Package apperror описывает ошибки прикладного уровня, которые middleware ErrorHandler
+// переводит в HTTP-ответы. Сервисы возвращают такие ошибки, а обработчики передают их в
+// ctx.Error, не выбирая статус сами; остальные ошибки считаются внутренними и клиенту не показываются.
+// Тексты для клиента берутся из каталога i18n по ключу и переводятся на язык запроса.
+package apperror
+
+import (
+	"errors"
+	"time"
+
+	"github.com/1URose/marketplace/internal/common/i18n"
+)
+
+// Kind — класс ошибки, по которому выбирается HTTP-статус.
+type Kind string
+
+const (
+	KindValidation   Kind = "validation"
+	KindUnauthorized Kind = "unauthorized"
+	KindForbidden    Kind = "forbidden"
+	KindNotFound     Kind = "not_found"
+	KindConflict     Kind = "conflict"
+	KindRateLimited  Kind = "rate_limited"
+	KindInternal     Kind = "internal"
+)
+
+// Violation — нарушение, относящееся к одному полю запроса.
+type Violation struct {
+	Field string
+	Code  string
+	// Key — ключ сообщения в каталоге i18n; Message — то же сообщение на языке по умолчанию.
+	Key     string
+	Message string
+	// Params — значения, подставленные в Message, например {"min": 3}; по ним клиент может построить свой текст.
+	Params map[string]interface{}
+}
+
+type Error struct {
+	Kind Kind
+	// Key — ключ сообщения в каталоге i18n, по нему ErrorHandler выбирает перевод.
+	Key string
+	// Message — сообщение на языке по умолчанию для логов. Показывается клиенту, поэтому не должен
+	// содержать внутренних подробностей.
+	Message string
+	// Detail — необязательное уточнение для клиента; если задан DetailKey, Detail переводится по нему.
+	Detail     string
+	DetailKey  string
+	Violations []Violation
+	// RetryAfter — через сколько можно повторить запрос; только для KindRateLimited.
+	RetryAfter time.Duration
+
+	// cause попадает только в лог.
+	cause error
+	// parent — ошибка, копией которой получена эта; по цепочке parent работает errors.Is.
+	parent *Error
+}
+
+// New создаёт ошибку с сообщением из каталога i18n по ключу key.
+func New(kind Kind, key string) *Error {
+	return &Error{Kind: kind, Key: key, Message: i18n.Message(i18n.Default, key, nil)}
+}
+
+func Validation(key string) *Error   { return New(KindValidation, key) }
+func Unauthorized(key string) *Error { return New(KindUnauthorized, key) }
+func Forbidden(key string) *Error    { return New(KindForbidden, key) }
+func NotFound(key string) *Error     { return New(KindNotFound, key) }
+func Conflict(key string) *Error     { return New(KindConflict, key) }
+func Internal(key string) *Error     { return New(KindInternal, key) }
+
+func RateLimited(key string, retryAfter time.Duration) *Error {
+	e := New(KindRateLimited, key)
+	e.RetryAfter = retryAfter
+	return e
+}
+
+func (e *Error) Error() string {
+	msg := e.Message
+	if e.Detail != "" {
+		msg += ": " + e.Detail
+	}
+	if e.cause != nil {
+		msg += ": " + e.cause.Error()
+	}
+	return msg
+}
+
+func (e *Error) Unwrap() error {
+	return e.cause
+}
+
+// Is считает копию, полученную через Wrap или With*, той же ошибкой, что и исходный образец.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	if !ok {
+		return false
+	}
+	for p := e.parent; p != nil; p = p.parent {
+		if p == t {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *Error) clone() *Error {
+	c := *e
+	c.Violations = append([]Violation(nil), e.Violations...)
+	c.parent = e
+	return &c
+}
+
+// Wrap возвращает копию ошибки с внутренней причиной, которая попадёт в лог, но не в ответ.
+func (e *Error) Wrap(cause error) *Error {
+	c := e.clone()
+	c.cause = cause
+	return c
+}
+
+// WithDetail добавляет уточнение как есть, без перевода: например, отклонённое значение.
+func (e *Error) WithDetail(detail string) *Error {
+	c := e.clone()
+	c.Detail = detail
+	c.DetailKey = ""
+	return c
+}
+
+// WithDetailKey добавляет уточнение из каталога i18n.
+func (e *Error) WithDetailKey(key string) *Error {
+	c := e.clone()
+	c.Detail = i18n.Message(i18n.Default, key, nil)
+	c.DetailKey = key
+	return c
+}
+
+func (e *Error) WithRetryAfter(retryAfter time.Duration) *Error {
+	c := e.clone()
+	c.RetryAfter = retryAfter
+	return c
+}
+
+func (e *Error) WithViolations(violations ...Violation) *Error {
+	c := e.clone()
+	c.Violations = append(c.Violations, violations...)
+	return c
+}
+
+// From находит ошибку прикладного уровня в цепочке err.
+func From(err error) (*Error, bool) {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr, true
+	}
+	return nil, false
+}
@@ -0,0 +1,26 @@
+package clock
+
+import "time"
+
+// Clock отдаёт текущее время. Сервисы, логика которых зависит от времени,
+// получают его через конструктор, чтобы в тестах время можно было зафиксировать.
+type Clock interface {
+	Now() time.Time
+}
+
+type System struct{}
+
+func NewSystem() *System {
+	return &System{}
+}
+
+func (System) Now() time.Time {
+	return time.Now()
+}
+
+// Func позволяет передать в качестве Clock обычную функцию.
+type Func func() time.Time
+
+func (f Func) Now() time.Time {
+	return f()
+}
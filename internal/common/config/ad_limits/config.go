@@ -1,8 +1,12 @@
 package ad_limits
 
 import (
+	"fmt"
 	"github.com/1URose/marketplace/internal/common/settings"
 	"log"
+	"strconv"
+	"strings"
+	"time"
 )
 
 type AdConfig struct {
@@ -19,6 +23,16 @@ type AdConfig struct {
 	MaxPrice          int
 	MaxImageFileSize  int64
 	AllowedImageTypes string
+
+	ReportAutoHideThreshold int
+
+	PromotedSlots int
+
+	PublishSchedulerInterval time.Duration
+
+	AllowedCategories string
+	// PriceFacetEdges — границы ценовых диапазонов фасета price, по возрастанию.
+	PriceFacetEdges []int
 }
 
 func NewAdConfig(
@@ -27,6 +41,11 @@ func NewAdConfig(
 	minTitle, maxTitle, minDesc, maxDesc, minPrice, maxPrice int,
 	maxImgSize64 int64,
 	imgTypes string,
+	reportAutoHideThreshold int,
+	promotedSlots int,
+	publishSchedulerInterval time.Duration,
+	categories string,
+	priceFacetEdges []int,
 ) *AdConfig {
 	return &AdConfig{
 		AllowedSortFields: sortFields,
@@ -40,6 +59,15 @@ func NewAdConfig(
 		MaxPrice:          maxPrice,
 		MaxImageFileSize:  maxImgSize64,
 		AllowedImageTypes: imgTypes,
+
+		ReportAutoHideThreshold: reportAutoHideThreshold,
+
+		PromotedSlots: promotedSlots,
+
+		PublishSchedulerInterval: publishSchedulerInterval,
+
+		AllowedCategories: categories,
+		PriceFacetEdges:   priceFacetEdges,
 	}
 }
 
@@ -56,11 +84,17 @@ func LoadAdConfigFromEnv() *AdConfig {
 		envMaxPrice        = "ADS_MAX_PRICE"
 		envMaxImageSize    = "ADS_MAX_IMAGE_SIZE"
 		envAllowedImgTypes = "ADS_ALLOWED_IMAGE_TYPES"
+		envReportAutoHide  = "ADS_REPORT_AUTO_HIDE_THRESHOLD"
+		envPromotedSlots   = "ADS_PROMOTED_SLOTS"
+		envPublishInterval = "ADS_PUBLISH_SCHEDULER_INTERVAL_SECONDS"
+		envCategories      = "ADS_ALLOWED_CATEGORIES"
+		envPriceFacetEdges = "ADS_PRICE_FACET_EDGES"
 	)
 
 	sortFields := settings.GetEnvSrt(envSortFields)
 	sortOrders := settings.GetEnvSrt(envSortOrders)
 	imgTypes := settings.GetEnvSrt(envAllowedImgTypes)
+	categories := settings.GetEnvSrt(envCategories)
 
 	pageSize, err := settings.GetEnvInt(envPageSize)
 	if err != nil {
@@ -101,6 +135,27 @@ func LoadAdConfigFromEnv() *AdConfig {
 	}
 	maxImgSize64 := int64(maxImgSize)
 
+	reportAutoHide, err := settings.GetEnvInt(envReportAutoHide)
+	if err != nil {
+		log.Panicf("[ad_limits][FATAL] invalid %s: %v", envReportAutoHide, err)
+	}
+
+	promotedSlots, err := settings.GetEnvInt(envPromotedSlots)
+	if err != nil {
+		log.Panicf("[ad_limits][FATAL] invalid %s: %v", envPromotedSlots, err)
+	}
+
+	publishIntervalSec, err := settings.GetEnvInt(envPublishInterval)
+	if err != nil || publishIntervalSec <= 0 {
+		log.Panicf("[ad_limits][FATAL] invalid %s: %v", envPublishInterval, err)
+	}
+	publishInterval := time.Duration(publishIntervalSec) * time.Second
+
+	priceFacetEdges, err := parseEdges(settings.GetEnvSrt(envPriceFacetEdges))
+	if err != nil {
+		log.Panicf("[ad_limits][FATAL] invalid %s: %v", envPriceFacetEdges, err)
+	}
+
 	ac := NewAdConfig(
 		sortFields,
 		sortOrders,
@@ -113,10 +168,15 @@ func LoadAdConfigFromEnv() *AdConfig {
 		maxPrice,
 		maxImgSize64,
 		imgTypes,
+		reportAutoHide,
+		promotedSlots,
+		publishInterval,
+		categories,
+		priceFacetEdges,
 	)
 
 	log.Printf(
-		"[ad_limits:config] loaded: sortFields=%s sortOrders=%s pageSize=%d minTitle=%d maxTitle=%d minDesc=%d maxDesc=%d minPrice=%d maxPrice=%d maxImgSize=%d imgTypes=%s",
+		"[ad_limits:config] loaded: sortFields=%s sortOrders=%s pageSize=%d minTitle=%d maxTitle=%d minDesc=%d maxDesc=%d minPrice=%d maxPrice=%d maxImgSize=%d imgTypes=%s reportAutoHide=%d promotedSlots=%d publishInterval=%s categories=%s priceFacetEdges=%v",
 		sortFields,
 		sortOrders,
 		pageSize,
@@ -128,7 +188,46 @@ func LoadAdConfigFromEnv() *AdConfig {
 		maxPrice,
 		maxImgSize,
 		imgTypes,
+		reportAutoHide,
+		promotedSlots,
+		publishInterval,
+		categories,
+		priceFacetEdges,
 	)
 
 	return ac
 }
+
+// Categories возвращает список допустимых категорий в порядке из конфигурации.
+func (ac *AdConfig) Categories() []string {
+	categories := make([]string, 0)
+	for _, c := range strings.Split(ac.AllowedCategories, ",") {
+		c = strings.ToLower(strings.TrimSpace(c))
+		if c != "" {
+			categories = append(categories, c)
+		}
+	}
+	return categories
+}
+
+func parseEdges(raw string) ([]int, error) {
+	edges := make([]int, 0)
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		edge, err := strconv.Atoi(part)
+		if err != nil {
+			return nil, fmt.Errorf("edge %q: %w", part, err)
+		}
+		if len(edges) > 0 && edge <= edges[len(edges)-1] {
+			return nil, fmt.Errorf("edges must be strictly ascending: %d after %d", edge, edges[len(edges)-1])
+		}
+		edges = append(edges, edge)
+	}
+	if len(edges) == 0 {
+		return nil, fmt.Errorf("no edges")
+	}
+	return edges, nil
+}
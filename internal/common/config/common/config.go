@@ -3,26 +3,141 @@ package common
 import (
 	"github.com/1URose/marketplace/internal/common/settings"
 	"log"
+	"strconv"
 	"strings"
 	"time"
 )
 
+// LoginThrottle — пороги защиты входа от перебора паролей.
+type LoginThrottle struct {
+	// FailureWindow — сколько помнить неудачные попытки входа с первой из них.
+	FailureWindow time.Duration
+	// BackoffBase и BackoffMax — первая и максимальная задержка перед следующей попыткой; задержка удваивается.
+	BackoffBase time.Duration
+	BackoffMax  time.Duration
+	// LockoutDuration — на сколько блокируется вход после LockoutAfter неудач.
+	LockoutDuration time.Duration
+
+	AccountBackoffAfter int
+	AccountLockoutAfter int
+	// С одного IP может входить много пользователей (NAT, офис), поэтому его пороги обычно выше.
+	IPBackoffAfter int
+	IPLockoutAfter int
+}
+
+// EmailVerification — подтверждение email после регистрации.
+type EmailVerification struct {
+	// TokenTTL — сколько действует ссылка из письма.
+	TokenTTL time.Duration
+	// ResendCooldown — не чаще этого пользователь может запросить письмо повторно.
+	ResendCooldown time.Duration
+	// LinkURL — страница фронтенда, которая принимает ?token= и вызывает POST /auth/verify-email.
+	LinkURL string
+	// RequiredForAds — публиковать объявления могут только пользователи с подтверждённым email.
+	RequiredForAds bool
+}
+
+// PasswordReset — восстановление пароля по ссылке из письма.
+type PasswordReset struct {
+	// TokenTTL — сколько действует ссылка сброса; намеренно короткий.
+	TokenTTL time.Duration
+	// Cooldown — не чаще этого на один адрес отправляется новое письмо сброса.
+	Cooldown time.Duration
+	// LinkURL — страница фронтенда, которая принимает ?token= и вызывает POST /auth/password/reset.
+	LinkURL string
+}
+
+// MagicLink — вход без пароля по ссылке из письма.
+type MagicLink struct {
+	// TokenTTL — сколько действует ссылка входа.
+	TokenTTL time.Duration
+	// Cooldown — не чаще этого на один адрес отправляется новая ссылка.
+	Cooldown time.Duration
+	// LinkURL — страница фронтенда, которая принимает ?token= и вызывает POST /auth/magic-link/consume.
+	LinkURL string
+}
+
+// SecurityEvents — журнал событий безопасности.
+type SecurityEvents struct {
+	// Retention — сколько хранятся события; более старые удаляются фоновой очисткой.
+	Retention time.Duration
+	// PurgeInterval — как часто запускается очистка.
+	PurgeInterval time.Duration
+	// PageSize — сколько событий отдаётся на одной странице.
+	PageSize int
+}
+
+// Алгоритмы подписи JWT.
+const (
+	AlgHS256 = "HS256"
+	AlgRS256 = "RS256"
+	AlgEdDSA = "EdDSA"
+)
+
+// JWTKeys описывает набор ключей подписи JWT.
+// В режиме HS256 токены подписываются SECRET_KEY. В режимах RS256 и EdDSA каталог KeysDir содержит
+// PEM-ключи вида <kid>.pem: токены подписываются ключом SigningKeyID, а проверяются любым ключом каталога,
+// поэтому при ротации старый ключ остаётся в каталоге, пока не истекут выданные им токены.
+type JWTKeys struct {
+	SigningAlg   string
+	KeysDir      string
+	SigningKeyID string
+	// VerifyHS256 — продолжать принимать токены, подписанные SECRET_KEY, после перехода на асимметричную подпись.
+	VerifyHS256 bool
+}
+
 type Config struct {
 	GinAddress   string
 	BearerPrefix string
 
 	JWTSecret  string
+	JWTKeys    JWTKeys
 	AccessTTL  time.Duration
 	RefreshTTL time.Duration
+
+	// MaxSessionsPerUser — сколько устройств пользователь может держать в системе одновременно.
+	MaxSessionsPerUser int
+	// RefreshReuseGrace — сколько после ротации старый refresh-токен ещё принимается от клиента,
+	// повторяющего параллельный запрос, без признания его украденным.
+	RefreshReuseGrace time.Duration
+	// DenylistCacheTTL — сколько узел доверяет локально закэшированному ответу denylist отозванных токенов.
+	// Отзыв, сделанный через другой узел, вступает здесь в силу не позже чем через это время.
+	DenylistCacheTTL time.Duration
+	// DenylistCacheSize — максимум jti в локальном кэше denylist.
+	DenylistCacheSize int
+
+	LoginThrottle LoginThrottle
+
+	// MFAChallengeTTL — сколько действует токен, выданный после пароля, для ввода второго фактора.
+	MFAChallengeTTL time.Duration
+	// MFAIssuer — название сервиса в приложении-аутентификаторе.
+	MFAIssuer string
+
+	EmailVerification EmailVerification
+	PasswordReset     PasswordReset
+	MagicLink         MagicLink
+	SecurityEvents    SecurityEvents
 }
 
-func NewConfig(ginAddress, bearerPrefix, secretKey string, accessTTL, refreshTTL time.Duration) *Config {
+func NewConfig(ginAddress, bearerPrefix, secretKey string, accessTTL, refreshTTL time.Duration, maxSessions int, refreshReuseGrace, denylistCacheTTL time.Duration, denylistCacheSize int, loginThrottle LoginThrottle, mfaChallengeTTL time.Duration, mfaIssuer string, jwtKeys JWTKeys, emailVerification EmailVerification, passwordReset PasswordReset, magicLink MagicLink, securityEvents SecurityEvents) *Config {
 	return &Config{
-		GinAddress:   ginAddress,
-		BearerPrefix: bearerPrefix,
-		JWTSecret:    secretKey,
-		AccessTTL:    accessTTL,
-		RefreshTTL:   refreshTTL,
+		GinAddress:         ginAddress,
+		BearerPrefix:       bearerPrefix,
+		JWTSecret:          secretKey,
+		AccessTTL:          accessTTL,
+		RefreshTTL:         refreshTTL,
+		MaxSessionsPerUser: maxSessions,
+		RefreshReuseGrace:  refreshReuseGrace,
+		DenylistCacheTTL:   denylistCacheTTL,
+		DenylistCacheSize:  denylistCacheSize,
+		LoginThrottle:      loginThrottle,
+		MFAChallengeTTL:    mfaChallengeTTL,
+		MFAIssuer:          mfaIssuer,
+		JWTKeys:            jwtKeys,
+		EmailVerification:  emailVerification,
+		PasswordReset:      passwordReset,
+		MagicLink:          magicLink,
+		SecurityEvents:     securityEvents,
 	}
 }
 
@@ -33,6 +148,12 @@ func LoadCommonConfigFromEnv() *Config {
 		envSecret       = "SECRET_KEY"
 		envAccessTTL    = "ACCESS_TTL_MINUTES"  // в минутах
 		envRefreshTTL   = "REFRESH_TTL_MINUTES" // в минутах
+		envMaxSessions  = "MAX_SESSIONS_PER_USER"
+		envReuseGrace   = "REFRESH_REUSE_GRACE_SECONDS"
+		envDenylistTTL  = "TOKEN_DENYLIST_CACHE_SECONDS"
+		envDenylistSize = "TOKEN_DENYLIST_CACHE_SIZE"
+		envMFATTL       = "MFA_CHALLENGE_TTL_MINUTES"
+		envMFAIssuer    = "MFA_ISSUER"
 	)
 
 	addr := settings.GetEnvSrt(envGinAddr)
@@ -62,5 +183,233 @@ func LoadCommonConfigFromEnv() *Config {
 	refreshTTL := time.Duration(refreshSec) * time.Minute
 	log.Printf("[server:config] token TTLs: AccessTTL=%s, RefreshTTL=%s", accessTTL, refreshTTL)
 
-	return NewConfig(addr, bearer, secretKey, accessTTL, refreshTTL)
+	maxSessions, err := settings.GetEnvInt(envMaxSessions)
+	if err != nil || maxSessions < 1 {
+		log.Panicf("[server:config][FATAL] invalid %s: %v", envMaxSessions, err)
+	}
+	log.Printf("[server:config] loaded MAX_SESSIONS_PER_USER=%d", maxSessions)
+
+	graceSec, err := settings.GetEnvInt(envReuseGrace)
+	if err != nil || graceSec < 0 {
+		log.Panicf("[server:config][FATAL] invalid %s: %v", envReuseGrace, err)
+	}
+	reuseGrace := time.Duration(graceSec) * time.Second
+	log.Printf("[server:config] loaded REFRESH_REUSE_GRACE_SECONDS=%s", reuseGrace)
+
+	denylistSec, err := settings.GetEnvInt(envDenylistTTL)
+	if err != nil || denylistSec < 0 {
+		log.Panicf("[server:config][FATAL] invalid %s: %v", envDenylistTTL, err)
+	}
+	denylistTTL := time.Duration(denylistSec) * time.Second
+	denylistSize, err := settings.GetEnvInt(envDenylistSize)
+	if err != nil || denylistSize < 1 {
+		log.Panicf("[server:config][FATAL] invalid %s: %v", envDenylistSize, err)
+	}
+	log.Printf("[server:config] token denylist cache: ttl=%s size=%d", denylistTTL, denylistSize)
+
+	loginThrottle := loadLoginThrottle()
+
+	mfaMin, err := settings.GetEnvInt(envMFATTL)
+	if err != nil || mfaMin < 1 {
+		log.Panicf("[server:config][FATAL] invalid %s: %v", envMFATTL, err)
+	}
+	mfaTTL := time.Duration(mfaMin) * time.Minute
+	mfaIssuer := settings.GetEnvSrt(envMFAIssuer)
+	log.Printf("[server:config] MFA: challengeTTL=%s issuer=%q", mfaTTL, mfaIssuer)
+
+	return NewConfig(
+		addr, bearer, secretKey, accessTTL, refreshTTL, maxSessions, reuseGrace,
+		denylistTTL, denylistSize, loginThrottle, mfaTTL, mfaIssuer, loadJWTKeys(), loadEmailVerification(),
+		loadPasswordReset(), loadMagicLink(), loadSecurityEvents(),
+	)
+}
+
+func loadEmailVerification() EmailVerification {
+	const (
+		envTTL      = "EMAIL_VERIFICATION_TTL_HOURS"
+		envCooldown = "EMAIL_VERIFICATION_RESEND_COOLDOWN_SECONDS"
+		envLinkURL  = "EMAIL_VERIFICATION_URL"
+		envRequired = "REQUIRE_VERIFIED_EMAIL_FOR_ADS"
+	)
+
+	ttlHours, err := settings.GetEnvInt(envTTL)
+	if err != nil || ttlHours < 1 {
+		log.Panicf("[server:config][FATAL] invalid %s: %v", envTTL, err)
+	}
+	cooldownSec, err := settings.GetEnvInt(envCooldown)
+	if err != nil || cooldownSec < 1 {
+		log.Panicf("[server:config][FATAL] invalid %s: %v", envCooldown, err)
+	}
+	required, err := strconv.ParseBool(settings.GetEnvSrt(envRequired))
+	if err != nil {
+		log.Panicf("[server:config][FATAL] invalid %s: %v", envRequired, err)
+	}
+
+	ev := EmailVerification{
+		TokenTTL:       time.Duration(ttlHours) * time.Hour,
+		ResendCooldown: time.Duration(cooldownSec) * time.Second,
+		LinkURL:        settings.GetEnvSrt(envLinkURL),
+		RequiredForAds: required,
+	}
+	log.Printf("[server:config] email verification: ttl=%s resendCooldown=%s requiredForAds=%t",
+		ev.TokenTTL, ev.ResendCooldown, ev.RequiredForAds,
+	)
+	return ev
+}
+
+func loadPasswordReset() PasswordReset {
+	const (
+		envTTL      = "PASSWORD_RESET_TTL_MINUTES"
+		envCooldown = "PASSWORD_RESET_COOLDOWN_SECONDS"
+		envLinkURL  = "PASSWORD_RESET_URL"
+	)
+
+	ttlMin, err := settings.GetEnvInt(envTTL)
+	if err != nil || ttlMin < 1 {
+		log.Panicf("[server:config][FATAL] invalid %s: %v", envTTL, err)
+	}
+	cooldownSec, err := settings.GetEnvInt(envCooldown)
+	if err != nil || cooldownSec < 0 {
+		log.Panicf("[server:config][FATAL] invalid %s: %v", envCooldown, err)
+	}
+
+	pr := PasswordReset{
+		TokenTTL: time.Duration(ttlMin) * time.Minute,
+		Cooldown: time.Duration(cooldownSec) * time.Second,
+		LinkURL:  settings.GetEnvSrt(envLinkURL),
+	}
+	log.Printf("[server:config] password reset: ttl=%s cooldown=%s", pr.TokenTTL, pr.Cooldown)
+	return pr
+}
+
+func loadMagicLink() MagicLink {
+	const (
+		envTTL      = "MAGIC_LINK_TTL_MINUTES"
+		envCooldown = "MAGIC_LINK_COOLDOWN_SECONDS"
+		envLinkURL  = "MAGIC_LINK_URL"
+	)
+
+	ttlMin, err := settings.GetEnvInt(envTTL)
+	if err != nil || ttlMin < 1 {
+		log.Panicf("[server:config][FATAL] invalid %s: %v", envTTL, err)
+	}
+	cooldownSec, err := settings.GetEnvInt(envCooldown)
+	if err != nil || cooldownSec < 0 {
+		log.Panicf("[server:config][FATAL] invalid %s: %v", envCooldown, err)
+	}
+
+	ml := MagicLink{
+		TokenTTL: time.Duration(ttlMin) * time.Minute,
+		Cooldown: time.Duration(cooldownSec) * time.Second,
+		LinkURL:  settings.GetEnvSrt(envLinkURL),
+	}
+	log.Printf("[server:config] magic link: ttl=%s cooldown=%s", ml.TokenTTL, ml.Cooldown)
+	return ml
+}
+
+func loadSecurityEvents() SecurityEvents {
+	const (
+		envRetention = "SECURITY_EVENTS_RETENTION_DAYS"
+		envPurge     = "SECURITY_EVENTS_PURGE_INTERVAL_MINUTES"
+		envPageSize  = "SECURITY_EVENTS_PAGE_SIZE"
+	)
+
+	retentionDays, err := settings.GetEnvInt(envRetention)
+	if err != nil || retentionDays < 1 {
+		log.Panicf("[server:config][FATAL] invalid %s: %v", envRetention, err)
+	}
+	purgeMin, err := settings.GetEnvInt(envPurge)
+	if err != nil || purgeMin < 1 {
+		log.Panicf("[server:config][FATAL] invalid %s: %v", envPurge, err)
+	}
+	pageSize, err := settings.GetEnvInt(envPageSize)
+	if err != nil || pageSize < 1 {
+		log.Panicf("[server:config][FATAL] invalid %s: %v", envPageSize, err)
+	}
+
+	se := SecurityEvents{
+		Retention:     time.Duration(retentionDays) * 24 * time.Hour,
+		PurgeInterval: time.Duration(purgeMin) * time.Minute,
+		PageSize:      pageSize,
+	}
+	log.Printf("[server:config] security events: retention=%s purgeInterval=%s pageSize=%d",
+		se.Retention, se.PurgeInterval, se.PageSize,
+	)
+	return se
+}
+
+func loadLoginThrottle() LoginThrottle {
+	const (
+		envWindow         = "LOGIN_FAILURE_WINDOW_MINUTES"
+		envBackoffBase    = "LOGIN_BACKOFF_BASE_SECONDS"
+		envBackoffMax     = "LOGIN_BACKOFF_MAX_SECONDS"
+		envLockout        = "LOGIN_LOCKOUT_MINUTES"
+		envAccountBackoff = "LOGIN_ACCOUNT_BACKOFF_AFTER"
+		envAccountLockout = "LOGIN_ACCOUNT_LOCKOUT_AFTER"
+		envIPBackoff      = "LOGIN_IP_BACKOFF_AFTER"
+		envIPLockout      = "LOGIN_IP_LOCKOUT_AFTER"
+	)
+
+	positive := func(env string) int {
+		v, err := settings.GetEnvInt(env)
+		if err != nil || v < 1 {
+			log.Panicf("[server:config][FATAL] invalid %s: %v", env, err)
+		}
+		return v
+	}
+
+	lt := LoginThrottle{
+		FailureWindow:       time.Duration(positive(envWindow)) * time.Minute,
+		BackoffBase:         time.Duration(positive(envBackoffBase)) * time.Second,
+		BackoffMax:          time.Duration(positive(envBackoffMax)) * time.Second,
+		LockoutDuration:     time.Duration(positive(envLockout)) * time.Minute,
+		AccountBackoffAfter: positive(envAccountBackoff),
+		AccountLockoutAfter: positive(envAccountLockout),
+		IPBackoffAfter:      positive(envIPBackoff),
+		IPLockoutAfter:      positive(envIPLockout),
+	}
+
+	if lt.BackoffMax < lt.BackoffBase {
+		log.Panicf("[server:config][FATAL] %s must not be less than %s", envBackoffMax, envBackoffBase)
+	}
+	if lt.AccountLockoutAfter < lt.AccountBackoffAfter || lt.IPLockoutAfter < lt.IPBackoffAfter {
+		log.Panicf("[server:config][FATAL] lockout thresholds must not be less than backoff thresholds")
+	}
+
+	log.Printf("[server:config] login throttle: %+v", lt)
+
+	return lt
+}
+
+func loadJWTKeys() JWTKeys {
+	const (
+		envAlg         = "JWT_SIGNING_ALG"
+		envKeysDir     = "JWT_KEYS_DIR"
+		envSigningKid  = "JWT_SIGNING_KID"
+		envVerifyHS256 = "JWT_VERIFY_HS256"
+	)
+
+	keys := JWTKeys{SigningAlg: settings.GetEnvSrt(envAlg)}
+
+	switch keys.SigningAlg {
+	case AlgHS256:
+		log.Printf("[server:config] JWT signing: alg=%s", keys.SigningAlg)
+		return keys
+	case AlgRS256, AlgEdDSA:
+	default:
+		log.Panicf("[server:config][FATAL] invalid %s=%q: expected %s, %s or %s", envAlg, keys.SigningAlg, AlgHS256, AlgRS256, AlgEdDSA)
+	}
+
+	keys.KeysDir = settings.GetEnvSrt(envKeysDir)
+	keys.SigningKeyID = settings.GetEnvSrt(envSigningKid)
+
+	verify, err := strconv.ParseBool(settings.GetEnvSrt(envVerifyHS256))
+	if err != nil {
+		log.Panicf("[server:config][FATAL] invalid %s: %v", envVerifyHS256, err)
+	}
+	keys.VerifyHS256 = verify
+
+	log.Printf("[server:config] JWT signing: %+v", keys)
+
+	return keys
 }
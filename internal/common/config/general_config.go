@@ -3,6 +3,10 @@ package config
 import (
 	"github.com/1URose/marketplace/internal/common/config/ad_limits"
 	"github.com/1URose/marketplace/internal/common/config/common"
+	"github.com/1URose/marketplace/internal/common/config/mail"
+	"github.com/1URose/marketplace/internal/common/config/oidc"
+	"github.com/1URose/marketplace/internal/common/config/password_hashing"
+	"github.com/1URose/marketplace/internal/common/config/password_policy"
 	"github.com/1URose/marketplace/internal/common/config/postgresql"
 	"github.com/1URose/marketplace/internal/common/config/redis"
 	"github.com/joho/godotenv"
@@ -14,6 +18,10 @@ type GeneralConfig struct {
 	PostgresConfig *postgresql.Config
 	RedisConfig    *redis.Config
 	CommonConfig   *common.Config
+	OIDCConfig     *oidc.Config
+	MailConfig     *mail.Config
+	PasswordConfig *password_hashing.Config
+	PolicyConfig   *password_policy.Config
 }
 
 func NewGeneralConfig() *GeneralConfig {
@@ -23,6 +31,10 @@ func NewGeneralConfig() *GeneralConfig {
 		PostgresConfig: postgresql.LoadPGConfigFromEnv(),
 		RedisConfig:    redis.LoadRedisConfigFromEnv(),
 		CommonConfig:   common.LoadCommonConfigFromEnv(),
+		OIDCConfig:     oidc.LoadOIDCConfigFromEnv(),
+		MailConfig:     mail.LoadMailConfigFromEnv(),
+		PasswordConfig: password_hashing.LoadPasswordHashingConfigFromEnv(),
+		PolicyConfig:   password_policy.LoadPasswordPolicyConfigFromEnv(),
 	}
 }
 
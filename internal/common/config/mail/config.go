@@ -0,0 +1,44 @@
+package mail
+
+import (
+	"github.com/1URose/marketplace/internal/common/settings"
+	"log"
+)
+
+// Почтовые бэкенды.
+const (
+	// BackendFile складывает письма .eml-файлами в DevDir — для разработки и тестов.
+	BackendFile = "file"
+)
+
+type Config struct {
+	Backend string
+	From    string
+	DevDir  string
+}
+
+func NewConfig(backend, from, devDir string) *Config {
+	log.Printf("[mail:config] loading: backend=%s from=%s devDir=%s", backend, from, devDir)
+	return &Config{
+		Backend: backend,
+		From:    from,
+		DevDir:  devDir,
+	}
+}
+
+func LoadMailConfigFromEnv() *Config {
+	log.Println("[mail:config] reading mail config from env")
+
+	const (
+		envBackend = "MAIL_BACKEND"
+		envFrom    = "MAIL_FROM"
+		envDevDir  = "MAIL_DEV_DIR"
+	)
+
+	backend := settings.GetEnvSrt(envBackend)
+	if backend != BackendFile {
+		log.Panicf("[mail:config][FATAL] unsupported %s=%q", envBackend, backend)
+	}
+
+	return NewConfig(backend, settings.GetEnvSrt(envFrom), settings.GetEnvSrt(envDevDir))
+}
@@ -0,0 +1,86 @@
+package oidc
+
+import (
+	"fmt"
+	"github.com/1URose/marketplace/internal/common/settings"
+	"log"
+	"strings"
+	"time"
+)
+
+// Provider — внешний OpenID Connect провайдер входа (Google, Yandex, VK ID или локальная заглушка).
+type Provider struct {
+	// Name — имя в URL: /auth/oidc/{name}/login.
+	Name         string
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	// RedirectURL должен совпадать с адресом, зарегистрированным у провайдера: .../auth/oidc/{name}/callback.
+	RedirectURL string
+	Scopes      []string
+}
+
+type Config struct {
+	Providers []Provider
+	// StateTTL — сколько ждать возвращения пользователя от провайдера.
+	StateTTL time.Duration
+}
+
+func NewConfig(providers []Provider, stateTTL time.Duration) *Config {
+	log.Printf("[oidc:config] loading: providers=%d stateTTL=%s", len(providers), stateTTL)
+	return &Config{
+		Providers: providers,
+		StateTTL:  stateTTL,
+	}
+}
+
+// LoadOIDCConfigFromEnv читает список провайдеров из OIDC_PROVIDERS и настройки каждого из OIDC_<NAME>_*.
+// Пустой OIDC_PROVIDERS выключает вход через внешних провайдеров.
+func LoadOIDCConfigFromEnv() *Config {
+	log.Println("[oidc:config] reading OIDC config from env")
+
+	const (
+		envProviders = "OIDC_PROVIDERS"
+		envStateTTL  = "OIDC_STATE_TTL_MINUTES"
+	)
+
+	providers := make([]Provider, 0)
+	for _, name := range strings.Split(settings.GetEnvSrtOrDefault(envProviders, ""), ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		providers = append(providers, loadProvider(name))
+	}
+
+	if len(providers) == 0 {
+		return NewConfig(providers, 0)
+	}
+
+	stateMin, err := settings.GetEnvInt(envStateTTL)
+	if err != nil || stateMin < 1 {
+		log.Panicf("[oidc:config][FATAL] invalid %s: %v", envStateTTL, err)
+	}
+
+	return NewConfig(providers, time.Duration(stateMin)*time.Minute)
+}
+
+func loadProvider(name string) Provider {
+	env := func(suffix string) string {
+		return fmt.Sprintf("OIDC_%s_%s", strings.ToUpper(name), suffix)
+	}
+
+	p := Provider{
+		Name:         name,
+		Issuer:       strings.TrimSuffix(settings.GetEnvSrt(env("ISSUER")), "/"),
+		ClientID:     settings.GetEnvSrt(env("CLIENT_ID")),
+		ClientSecret: settings.GetEnvSrt(env("CLIENT_SECRET")),
+		RedirectURL:  settings.GetEnvSrt(env("REDIRECT_URL")),
+		Scopes:       strings.Fields(settings.GetEnvSrtOrDefault(env("SCOPES"), "openid email")),
+	}
+
+	log.Printf("[oidc:config] loaded provider %s: issuer=%s clientID=%s redirect=%s scopes=%v",
+		p.Name, p.Issuer, p.ClientID, p.RedirectURL, p.Scopes,
+	)
+	return p
+}
@@ -0,0 +1,62 @@
+package password_hashing
+
+import (
+	"github.com/1URose/marketplace/internal/common/settings"
+	"log"
+)
+
+// Config — алгоритм и параметры хеширования паролей. Уже сохранённые хеши с другими параметрами
+// продолжают проверяться и пересчитываются при следующем успешном входе.
+type Config struct {
+	// Algorithm — argon2id или bcrypt.
+	Algorithm string
+	// Argon2Memory — память argon2id в KiB, Argon2Time — число проходов, Argon2Threads — параллелизм.
+	Argon2Memory  uint32
+	Argon2Time    uint32
+	Argon2Threads uint8
+	BcryptCost    int
+}
+
+func NewConfig(algorithm string, argon2Memory, argon2Time uint32, argon2Threads uint8, bcryptCost int) *Config {
+	log.Printf("[password_hashing:config] loading: algorithm=%s argon2(m=%d,t=%d,p=%d) bcryptCost=%d",
+		algorithm, argon2Memory, argon2Time, argon2Threads, bcryptCost,
+	)
+	return &Config{
+		Algorithm:     algorithm,
+		Argon2Memory:  argon2Memory,
+		Argon2Time:    argon2Time,
+		Argon2Threads: argon2Threads,
+		BcryptCost:    bcryptCost,
+	}
+}
+
+func LoadPasswordHashingConfigFromEnv() *Config {
+	log.Println("[password_hashing:config] reading password hashing config from env")
+
+	const (
+		envAlgorithm     = "PASSWORD_HASH_ALGORITHM"
+		envArgon2Memory  = "PASSWORD_ARGON2_MEMORY_KIB"
+		envArgon2Time    = "PASSWORD_ARGON2_TIME"
+		envArgon2Threads = "PASSWORD_ARGON2_THREADS"
+		envBcryptCost    = "PASSWORD_BCRYPT_COST"
+	)
+
+	memory, err := settings.GetEnvInt(envArgon2Memory)
+	if err != nil || memory < 8*1024 {
+		log.Panicf("[password_hashing:config][FATAL] invalid %s (at least 8192 KiB): %v", envArgon2Memory, err)
+	}
+	passes, err := settings.GetEnvInt(envArgon2Time)
+	if err != nil || passes < 1 {
+		log.Panicf("[password_hashing:config][FATAL] invalid %s: %v", envArgon2Time, err)
+	}
+	threads, err := settings.GetEnvInt(envArgon2Threads)
+	if err != nil || threads < 1 || threads > 255 {
+		log.Panicf("[password_hashing:config][FATAL] invalid %s: %v", envArgon2Threads, err)
+	}
+	cost, err := settings.GetEnvInt(envBcryptCost)
+	if err != nil || cost < 10 || cost > 31 {
+		log.Panicf("[password_hashing:config][FATAL] invalid %s (10..31): %v", envBcryptCost, err)
+	}
+
+	return NewConfig(settings.GetEnvSrt(envAlgorithm), uint32(memory), uint32(passes), uint8(threads), cost)
+}
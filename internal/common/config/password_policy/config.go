@@ -0,0 +1,68 @@
+package password_policy
+
+import (
+	"github.com/1URose/marketplace/internal/common/settings"
+	"log"
+	"strconv"
+	"strings"
+)
+
+// Config — требования к новым паролям при регистрации, сбросе и смене пароля.
+type Config struct {
+	MinLength int
+	MaxLength int
+	// RequiredClasses — какие классы символов обязательны: lower, upper, letter, digit, symbol.
+	RequiredClasses []string
+	// RejectEmail — запрещать пароли, содержащие email или его часть до @.
+	RejectEmail bool
+	// BreachedDataset — файл с SHA-1 утёкших паролей; пустая строка отключает проверку.
+	BreachedDataset string
+}
+
+func NewConfig(minLength, maxLength int, requiredClasses []string, rejectEmail bool, breachedDataset string) *Config {
+	log.Printf("[password_policy:config] loading: length=%d..%d classes=%v rejectEmail=%t breachedDataset=%q",
+		minLength, maxLength, requiredClasses, rejectEmail, breachedDataset,
+	)
+	return &Config{
+		MinLength:       minLength,
+		MaxLength:       maxLength,
+		RequiredClasses: requiredClasses,
+		RejectEmail:     rejectEmail,
+		BreachedDataset: breachedDataset,
+	}
+}
+
+func LoadPasswordPolicyConfigFromEnv() *Config {
+	log.Println("[password_policy:config] reading password policy config from env")
+
+	const (
+		envMinLength   = "PASSWORD_MIN_LENGTH"
+		envMaxLength   = "PASSWORD_MAX_LENGTH"
+		envClasses     = "PASSWORD_REQUIRED_CHAR_CLASSES"
+		envRejectEmail = "PASSWORD_REJECT_EMAIL"
+		envBreached    = "PASSWORD_BREACHED_DATASET"
+	)
+
+	minLength, err := settings.GetEnvInt(envMinLength)
+	if err != nil || minLength < 1 {
+		log.Panicf("[password_policy:config][FATAL] invalid %s: %v", envMinLength, err)
+	}
+	maxLength, err := settings.GetEnvInt(envMaxLength)
+	if err != nil || maxLength < minLength {
+		log.Panicf("[password_policy:config][FATAL] invalid %s (must be >= %s): %v", envMaxLength, envMinLength, err)
+	}
+
+	var classes []string
+	for _, c := range strings.Split(settings.GetEnvSrtOrDefault(envClasses, ""), ",") {
+		if c = strings.TrimSpace(strings.ToLower(c)); c != "" {
+			classes = append(classes, c)
+		}
+	}
+
+	rejectEmail, err := strconv.ParseBool(settings.GetEnvSrt(envRejectEmail))
+	if err != nil {
+		log.Panicf("[password_policy:config][FATAL] invalid %s: %v", envRejectEmail, err)
+	}
+
+	return NewConfig(minLength, maxLength, classes, rejectEmail, settings.GetEnvSrtOrDefault(envBreached, ""))
+}
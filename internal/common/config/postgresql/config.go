@@ -76,7 +76,7 @@ func LoadPGConfigFromEnv() *Config {
 	)
 
 	log.Printf(
-		"[postgresql:config] loaded: host=%s port=%s user=%s db=%s max_conns=%d min_conns=%d max_conn_lifetime=%s",
+		"[postgresql:config] loaded: host=%s port=%s user=%s db=%s max_conns=%d min_conns=%d max_conn_lifetime=%ds",
 		host, port, user, db, maxConns, minConns, maxLifetimeSec,
 	)
 
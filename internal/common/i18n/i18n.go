@@ -0,0 +1,88 @@
+// Package i18n хранит каталоги сообщений API и выбирает язык ответа по заголовку Accept-Language.
+// Сообщения адресуются ключами; значения могут содержать подстановки вида {min}.
+package i18n
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type Lang string
+
+const (
+	EN Lang = "en"
+	RU Lang = "ru"
+
+	// Default используется, когда клиент не указал поддерживаемый язык, и для ключей, которых нет в каталоге языка.
+	Default = EN
+)
+
+var catalogs = map[Lang]map[string]string{
+	EN: messagesEN,
+	RU: messagesRU,
+}
+
+// Message возвращает сообщение по ключу на языке lang, а если его там нет — на языке по умолчанию.
+// Неизвестный ключ возвращается как есть, чтобы пропуск в каталоге был заметен, но не ломал ответ.
+func Message(lang Lang, key string, params map[string]interface{}) string {
+	template, ok := catalogs[lang][key]
+	if !ok {
+		template, ok = catalogs[Default][key]
+	}
+	if !ok {
+		return key
+	}
+	if len(params) == 0 {
+		return template
+	}
+
+	pairs := make([]string, 0, len(params)*2)
+	for name, value := range params {
+		text := fmt.Sprint(value)
+		if list, ok := value.([]string); ok {
+			text = strings.Join(list, ", ")
+		}
+		pairs = append(pairs, "{"+name+"}", text)
+	}
+	return strings.NewReplacer(pairs...).Replace(template)
+}
+
+// FromAcceptLanguage выбирает поддерживаемый язык с наибольшим весом q; региональные варианты
+// (ru-RU, en-GB) сводятся к основному языку.
+func FromAcceptLanguage(header string) Lang {
+	type candidate struct {
+		lang Lang
+		q    float64
+	}
+
+	var candidates []candidate
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		tag := strings.ToLower(strings.TrimSpace(fields[0]))
+		if i := strings.IndexByte(tag, '-'); i >= 0 {
+			tag = tag[:i]
+		}
+
+		q := 1.0
+		for _, f := range fields[1:] {
+			f = strings.TrimSpace(f)
+			if strings.HasPrefix(f, "q=") {
+				if v, err := strconv.ParseFloat(f[2:], 64); err == nil {
+					q = v
+				}
+			}
+		}
+
+		if _, ok := catalogs[Lang(tag)]; ok && q > 0 {
+			candidates = append(candidates, candidate{lang: Lang(tag), q: q})
+		}
+	}
+
+	if len(candidates) == 0 {
+		return Default
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].q > candidates[j].q })
+	return candidates[0].lang
+}
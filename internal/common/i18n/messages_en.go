@@ -0,0 +1,143 @@
+package i18n
+
+var messagesEN = map[string]string{
+	// общие ошибки запроса
+	"request.invalid_body":        "Invalid request body",
+	"request.empty_body":          "Request body is empty",
+	"request.malformed_json":      "Request body is not valid JSON",
+	"request.invalid_json_type":   "Request body has an unexpected JSON type",
+	"request.invalid_query":       "Invalid request query",
+	"request.validation_failed":   "Validation failed",
+	"request.page_out_of_range":   "Page number out of range",
+	"request.internal":            "Internal server error",
+	"validation.required":         "Value is required",
+	"validation.required_without": "Value is required when {other} is not provided",
+	"validation.min":              "Value must be at least {min}",
+	"validation.min_string":       "Value must be at least {min} characters long",
+	"validation.min_items":        "Value must contain at least {min} items",
+	"validation.max":              "Value must be at most {max}",
+	"validation.max_string":       "Value must be at most {max} characters long",
+	"validation.max_items":        "Value must contain at most {max} items",
+	"validation.len":              "Value must be exactly {len}",
+	"validation.len_string":       "Value must be exactly {len} characters long",
+	"validation.len_items":        "Value must contain exactly {len} items",
+	"validation.oneof":            "Value must be one of: {allowed}",
+	"validation.email":            "Value must be a valid email address",
+	"validation.url":              "Value must be a valid URL",
+	"validation.ip":               "Value must be a valid IP address",
+	"validation.numeric":          "Value must contain only digits",
+	"validation.type":             "Value must be of type {type}",
+	"validation.invalid":          "Value is invalid",
+
+	// объявления
+	"ad.invalid_id":         "Invalid ad id",
+	"ad.not_found":          "Ad not found",
+	"ad.not_owner":          "Ad belongs to another user",
+	"ad.already_published":  "Ad is already published",
+	"ad.no_changes":         "Nothing to change",
+	"ad.revision_not_found": "Revision not found",
+	"ad.nothing_to_update":  "At least one field must be provided",
+
+	// нарушения validator.AdAllowedValues: ad.<поле>.<код>
+	"ad.page.min":                    "Page must be at least {min}",
+	"ad.sort_by.unsupported":         "Unsupported sort field \"{value}\"",
+	"ad.sort_order.unsupported":      "Unsupported sort order \"{value}\"",
+	"ad.min_price.min":               "Minimum price must be at least {min}",
+	"ad.max_price.min":               "Maximum price must be at least {min}",
+	"ad.min_price.greater_than_max":  "Minimum price ({min_price}) cannot be greater than maximum price ({max_price})",
+	"ad.category.unsupported":        "Unsupported category \"{value}\"",
+	"ad.facets.unsupported":          "Unsupported facet \"{value}\"",
+	"ad.title.too_short":             "Title is too short: {actual} < {min}",
+	"ad.title.too_long":              "Title is too long: {actual} > {max}",
+	"ad.description.too_short":       "Description is too short: {actual} < {min}",
+	"ad.description.too_long":        "Description is too long: {actual} > {max}",
+	"ad.price.min":                   "Price must be at least {min}",
+	"ad.price.max":                   "Price must be at most {max}",
+	"ad.publish_at.draft_scheduled":  "Draft cannot have publish_at",
+	"ad.publish_at.not_in_future":    "publish_at must be in the future, got {value}",
+	"ad.image_url.invalid_scheme":    "Image URL must start with http:// or https://",
+	"ad.image_url.unreachable":       "Image URL is unreachable",
+	"ad.image_url.unexpected_status": "Image URL responded with status {status}",
+	"ad.image_url.unsupported_type":  "Unsupported image content type \"{value}\"",
+	"ad.image_url.missing_length":    "Image content length is missing or zero",
+	"ad.image_url.too_large":         "Image is too large: {actual} > {max}",
+
+	// жалобы и продвижение
+	"report.already_reported":   "Ad already reported",
+	"report.own_ad":             "Cannot report own ad",
+	"report.no_open_reports":    "No open reports for ad",
+	"report.unsupported_action": "Unsupported action",
+	"promotion.invalid_period":  "Invalid promotion period",
+	"promotion.period_rule":     "promotion must end after it starts and after the current moment",
+
+	// пользователи и аутентификация
+	"user.email_taken":                "Email already taken",
+	"auth.unauthorized":               "Unauthorized",
+	"auth.forbidden":                  "Forbidden",
+	"auth.insufficient_scope":         "Insufficient scope",
+	"auth.email_not_verified":         "Email not verified",
+	"auth.invalid_credentials":        "Invalid credentials",
+	"auth.invalid_current_password":   "Invalid current password",
+	"auth.user_not_found":             "User not found",
+	"auth.user_suspended":             "User is suspended",
+	"auth.invalid_role":               "Invalid role",
+	"auth.own_role_change":            "Cannot change own role",
+	"auth.invalid_user_id":            "Invalid user id",
+	"auth.login_throttled":            "Too many login attempts",
+	"auth.invalid_token":              "Invalid token",
+	"auth.token_revoked":              "Token revoked",
+	"auth.nothing_to_revoke":          "Nothing to revoke",
+	"auth.missing_refresh_token":      "Missing refresh token",
+	"auth.invalid_refresh_token":      "Invalid refresh token",
+	"auth.refresh_token_reused":       "Refresh token reused",
+	"auth.session_not_found":          "Session not found",
+	"auth.session_mismatch":           "Session not found or token mismatch",
+	"auth.password_policy":            "Password does not meet requirements",
+	"auth.same_email":                 "New email equals current email",
+	"auth.invalid_verification_token": "Invalid or expired verification token",
+	"auth.email_already_verified":     "Email already verified",
+	"auth.verification_throttled":     "Verification email recently sent",
+	"auth.invalid_reset_token":        "Invalid or expired reset token",
+	"auth.invalid_magic_link":         "Invalid or expired magic link",
+	"auth.magic_link_device_mismatch": "Magic link must be opened on the device that requested it",
+
+	// двухфакторная аутентификация
+	"mfa.invalid_token":   "Invalid MFA token",
+	"mfa.invalid_code":    "Invalid two-factor code",
+	"mfa.already_enabled": "Two-factor authentication already enabled",
+	"mfa.not_enrolled":    "TOTP enrollment not started",
+	"mfa.not_enabled":     "Two-factor authentication not enabled",
+
+	// вход через внешних провайдеров
+	"oidc.unknown_provider":           "Unknown identity provider",
+	"oidc.invalid_response":           "Invalid login response",
+	"oidc.invalid_state":              "invalid or expired state",
+	"oidc.nonce_mismatch":             "nonce mismatch",
+	"oidc.invalid_id_token":           "invalid id token",
+	"oidc.email_not_verified":         "Email is not verified by provider",
+	"oidc.local_account_not_verified": "An account with this email exists but its email is not verified; sign in with password and verify it first",
+	"oidc.identity_exists":            "Identity already linked",
+	"oidc.provider_rejected":          "Login was rejected by provider",
+	"oidc.missing_code_or_state":      "Missing code or state",
+
+	// API-ключи и журнал безопасности
+	"api_key.invalid":             "Invalid API key",
+	"api_key.invalid_id":          "Invalid API key id",
+	"api_key.not_found":           "API key not found",
+	"api_key.unknown_scope":       "Unknown API key scope",
+	"api_key.no_scopes":           "API key must have at least one scope",
+	"api_key.expiry_in_past":      "API key expiry must be in the future",
+	"security_event.unknown_type": "Unknown security event type",
+
+	// политика паролей: password.<код>
+	"password.too_short":         "Password must be at least {min} characters long",
+	"password.too_long":          "Password must be at most {max} characters long",
+	"password.too_long_bytes":    "Password must be at most {max} bytes long",
+	"password.missing_lowercase": "Password must contain at least one lowercase letter",
+	"password.missing_uppercase": "Password must contain at least one uppercase letter",
+	"password.missing_letter":    "Password must contain at least one letter",
+	"password.missing_digit":     "Password must contain at least one digit",
+	"password.missing_symbol":    "Password must contain at least one symbol",
+	"password.contains_email":    "Password must not contain your email",
+	"password.breached":          "Password has appeared in a data breach, choose another one",
+}
@@ -0,0 +1,143 @@
+package i18n
+
+var messagesRU = map[string]string{
+	// общие ошибки запроса
+	"request.invalid_body":        "Некорректное тело запроса",
+	"request.empty_body":          "Тело запроса пустое",
+	"request.malformed_json":      "Тело запроса не является корректным JSON",
+	"request.invalid_json_type":   "Тело запроса имеет неожиданный тип JSON",
+	"request.invalid_query":       "Некорректные параметры запроса",
+	"request.validation_failed":   "Ошибка валидации",
+	"request.page_out_of_range":   "Номер страницы вне диапазона",
+	"request.internal":            "Внутренняя ошибка сервера",
+	"validation.required":         "Обязательное поле",
+	"validation.required_without": "Поле обязательно, если не передано {other}",
+	"validation.min":              "Значение должно быть не меньше {min}",
+	"validation.min_string":       "Длина должна быть не меньше {min} символов",
+	"validation.min_items":        "Должно быть не меньше {min} элементов",
+	"validation.max":              "Значение должно быть не больше {max}",
+	"validation.max_string":       "Длина должна быть не больше {max} символов",
+	"validation.max_items":        "Должно быть не больше {max} элементов",
+	"validation.len":              "Значение должно быть равно {len}",
+	"validation.len_string":       "Длина должна быть ровно {len} символов",
+	"validation.len_items":        "Должно быть ровно {len} элементов",
+	"validation.oneof":            "Допустимые значения: {allowed}",
+	"validation.email":            "Некорректный email",
+	"validation.url":              "Некорректный URL",
+	"validation.ip":               "Некорректный IP-адрес",
+	"validation.numeric":          "Допустимы только цифры",
+	"validation.type":             "Значение должно иметь тип {type}",
+	"validation.invalid":          "Некорректное значение",
+
+	// объявления
+	"ad.invalid_id":         "Некорректный ID объявления",
+	"ad.not_found":          "Объявление не найдено",
+	"ad.not_owner":          "Объявление принадлежит другому пользователю",
+	"ad.already_published":  "Объявление уже опубликовано",
+	"ad.no_changes":         "Нечего изменять",
+	"ad.revision_not_found": "Версия объявления не найдена",
+	"ad.nothing_to_update":  "Нужно передать хотя бы одно поле",
+
+	// нарушения validator.AdAllowedValues: ad.<поле>.<код>
+	"ad.page.min":                    "Номер страницы должен быть не меньше {min}",
+	"ad.sort_by.unsupported":         "Неподдерживаемое поле сортировки «{value}»",
+	"ad.sort_order.unsupported":      "Неподдерживаемый порядок сортировки «{value}»",
+	"ad.min_price.min":               "Минимальная цена должна быть не меньше {min}",
+	"ad.max_price.min":               "Максимальная цена должна быть не меньше {min}",
+	"ad.min_price.greater_than_max":  "Минимальная цена ({min_price}) не может быть больше максимальной ({max_price})",
+	"ad.category.unsupported":        "Неподдерживаемая категория «{value}»",
+	"ad.facets.unsupported":          "Неподдерживаемый фасет «{value}»",
+	"ad.title.too_short":             "Заголовок слишком короткий: {actual} < {min}",
+	"ad.title.too_long":              "Заголовок слишком длинный: {actual} > {max}",
+	"ad.description.too_short":       "Описание слишком короткое: {actual} < {min}",
+	"ad.description.too_long":        "Описание слишком длинное: {actual} > {max}",
+	"ad.price.min":                   "Цена должна быть не меньше {min}",
+	"ad.price.max":                   "Цена должна быть не больше {max}",
+	"ad.publish_at.draft_scheduled":  "У черновика не может быть publish_at",
+	"ad.publish_at.not_in_future":    "publish_at должен быть в будущем, получено {value}",
+	"ad.image_url.invalid_scheme":    "URL изображения должен начинаться с http:// или https://",
+	"ad.image_url.unreachable":       "URL изображения недоступен",
+	"ad.image_url.unexpected_status": "URL изображения ответил статусом {status}",
+	"ad.image_url.unsupported_type":  "Неподдерживаемый тип изображения «{value}»",
+	"ad.image_url.missing_length":    "Размер изображения не указан или равен нулю",
+	"ad.image_url.too_large":         "Изображение слишком большое: {actual} > {max}",
+
+	// жалобы и продвижение
+	"report.already_reported":   "Жалоба на объявление уже отправлена",
+	"report.own_ad":             "Нельзя пожаловаться на своё объявление",
+	"report.no_open_reports":    "Открытых жалоб на объявление нет",
+	"report.unsupported_action": "Неподдерживаемое действие",
+	"promotion.invalid_period":  "Некорректный период продвижения",
+	"promotion.period_rule":     "продвижение должно заканчиваться позже начала и позже текущего момента",
+
+	// пользователи и аутентификация
+	"user.email_taken":                "Email уже занят",
+	"auth.unauthorized":               "Требуется авторизация",
+	"auth.forbidden":                  "Доступ запрещён",
+	"auth.insufficient_scope":         "Недостаточно прав API-ключа",
+	"auth.email_not_verified":         "Email не подтверждён",
+	"auth.invalid_credentials":        "Неверный email или пароль",
+	"auth.invalid_current_password":   "Неверный текущий пароль",
+	"auth.user_not_found":             "Пользователь не найден",
+	"auth.user_suspended":             "Пользователь заблокирован",
+	"auth.invalid_role":               "Недопустимая роль",
+	"auth.own_role_change":            "Нельзя изменить собственную роль",
+	"auth.invalid_user_id":            "Некорректный ID пользователя",
+	"auth.login_throttled":            "Слишком много попыток входа",
+	"auth.invalid_token":              "Некорректный токен",
+	"auth.token_revoked":              "Токен отозван",
+	"auth.nothing_to_revoke":          "Нечего отзывать",
+	"auth.missing_refresh_token":      "Не передан refresh-токен",
+	"auth.invalid_refresh_token":      "Некорректный refresh-токен",
+	"auth.refresh_token_reused":       "Refresh-токен уже был использован",
+	"auth.session_not_found":          "Сессия не найдена",
+	"auth.session_mismatch":           "Сессия не найдена или токен не совпадает",
+	"auth.password_policy":            "Пароль не соответствует требованиям",
+	"auth.same_email":                 "Новый email совпадает с текущим",
+	"auth.invalid_verification_token": "Токен подтверждения неверен или истёк",
+	"auth.email_already_verified":     "Email уже подтверждён",
+	"auth.verification_throttled":     "Письмо с подтверждением уже недавно отправлено",
+	"auth.invalid_reset_token":        "Токен сброса пароля неверен или истёк",
+	"auth.invalid_magic_link":         "Ссылка для входа неверна или истекла",
+	"auth.magic_link_device_mismatch": "Ссылку для входа нужно открыть на устройстве, с которого её запросили",
+
+	// двухфакторная аутентификация
+	"mfa.invalid_token":   "Некорректный MFA-токен",
+	"mfa.invalid_code":    "Неверный код двухфакторной аутентификации",
+	"mfa.already_enabled": "Двухфакторная аутентификация уже включена",
+	"mfa.not_enrolled":    "Подключение TOTP не начато",
+	"mfa.not_enabled":     "Двухфакторная аутентификация не включена",
+
+	// вход через внешних провайдеров
+	"oidc.unknown_provider":           "Неизвестный провайдер входа",
+	"oidc.invalid_response":           "Некорректный ответ провайдера входа",
+	"oidc.invalid_state":              "параметр state неверен или истёк",
+	"oidc.nonce_mismatch":             "nonce не совпадает",
+	"oidc.invalid_id_token":           "некорректный id token",
+	"oidc.email_not_verified":         "Провайдер не подтвердил email",
+	"oidc.local_account_not_verified": "Пользователь с этим email уже есть, но адрес не подтверждён: войдите по паролю и подтвердите его",
+	"oidc.identity_exists":            "Учётная запись провайдера уже привязана",
+	"oidc.provider_rejected":          "Провайдер отклонил вход",
+	"oidc.missing_code_or_state":      "Не переданы code или state",
+
+	// API-ключи и журнал безопасности
+	"api_key.invalid":             "Неверный API-ключ",
+	"api_key.invalid_id":          "Некорректный ID API-ключа",
+	"api_key.not_found":           "API-ключ не найден",
+	"api_key.unknown_scope":       "Неизвестная область доступа API-ключа",
+	"api_key.no_scopes":           "У API-ключа должна быть хотя бы одна область доступа",
+	"api_key.expiry_in_past":      "Срок действия API-ключа должен быть в будущем",
+	"security_event.unknown_type": "Неизвестный тип события безопасности",
+
+	// политика паролей: password.<код>
+	"password.too_short":         "Пароль должен содержать не меньше {min} символов",
+	"password.too_long":          "Пароль должен содержать не больше {max} символов",
+	"password.too_long_bytes":    "Пароль не должен превышать {max} байт",
+	"password.missing_lowercase": "Пароль должен содержать хотя бы одну строчную букву",
+	"password.missing_uppercase": "Пароль должен содержать хотя бы одну заглавную букву",
+	"password.missing_letter":    "Пароль должен содержать хотя бы одну букву",
+	"password.missing_digit":     "Пароль должен содержать хотя бы одну цифру",
+	"password.missing_symbol":    "Пароль должен содержать хотя бы один спецсимвол",
+	"password.contains_email":    "Пароль не должен содержать ваш email",
+	"password.breached":          "Этот пароль встречался в утечках данных, выберите другой",
+}
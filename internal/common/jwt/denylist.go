@@ -0,0 +1,107 @@
+package jwt
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrTokenRevoked — токен отозван и внесён в denylist.
+	ErrTokenRevoked = errors.New("token revoked")
+	// ErrInvalidToken — токен не удалось разобрать или он не поддерживает отзыв.
+	ErrInvalidToken = errors.New("invalid token")
+)
+
+// Denylist хранит jti отозванных токенов; запись живёт до истечения срока действия самого токена.
+type Denylist interface {
+	Add(ctx context.Context, jti string, expiresAt time.Time) error
+	Contains(ctx context.Context, jti string) (bool, error)
+}
+
+type cacheEntry struct {
+	denied    bool
+	expiresAt time.Time
+}
+
+// CachedDenylist держит ответы denylist в памяти узла, чтобы не ходить в Redis на каждый запрос.
+// Отзыв через этот же узел виден сразу; отзыв через другой узел — не позже чем через ttl.
+// Исключение — закрытые сессии: отсутствие sid в denylist не кэшируется, поэтому выход со всех устройств,
+// сброс пароля и блокировка пользователя действуют на всех узлах сразу.
+type CachedDenylist struct {
+	next       Denylist
+	ttl        time.Duration
+	maxEntries int
+
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+}
+
+func NewCachedDenylist(next Denylist, ttl time.Duration, maxEntries int) *CachedDenylist {
+	log.Printf("[jwt:denylist] NewCachedDenylist initialized: ttl=%s maxEntries=%d", ttl, maxEntries)
+
+	return &CachedDenylist{
+		next:       next,
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		entries:    make(map[string]cacheEntry, maxEntries),
+	}
+}
+
+func (d *CachedDenylist) Add(ctx context.Context, jti string, expiresAt time.Time) error {
+	if err := d.next.Add(ctx, jti, expiresAt); err != nil {
+		return err
+	}
+
+	// отзыв необратим, поэтому его можно помнить до истечения токена
+	d.store(jti, cacheEntry{denied: true, expiresAt: expiresAt})
+	return nil
+}
+
+func (d *CachedDenylist) Contains(ctx context.Context, jti string) (bool, error) {
+	now := time.Now()
+
+	d.mu.Lock()
+	entry, ok := d.entries[jti]
+	d.mu.Unlock()
+	if ok && now.Before(entry.expiresAt) {
+		return entry.denied, nil
+	}
+
+	denied, err := d.next.Contains(ctx, jti)
+	if err != nil {
+		return false, err
+	}
+	if !denied && isSessionKey(jti) {
+		return false, nil
+	}
+
+	d.store(jti, cacheEntry{denied: denied, expiresAt: now.Add(d.ttl)})
+	return denied, nil
+}
+
+func (d *CachedDenylist) store(jti string, entry cacheEntry) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if _, ok := d.entries[jti]; !ok && len(d.entries) >= d.maxEntries {
+		d.evict(time.Now())
+	}
+	d.entries[jti] = entry
+}
+
+// evict удаляет устаревшие записи, а если кэш всё ещё полон — сбрасывает его целиком:
+// следующие запросы просто сходят в Redis.
+func (d *CachedDenylist) evict(now time.Time) {
+	for jti, entry := range d.entries {
+		if !now.Before(entry.expiresAt) {
+			delete(d.entries, jti)
+		}
+	}
+	if len(d.entries) >= d.maxEntries {
+		log.Printf("[jwt:denylist] cache is full, resetting: entries=%d", len(d.entries))
+		d.entries = make(map[string]cacheEntry, d.maxEntries)
+	}
+}
@@ -1,9 +1,27 @@
 package entity
 
-import "github.com/golang-jwt/jwt/v4"
+import (
+	"github.com/golang-jwt/jwt/v4"
+	"time"
+)
 
 type Claims struct {
 	Email     string `json:"email"`
 	TokenType string `json:"token_type"`
+	// SessionID связывает токен с сессией устройства, в которой он выдан.
+	SessionID string `json:"sid,omitempty"`
+	// Role — роль пользователя на момент выдачи access-токена; новая роль попадает в токен при следующем refresh.
+	Role string `json:"role,omitempty"`
+	// DeviceHash привязывает ссылку входа к устройству, запросившему её: SHA-256 от секрета, выданного этому устройству.
+	DeviceHash string `json:"dvh,omitempty"`
 	jwt.RegisteredClaims
 }
+
+// IssuedNotAfter сообщает, выдан ли токен не позже момента t.
+// iat хранится с точностью до секунды, поэтому токены, выданные в ту же секунду, тоже считаются выданными раньше.
+func (c *Claims) IssuedNotAfter(t time.Time) bool {
+	if c.IssuedAt == nil {
+		return true
+	}
+	return c.IssuedAt.Time.Unix() <= t.Unix()
+}
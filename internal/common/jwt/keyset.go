@@ -0,0 +1,193 @@
+package jwt
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/1URose/marketplace/internal/common/config/common"
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// hmacKeyID — kid токенов, подписанных SECRET_KEY.
+const hmacKeyID = "hs256"
+
+// minRSABits — ключи RSA короче не принимаются.
+const minRSABits = 2048
+
+// Key — ключ из набора. Для ключей, которыми только проверяют подпись, signKey равен nil.
+type Key struct {
+	ID        string
+	Method    jwt.SigningMethod
+	signKey   interface{}
+	verifyKey interface{}
+}
+
+// Keyset — один ключ подписи и все ключи, которыми ещё принимаются токены.
+type Keyset struct {
+	signing *Key
+	keys    map[string]*Key
+}
+
+// LoadKeyset собирает набор ключей по конфигурации: SECRET_KEY для HS256 или PEM-ключи из каталога для RS256/EdDSA.
+func LoadKeyset(cfg *common.Config) (*Keyset, error) {
+	hmacKey := &Key{
+		ID:        hmacKeyID,
+		Method:    jwt.SigningMethodHS256,
+		signKey:   []byte(cfg.JWTSecret),
+		verifyKey: []byte(cfg.JWTSecret),
+	}
+
+	if cfg.JWTKeys.SigningAlg == common.AlgHS256 {
+		log.Println("[jwt:keyset] using HS256 with SECRET_KEY")
+		return &Keyset{
+			signing: hmacKey,
+			keys:    map[string]*Key{hmacKey.ID: hmacKey},
+		}, nil
+	}
+
+	keys, err := loadKeyDir(cfg.JWTKeys.KeysDir)
+	if err != nil {
+		return nil, err
+	}
+
+	signing, ok := keys[cfg.JWTKeys.SigningKeyID]
+	if !ok || signing.signKey == nil {
+		return nil, fmt.Errorf("signing key %q: private key not found in %s", cfg.JWTKeys.SigningKeyID, cfg.JWTKeys.KeysDir)
+	}
+	if signing.Method.Alg() != cfg.JWTKeys.SigningAlg {
+		return nil, fmt.Errorf("signing key %q is %s, expected %s", signing.ID, signing.Method.Alg(), cfg.JWTKeys.SigningAlg)
+	}
+
+	// SECRET_KEY остаётся ключом проверки, пока после перехода не истекут выданные им токены
+	if cfg.JWTKeys.VerifyHS256 {
+		keys[hmacKey.ID] = &Key{ID: hmacKey.ID, Method: hmacKey.Method, verifyKey: hmacKey.verifyKey}
+	}
+
+	ids := make([]string, 0, len(keys))
+	for id := range keys {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	log.Printf("[jwt:keyset] loaded keys: signing=%s verification=%v", signing.ID, ids)
+
+	return &Keyset{signing: signing, keys: keys}, nil
+}
+
+// loadKeyDir читает ключи <kid>.pem: закрытые ключи (PKCS#8 или PKCS#1) и открытые (PKIX) выведенных из подписи ключей.
+func loadKeyDir(dir string) (map[string]*Key, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return nil, fmt.Errorf("list keys in %s: %w", dir, err)
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no *.pem keys in %s", dir)
+	}
+
+	keys := make(map[string]*Key, len(paths))
+	for _, path := range paths {
+		id := strings.TrimSuffix(filepath.Base(path), ".pem")
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read key %s: %w", path, err)
+		}
+
+		key, err := parseKey(id, data)
+		if err != nil {
+			return nil, fmt.Errorf("parse key %s: %w", path, err)
+		}
+		keys[id] = key
+	}
+
+	return keys, nil
+}
+
+func parseKey(id string, data []byte) (*Key, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM block")
+	}
+
+	var parsed interface{}
+	var err error
+	switch block.Type {
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported PEM block %q", block.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	key := &Key{ID: id}
+	switch k := parsed.(type) {
+	case *rsa.PrivateKey:
+		key.Method, key.signKey, key.verifyKey = jwt.SigningMethodRS256, k, &k.PublicKey
+	case *rsa.PublicKey:
+		key.Method, key.verifyKey = jwt.SigningMethodRS256, k
+	case ed25519.PrivateKey:
+		key.Method, key.signKey, key.verifyKey = jwt.SigningMethodEdDSA, k, k.Public()
+	case ed25519.PublicKey:
+		key.Method, key.verifyKey = jwt.SigningMethodEdDSA, k
+	default:
+		return nil, fmt.Errorf("unsupported key type %T", parsed)
+	}
+
+	if pub, ok := key.verifyKey.(*rsa.PublicKey); ok && pub.N.BitLen() < minRSABits {
+		return nil, fmt.Errorf("RSA key is %d bits, at least %d required", pub.N.BitLen(), minRSABits)
+	}
+
+	return key, nil
+}
+
+// sign подписывает claims ключом подписи и проставляет его kid в заголовок.
+func (ks *Keyset) sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(ks.signing.Method, claims)
+	token.Header["kid"] = ks.signing.ID
+	return token.SignedString(ks.signing.signKey)
+}
+
+// verificationKey подбирает ключ по kid из заголовка. Алгоритм токена должен совпадать с алгоритмом ключа,
+// иначе, например, открытый RSA-ключ можно было бы подсунуть как секрет HS256.
+// Токены без kid выданы до появления набора ключей и подписаны SECRET_KEY.
+func (ks *Keyset) verificationKey(t *jwt.Token) (interface{}, error) {
+	kid, _ := t.Header["kid"].(string)
+	if kid == "" {
+		kid = hmacKeyID
+	}
+
+	key, ok := ks.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown key id %q", kid)
+	}
+	if t.Method.Alg() != key.Method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method %v for key %q", t.Header["alg"], kid)
+	}
+
+	return key.verifyKey, nil
+}
+
+// publicKeys возвращает открытые ключи набора в порядке kid; симметричный SECRET_KEY не публикуется.
+func (ks *Keyset) publicKeys() []*Key {
+	keys := make([]*Key, 0, len(ks.keys))
+	for _, k := range ks.keys {
+		if k.Method != jwt.SigningMethodHS256 {
+			keys = append(keys, k)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].ID < keys[j].ID })
+	return keys
+}
@@ -1,49 +1,86 @@
 package jwt
 
 import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
 	"github.com/1URose/marketplace/internal/common/config/common"
 	"github.com/1URose/marketplace/internal/common/jwt/entity"
 	"log"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v4"
 )
 
+// Типы токенов из писем.
+const (
+	TokenTypeEmailVerification = "email_verification"
+	TokenTypeEmailChange       = "email_change"
+	TokenTypeMagicLink         = "magic_link"
+)
+
 type Manager struct {
-	secretKey  []byte
+	keyset     *Keyset
 	accessTTL  time.Duration
 	refreshTTL time.Duration
+	mfaTTL     time.Duration
+	verifyTTL  time.Duration
+	magicTTL   time.Duration
+	denylist   Denylist
 }
 
-func NewManager(cfg *common.Config) *Manager {
-	log.Printf("[jwt] NewManager called: accessTTL=%s refreshTTL=%s", cfg.AccessTTL, cfg.RefreshTTL)
+func NewManager(cfg *common.Config, keyset *Keyset, denylist Denylist) *Manager {
+	log.Printf("[jwt] NewManager called: accessTTL=%s refreshTTL=%s signingKey=%s",
+		cfg.AccessTTL, cfg.RefreshTTL, keyset.signing.ID,
+	)
 
 	return &Manager{
-		secretKey:  []byte(cfg.JWTSecret),
+		keyset:     keyset,
 		accessTTL:  cfg.AccessTTL,
 		refreshTTL: cfg.RefreshTTL,
+		mfaTTL:     cfg.MFAChallengeTTL,
+		verifyTTL:  cfg.EmailVerification.TokenTTL,
+		magicTTL:   cfg.MagicLink.TokenTTL,
+		denylist:   denylist,
 	}
 }
 
-func (m *Manager) GenerateAccessToken(email string, UserId int) (string, error) {
+// newTokenID возвращает случайный jti, по которому токен можно отозвать.
+func newTokenID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func (m *Manager) GenerateAccessToken(email string, UserId int, role, sessionID string) (string, error) {
 
-	log.Printf("[jwt] GenerateAccessToken called for email=%q", email)
+	log.Printf("[jwt] GenerateAccessToken called for email=%q role=%s sid=%s", email, role, sessionID)
+
+	jti, err := newTokenID()
+	if err != nil {
+		log.Printf("[jwt][ERROR] GenerateAccessToken jti generation failed: %v", err)
+		return "", err
+	}
 
 	claims := entity.Claims{
 		Email:     email,
 		TokenType: "access",
+		SessionID: sessionID,
+		Role:      role,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(m.accessTTL)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			Subject:   strconv.Itoa(UserId),
+			ID:        jti,
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-
-	signed, err := token.SignedString(m.secretKey)
+	signed, err := m.keyset.sign(claims)
 
 	if err != nil {
 
@@ -57,23 +94,29 @@ func (m *Manager) GenerateAccessToken(email string, UserId int) (string, error)
 	return signed, nil
 }
 
-func (m *Manager) GenerateRefreshToken(email string, UserId int) (string, error) {
+func (m *Manager) GenerateRefreshToken(email string, UserId int, sessionID string) (string, error) {
+
+	log.Printf("[jwt] GenerateRefreshToken called for email=%q sid=%s", email, sessionID)
 
-	log.Printf("[jwt] GenerateRefreshToken called for email=%q", email)
+	jti, err := newTokenID()
+	if err != nil {
+		log.Printf("[jwt][ERROR] GenerateRefreshToken jti generation failed: %v", err)
+		return "", err
+	}
 
 	claims := entity.Claims{
 		Email:     email,
 		TokenType: "refresh",
+		SessionID: sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(m.refreshTTL)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			Subject:   strconv.Itoa(UserId),
+			ID:        jti,
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-
-	signed, err := token.SignedString(m.secretKey)
+	signed, err := m.keyset.sign(claims)
 
 	if err != nil {
 
@@ -87,7 +130,101 @@ func (m *Manager) GenerateRefreshToken(email string, UserId int) (string, error)
 	return signed, nil
 }
 
-func (m *Manager) ValidateAccessToken(tokenString string) (*entity.Claims, error) {
+// GenerateMFAToken выдаёт короткоживущий токен, подтверждающий, что пароль уже проверен:
+// с ним и кодом второго фактора клиент получает обычную пару токенов.
+func (m *Manager) GenerateMFAToken(email string, UserId int) (string, error) {
+
+	log.Printf("[jwt] GenerateMFAToken called for email=%q", email)
+
+	jti, err := newTokenID()
+	if err != nil {
+		log.Printf("[jwt][ERROR] GenerateMFAToken jti generation failed: %v", err)
+		return "", err
+	}
+
+	claims := entity.Claims{
+		Email:     email,
+		TokenType: "mfa",
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(m.mfaTTL)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			Subject:   strconv.Itoa(UserId),
+			ID:        jti,
+		},
+	}
+
+	signed, err := m.keyset.sign(claims)
+
+	if err != nil {
+
+		log.Printf("[jwt][ERROR] GenerateMFAToken signing failed: %v", err)
+
+		return "", err
+	}
+
+	log.Println("[jwt] GenerateMFAToken successful")
+
+	return signed, nil
+}
+
+// GenerateEmailVerificationToken выдаёт токен для ссылки подтверждения email. Токен привязан к адресу:
+// после смены email он не подтверждает новый.
+func (m *Manager) GenerateEmailVerificationToken(email string, UserId int) (string, error) {
+	log.Printf("[jwt] GenerateEmailVerificationToken called for email=%q", email)
+
+	return m.generateEmailToken(TokenTypeEmailVerification, email, UserId, "", m.verifyTTL)
+}
+
+// GenerateEmailChangeToken выдаёт токен для ссылки, которая подтверждает новый адрес и переключает на него
+// учётную запись. Новый адрес хранится в claim email.
+func (m *Manager) GenerateEmailChangeToken(newEmail string, UserId int) (string, error) {
+	log.Printf("[jwt] GenerateEmailChangeToken called for email=%q", newEmail)
+
+	return m.generateEmailToken(TokenTypeEmailChange, newEmail, UserId, "", m.verifyTTL)
+}
+
+// GenerateMagicLinkToken выдаёт токен для ссылки входа без пароля. Непустой deviceHash привязывает ссылку
+// к устройству: обменять её на токены можно, только предъявив секрет этого устройства.
+func (m *Manager) GenerateMagicLinkToken(email string, UserId int, deviceHash string) (string, error) {
+	log.Printf("[jwt] GenerateMagicLinkToken called for email=%q deviceBound=%t", email, deviceHash != "")
+
+	return m.generateEmailToken(TokenTypeMagicLink, email, UserId, deviceHash, m.magicTTL)
+}
+
+func (m *Manager) generateEmailToken(tokenType, email string, UserId int, deviceHash string, ttl time.Duration) (string, error) {
+	jti, err := newTokenID()
+	if err != nil {
+		log.Printf("[jwt][ERROR] generate %s token: jti generation failed: %v", tokenType, err)
+		return "", err
+	}
+
+	claims := entity.Claims{
+		Email:      email,
+		TokenType:  tokenType,
+		DeviceHash: deviceHash,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(ttl)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			Subject:   strconv.Itoa(UserId),
+			ID:        jti,
+		},
+	}
+
+	signed, err := m.keyset.sign(claims)
+
+	if err != nil {
+
+		log.Printf("[jwt][ERROR] generate %s token: signing failed: %v", tokenType, err)
+
+		return "", err
+	}
+
+	log.Printf("[jwt] %s token generated", tokenType)
+
+	return signed, nil
+}
+
+func (m *Manager) ValidateAccessToken(ctx context.Context, tokenString string) (*entity.Claims, error) {
 	log.Println("[jwt] ValidateAccessToken called")
 	claims, err := m.parseToken(tokenString)
 	if err != nil {
@@ -99,11 +236,77 @@ func (m *Manager) ValidateAccessToken(tokenString string) (*entity.Claims, error
 		log.Printf("[jwt][ERROR] ValidateAccessToken wrong token type: %v", err)
 		return nil, err
 	}
+	if err := m.checkDenylist(ctx, claims); err != nil {
+		log.Printf("[jwt][ERROR] ValidateAccessToken denylist check failed: %v", err)
+		return nil, err
+	}
 	log.Printf("[jwt] ValidateAccessToken successful: subject=%s email=%s", claims.Subject, claims.Email)
 	return claims, nil
 }
 
-func (m *Manager) ValidateRefreshToken(tokenString string) (*entity.Claims, error) {
+func (m *Manager) ValidateMFAToken(ctx context.Context, tokenString string) (*entity.Claims, error) {
+	log.Println("[jwt] ValidateMFAToken called")
+	claims, err := m.parseToken(tokenString)
+	if err != nil {
+		log.Printf("[jwt][ERROR] ValidateMFAToken parseToken failed: %v", err)
+		return nil, err
+	}
+	if claims.TokenType != "mfa" {
+		err := fmt.Errorf("expected mfa token, got %q", claims.TokenType)
+		log.Printf("[jwt][ERROR] ValidateMFAToken wrong token type: %v", err)
+		return nil, err
+	}
+	if err := m.checkDenylist(ctx, claims); err != nil {
+		log.Printf("[jwt][ERROR] ValidateMFAToken denylist check failed: %v", err)
+		return nil, err
+	}
+	log.Printf("[jwt] ValidateMFAToken successful: subject=%s", claims.Subject)
+	return claims, nil
+}
+
+// ValidateEmailToken проверяет токен из письма: подтверждения email или смены адреса.
+// Тип токена вызывающий узнаёт из claims.TokenType.
+func (m *Manager) ValidateEmailToken(ctx context.Context, tokenString string) (*entity.Claims, error) {
+	log.Println("[jwt] ValidateEmailToken called")
+	claims, err := m.parseToken(tokenString)
+	if err != nil {
+		log.Printf("[jwt][ERROR] ValidateEmailToken parseToken failed: %v", err)
+		return nil, err
+	}
+	if claims.TokenType != TokenTypeEmailVerification && claims.TokenType != TokenTypeEmailChange {
+		err := fmt.Errorf("expected email token, got %q", claims.TokenType)
+		log.Printf("[jwt][ERROR] ValidateEmailToken wrong token type: %v", err)
+		return nil, err
+	}
+	if err := m.checkDenylist(ctx, claims); err != nil {
+		log.Printf("[jwt][ERROR] ValidateEmailToken denylist check failed: %v", err)
+		return nil, err
+	}
+	log.Printf("[jwt] ValidateEmailToken successful: subject=%s type=%s", claims.Subject, claims.TokenType)
+	return claims, nil
+}
+
+func (m *Manager) ValidateMagicLinkToken(ctx context.Context, tokenString string) (*entity.Claims, error) {
+	log.Println("[jwt] ValidateMagicLinkToken called")
+	claims, err := m.parseToken(tokenString)
+	if err != nil {
+		log.Printf("[jwt][ERROR] ValidateMagicLinkToken parseToken failed: %v", err)
+		return nil, err
+	}
+	if claims.TokenType != TokenTypeMagicLink {
+		err := fmt.Errorf("expected magic_link token, got %q", claims.TokenType)
+		log.Printf("[jwt][ERROR] ValidateMagicLinkToken wrong token type: %v", err)
+		return nil, err
+	}
+	if err := m.checkDenylist(ctx, claims); err != nil {
+		log.Printf("[jwt][ERROR] ValidateMagicLinkToken denylist check failed: %v", err)
+		return nil, err
+	}
+	log.Printf("[jwt] ValidateMagicLinkToken successful: subject=%s", claims.Subject)
+	return claims, nil
+}
+
+func (m *Manager) ValidateRefreshToken(ctx context.Context, tokenString string) (*entity.Claims, error) {
 	log.Println("[jwt] ValidateRefreshToken called")
 	claims, err := m.parseToken(tokenString)
 	if err != nil {
@@ -115,19 +318,90 @@ func (m *Manager) ValidateRefreshToken(tokenString string) (*entity.Claims, erro
 		log.Printf("[jwt][ERROR] ValidateRefreshToken wrong token type: %v", err)
 		return nil, err
 	}
+	if err := m.checkDenylist(ctx, claims); err != nil {
+		log.Printf("[jwt][ERROR] ValidateRefreshToken denylist check failed: %v", err)
+		return nil, err
+	}
 	log.Printf("[jwt] ValidateRefreshToken successful: subject=%s email=%s", claims.Subject, claims.Email)
 	return claims, nil
 }
 
+// Revoke вносит access-, refresh-, MFA- или одноразовый токен подтверждения email в denylist до истечения его срока действия.
+// Повторный отзыв уже отозванного токена не считается ошибкой.
+func (m *Manager) Revoke(ctx context.Context, tokenString string) (*entity.Claims, error) {
+	log.Println("[jwt] Revoke called")
+	claims, err := m.parseToken(tokenString)
+	if err != nil {
+		log.Printf("[jwt][ERROR] Revoke parseToken failed: %v", err)
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+	if claims.ID == "" {
+		log.Printf("[jwt][ERROR] Revoke: token has no jti")
+		return nil, fmt.Errorf("%w: token has no jti", ErrInvalidToken)
+	}
+	if err := m.denylist.Add(ctx, claims.ID, claims.ExpiresAt.Time); err != nil {
+		log.Printf("[jwt][ERROR] Revoke denylist add failed: %v", err)
+		return nil, err
+	}
+	log.Printf("[jwt] Revoke successful: jti=%s subject=%s", claims.ID, claims.Subject)
+	return claims, nil
+}
+
+// RevokeSession вносит в denylist все токены сессии sessionID, чтобы её access-токены перестали приниматься
+// сразу после закрытия сессии, без чтения сессии из Redis на каждый запрос. Запись живёт, пока не истекут
+// выданные сессии access-токены; refresh-токены и так проверяются по самой сессии.
+func (m *Manager) RevokeSession(ctx context.Context, sessionID string) error {
+	log.Printf("[jwt] RevokeSession called: sid=%s", sessionID)
+	if err := m.denylist.Add(ctx, sessionDenylistKey(sessionID), time.Now().Add(m.accessTTL)); err != nil {
+		log.Printf("[jwt][ERROR] RevokeSession denylist add failed: %v", err)
+		return err
+	}
+	return nil
+}
+
+// sessionKeyPrefix отделяет записи сессий от jti: те состоят только из шестнадцатеричных цифр.
+const sessionKeyPrefix = "sid:"
+
+func sessionDenylistKey(sessionID string) string {
+	return sessionKeyPrefix + sessionID
+}
+
+func isSessionKey(key string) bool {
+	return strings.HasPrefix(key, sessionKeyPrefix)
+}
+
+// checkDenylist отклоняет отозванные токены и токены закрытых сессий. Токены, выданные до появления jti,
+// в denylist попасть не могут.
+func (m *Manager) checkDenylist(ctx context.Context, claims *entity.Claims) error {
+	keys := make([]string, 0, 2)
+	if claims.ID != "" {
+		keys = append(keys, claims.ID)
+	}
+	if claims.SessionID != "" {
+		keys = append(keys, sessionDenylistKey(claims.SessionID))
+	}
+
+	for _, key := range keys {
+		denied, err := m.denylist.Contains(ctx, key)
+		if err != nil {
+			return fmt.Errorf("denylist lookup: %w", err)
+		}
+		if denied {
+			return ErrTokenRevoked
+		}
+	}
+	return nil
+}
+
 func (m *Manager) parseToken(tokenString string) (*entity.Claims, error) {
 	log.Printf("[jwt] parseToken called")
 	token, err := jwt.ParseWithClaims(tokenString, &entity.Claims{}, func(t *jwt.Token) (interface{}, error) {
-		if t.Method != jwt.SigningMethodHS256 {
-			err := fmt.Errorf("unexpected signing method %v", t.Header["alg"])
-			log.Printf("[jwt][ERROR] parseToken unexpected signing method: %v", err)
+		key, err := m.keyset.verificationKey(t)
+		if err != nil {
+			log.Printf("[jwt][ERROR] parseToken verification key: %v", err)
 			return nil, err
 		}
-		return m.secretKey, nil
+		return key, nil
 	})
 	if err != nil {
 		log.Printf("[jwt][ERROR] parseToken parsing failed: %v", err)
@@ -146,8 +420,9 @@ func (m *Manager) parseToken(tokenString string) (*entity.Claims, error) {
 	}
 
 	log.Printf(
-		"[jwt] parseToken successful: tokenType=%s subject=%s email=%s issuedAt=%s expiresAt=%s",
+		"[jwt] parseToken successful: tokenType=%s jti=%s subject=%s email=%s issuedAt=%s expiresAt=%s",
 		claims.TokenType,
+		claims.ID,
 		claims.Subject,
 		claims.Email,
 		claims.IssuedAt.Time.Format(time.RFC3339),
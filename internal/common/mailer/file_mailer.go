@@ -0,0 +1,84 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"mime"
+	"mime/quotedprintable"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// FileMailer — почта для разработки: каждое письмо сохраняется в каталог отдельным .eml-файлом,
+// который открывается любым почтовым клиентом.
+type FileMailer struct {
+	from string
+	dir  string
+}
+
+func NewFileMailer(from, dir string) (*FileMailer, error) {
+	log.Printf("[mailer:file] NewFileMailer initialized: dir=%s", dir)
+
+	if _, err := mail.ParseAddress(from); err != nil {
+		return nil, fmt.Errorf("invalid sender address %q: %w", from, err)
+	}
+	// в письмах ссылки с одноразовыми токенами: каталог доступен только владельцу процесса
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("create mail dir: %w", err)
+	}
+
+	return &FileMailer{from: from, dir: dir}, nil
+}
+
+func (fm *FileMailer) Send(_ context.Context, msg Message) error {
+	if _, err := mail.ParseAddress(msg.To); err != nil {
+		return fmt.Errorf("invalid recipient address %q: %w", msg.To, err)
+	}
+
+	id, err := randomID()
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", fm.from)
+	fmt.Fprintf(&buf, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", now.Format(time.RFC1123Z))
+	fmt.Fprintf(&buf, "Message-ID: <%s@marketplace>\r\n", id)
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+
+	qp := quotedprintable.NewWriter(&buf)
+	if _, err := qp.Write([]byte(msg.Body)); err != nil {
+		return fmt.Errorf("encode body: %w", err)
+	}
+	if err := qp.Close(); err != nil {
+		return fmt.Errorf("encode body: %w", err)
+	}
+
+	name := filepath.Join(fm.dir, fmt.Sprintf("%s-%s.eml", now.Format("20060102-150405"), id))
+	if err := os.WriteFile(name, buf.Bytes(), 0o600); err != nil {
+		log.Printf("[mailer:file][ERROR] write %s: %v", name, err)
+		return fmt.Errorf("write message: %w", err)
+	}
+
+	log.Printf("[mailer:file] message to %s saved to %s", msg.To, name)
+	return nil
+}
+
+func randomID() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate message id: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,28 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"github.com/1URose/marketplace/internal/common/config/mail"
+)
+
+// Message — простое текстовое письмо.
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer отправляет письма пользователям. Бэкенд выбирается через MAIL_BACKEND.
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+func New(cfg *mail.Config) (Mailer, error) {
+	switch cfg.Backend {
+	case mail.BackendFile:
+		return NewFileMailer(cfg.From, cfg.DevDir)
+	default:
+		return nil, fmt.Errorf("unsupported mail backend %q", cfg.Backend)
+	}
+}
@@ -0,0 +1,88 @@
+package password
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+)
+
+// rangePrefixLen — длина префикса SHA-1, по которому запрашивается диапазон, как в k-anonymity API Have I Been Pwned.
+const rangePrefixLen = 5
+
+// BreachedSource возвращает суффиксы SHA-1 утёкших паролей с заданным префиксом. Источнику передаётся
+// только префикс хеша, поэтому его можно заменить внешним сервисом, не раскрывая пароль.
+type BreachedSource interface {
+	Range(ctx context.Context, prefix string) ([]string, error)
+}
+
+// IsBreached сообщает, встречается ли пароль в источнике утёкших паролей.
+func IsBreached(ctx context.Context, source BreachedSource, password string) (bool, error) {
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+
+	suffixes, err := source.Range(ctx, hash[:rangePrefixLen])
+	if err != nil {
+		return false, err
+	}
+
+	for _, s := range suffixes {
+		if s == hash[rangePrefixLen:] {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// FileBreachedSource — локальный набор утёкших паролей в формате Have I Been Pwned: по строке
+// `<SHA-1 в hex>[:<число утечек>]`, строки с # пропускаются. Файл целиком читается в память при старте.
+type FileBreachedSource struct {
+	ranges map[string][]string
+}
+
+func NewFileBreachedSource(path string) (*FileBreachedSource, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open breached password dataset: %w", err)
+	}
+	defer f.Close()
+
+	ranges := make(map[string][]string)
+	count := 0
+
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		hash, _, _ := strings.Cut(text, ":")
+		hash = strings.ToUpper(hash)
+		if len(hash) != sha1.Size*2 {
+			return nil, fmt.Errorf("breached password dataset %s:%d: malformed SHA-1 %q", path, line, hash)
+		}
+		if _, err := hex.DecodeString(hash); err != nil {
+			return nil, fmt.Errorf("breached password dataset %s:%d: %w", path, line, err)
+		}
+
+		prefix := hash[:rangePrefixLen]
+		ranges[prefix] = append(ranges[prefix], hash[rangePrefixLen:])
+		count++
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read breached password dataset: %w", err)
+	}
+
+	log.Printf("[password] breached password dataset loaded: path=%s hashes=%d", path, count)
+
+	return &FileBreachedSource{ranges: ranges}, nil
+}
+
+func (fs *FileBreachedSource) Range(_ context.Context, prefix string) ([]string, error) {
+	return fs.ranges[strings.ToUpper(prefix)], nil
+}
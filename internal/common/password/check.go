@@ -1,18 +1,15 @@
 package password
 
 import (
-	"golang.org/x/crypto/bcrypt"
 	"log"
 )
 
 func CheckPasswordHash(password, hash string) bool {
 	log.Println("[password] verifying password hash")
 
-	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+	if !defaultHasher.Verify(password, hash) {
 
-	if err != nil {
-
-		log.Printf("[password][ERROR] password verification failed: %v", err)
+		log.Printf("[password][ERROR] password verification failed")
 
 		return false
 	}
@@ -21,3 +18,8 @@ func CheckPasswordHash(password, hash string) bool {
 
 	return true
 }
+
+// NeedsRehash сообщает, что хеш стоит пересчитать текущим алгоритмом при следующей проверке пароля.
+func NeedsRehash(hash string) bool {
+	return defaultHasher.NeedsRehash(hash)
+}
@@ -2,15 +2,13 @@ package password
 
 import (
 	"log"
-
-	"golang.org/x/crypto/bcrypt"
 )
 
 func HashPassword(password string) (string, error) {
 
 	log.Println("[password] hashing password")
 
-	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	hash, err := defaultHasher.Hash(password)
 
 	if err != nil {
 
@@ -21,5 +19,5 @@ func HashPassword(password string) (string, error) {
 
 	log.Println("[password] password hashed successfully")
 
-	return string(hash), nil
+	return hash, nil
 }
@@ -0,0 +1,199 @@
+package password
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Алгоритмы хеширования паролей.
+const (
+	AlgorithmArgon2id = "argon2id"
+	AlgorithmBcrypt   = "bcrypt"
+)
+
+const (
+	// BcryptMaxBytes — bcrypt учитывает только первые 72 байта пароля.
+	BcryptMaxBytes = 72
+
+	argon2SaltLen = 16
+	argon2KeyLen  = 32
+)
+
+var (
+	// ErrPasswordTooLong — пароль длиннее, чем принимает bcrypt; молча обрезать его нельзя.
+	ErrPasswordTooLong  = fmt.Errorf("password exceeds %d bytes", BcryptMaxBytes)
+	ErrUnknownAlgorithm = errors.New("unknown password hash algorithm")
+)
+
+// Params — алгоритм новых хешей и его параметры.
+type Params struct {
+	Algorithm     string
+	Argon2Memory  uint32
+	Argon2Time    uint32
+	Argon2Threads uint8
+	BcryptCost    int
+}
+
+// DefaultParams — argon2id с параметрами из рекомендаций OWASP.
+var DefaultParams = Params{
+	Algorithm:     AlgorithmArgon2id,
+	Argon2Memory:  64 * 1024,
+	Argon2Time:    3,
+	Argon2Threads: 2,
+	BcryptCost:    bcrypt.DefaultCost,
+}
+
+// defaultHasher задаётся при старте сервиса через SetDefault и дальше не меняется.
+var defaultHasher = &Hasher{params: DefaultParams}
+
+// SetDefault задаёт хешер для HashPassword, CheckPasswordHash и NeedsRehash.
+func SetDefault(h *Hasher) {
+	defaultHasher = h
+}
+
+// Hasher хеширует пароли текущим алгоритмом, а проверяет хеши любого поддерживаемого формата:
+// argon2id в формате PHC ($argon2id$v=19$m=...,t=...,p=...$salt$hash) и bcrypt ($2a$, $2b$, $2y$).
+// Алгоритм и параметры записаны в самом хеше, поэтому их можно менять без миграции данных.
+type Hasher struct {
+	params Params
+}
+
+func NewHasher(params Params) (*Hasher, error) {
+	switch params.Algorithm {
+	case AlgorithmArgon2id:
+		if params.Argon2Memory == 0 || params.Argon2Time == 0 || params.Argon2Threads == 0 {
+			return nil, fmt.Errorf("argon2id parameters must be positive: %+v", params)
+		}
+	case AlgorithmBcrypt:
+		if params.BcryptCost < bcrypt.MinCost || params.BcryptCost > bcrypt.MaxCost {
+			return nil, fmt.Errorf("bcrypt cost %d out of range", params.BcryptCost)
+		}
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownAlgorithm, params.Algorithm)
+	}
+
+	log.Printf("[password] NewHasher initialized: algorithm=%s", params.Algorithm)
+
+	return &Hasher{params: params}, nil
+}
+
+func (h *Hasher) Hash(password string) (string, error) {
+	if h.params.Algorithm == AlgorithmBcrypt {
+		if len(password) > BcryptMaxBytes {
+			return "", ErrPasswordTooLong
+		}
+		hash, err := bcrypt.GenerateFromPassword([]byte(password), h.params.BcryptCost)
+		if err != nil {
+			return "", err
+		}
+		return string(hash), nil
+	}
+
+	salt := make([]byte, argon2SaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	key := argon2.IDKey([]byte(password), salt, h.params.Argon2Time, h.params.Argon2Memory, h.params.Argon2Threads, argon2KeyLen)
+
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, h.params.Argon2Memory, h.params.Argon2Time, h.params.Argon2Threads,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+// Verify сверяет пароль с хешем любого поддерживаемого формата. Пустой хеш (пользователь без пароля)
+// не совпадает ни с чем.
+func (h *Hasher) Verify(password, encoded string) bool {
+	switch {
+	case encoded == "":
+		return false
+	case isBcrypt(encoded):
+		// bcrypt сравнил бы только первые 72 байта, и более длинный пароль с тем же началом тоже подошёл бы
+		if len(password) > BcryptMaxBytes {
+			log.Printf("[password][ERROR] password exceeds %d bytes, rejected for bcrypt hash", BcryptMaxBytes)
+			return false
+		}
+		return bcrypt.CompareHashAndPassword([]byte(encoded), []byte(password)) == nil
+	default:
+		a, err := parseArgon2id(encoded)
+		if err != nil {
+			log.Printf("[password][ERROR] unsupported password hash: %v", err)
+			return false
+		}
+		key := argon2.IDKey([]byte(password), a.salt, a.time, a.memory, a.threads, uint32(len(a.key)))
+		return subtle.ConstantTimeCompare(key, a.key) == 1
+	}
+}
+
+// NeedsRehash сообщает, что хеш создан другим алгоритмом или с другими параметрами, чем текущие.
+func (h *Hasher) NeedsRehash(encoded string) bool {
+	switch {
+	case encoded == "":
+		return false
+	case isBcrypt(encoded):
+		if h.params.Algorithm != AlgorithmBcrypt {
+			return true
+		}
+		cost, err := bcrypt.Cost([]byte(encoded))
+		return err == nil && cost != h.params.BcryptCost
+	default:
+		a, err := parseArgon2id(encoded)
+		if err != nil {
+			return false
+		}
+		return h.params.Algorithm != AlgorithmArgon2id ||
+			a.version != argon2.Version ||
+			a.memory != h.params.Argon2Memory ||
+			a.time != h.params.Argon2Time ||
+			a.threads != h.params.Argon2Threads
+	}
+}
+
+func isBcrypt(encoded string) bool {
+	return strings.HasPrefix(encoded, "$2a$") || strings.HasPrefix(encoded, "$2b$") || strings.HasPrefix(encoded, "$2y$")
+}
+
+type argon2idHash struct {
+	version int
+	memory  uint32
+	time    uint32
+	threads uint8
+	salt    []byte
+	key     []byte
+}
+
+func parseArgon2id(encoded string) (*argon2idHash, error) {
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 || parts[1] != AlgorithmArgon2id {
+		return nil, ErrUnknownAlgorithm
+	}
+
+	var a argon2idHash
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &a.version); err != nil {
+		return nil, fmt.Errorf("argon2id version: %w", err)
+	}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &a.memory, &a.time, &a.threads); err != nil {
+		return nil, fmt.Errorf("argon2id params: %w", err)
+	}
+
+	var err error
+	if a.salt, err = base64.RawStdEncoding.DecodeString(parts[4]); err != nil {
+		return nil, fmt.Errorf("argon2id salt: %w", err)
+	}
+	if a.key, err = base64.RawStdEncoding.DecodeString(parts[5]); err != nil {
+		return nil, fmt.Errorf("argon2id key: %w", err)
+	}
+	if len(a.key) == 0 {
+		return nil, errors.New("argon2id key is empty")
+	}
+
+	return &a, nil
+}
@@ -0,0 +1,183 @@
+package password
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/1URose/marketplace/internal/common/i18n"
+)
+
+// Классы символов, которые политика может требовать в пароле.
+const (
+	ClassLower  = "lower"
+	ClassUpper  = "upper"
+	ClassLetter = "letter"
+	ClassDigit  = "digit"
+	ClassSymbol = "symbol"
+)
+
+// Коды нарушений политики: по ним фронтенд показывает своё сообщение у поля пароля.
+const (
+	CodeTooShort      = "too_short"
+	CodeTooLong       = "too_long"
+	CodeMissingLower  = "missing_lowercase"
+	CodeMissingUpper  = "missing_uppercase"
+	CodeMissingLetter = "missing_letter"
+	CodeMissingDigit  = "missing_digit"
+	CodeMissingSymbol = "missing_symbol"
+	CodeContainsEmail = "contains_email"
+	CodeBreached      = "breached"
+)
+
+var classCodes = map[string]string{
+	ClassLower:  CodeMissingLower,
+	ClassUpper:  CodeMissingUpper,
+	ClassLetter: CodeMissingLetter,
+	ClassDigit:  CodeMissingDigit,
+	ClassSymbol: CodeMissingSymbol,
+}
+
+// minEmailPartLen — часть email до @ короче этого не проверяется: иначе «ann» запрещала бы «planning».
+const minEmailPartLen = 3
+
+// Violation — одна причина, по которой пароль не принят. Key — ключ сообщения в каталоге i18n (password.<код>).
+type Violation struct {
+	Code    string
+	Key     string
+	Message string
+	Params  map[string]interface{}
+}
+
+// MessageKey возвращает ключ сообщения в каталоге i18n для кода нарушения.
+func MessageKey(code string) string {
+	return "password." + code
+}
+
+// PolicyError возвращается, когда пароль нарушает политику; содержит все нарушения сразу.
+type PolicyError struct {
+	Violations []Violation
+}
+
+func (e *PolicyError) Error() string {
+	codes := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		codes[i] = v.Code
+	}
+	return fmt.Sprintf("password violates policy: %s", strings.Join(codes, ", "))
+}
+
+type PolicyRules struct {
+	MinLength       int
+	MaxLength       int
+	RequiredClasses []string
+	RejectEmail     bool
+}
+
+// Policy проверяет новые пароли. Длина считается в символах, а не в байтах.
+type Policy struct {
+	rules    PolicyRules
+	breached BreachedSource
+}
+
+// NewPolicy создаёт политику; breached может быть nil — тогда утёкшие пароли не проверяются.
+func NewPolicy(rules PolicyRules, breached BreachedSource) (*Policy, error) {
+	for _, c := range rules.RequiredClasses {
+		if _, ok := classCodes[c]; !ok {
+			return nil, fmt.Errorf("unknown password character class %q", c)
+		}
+	}
+
+	log.Printf("[password] NewPolicy initialized: rules=%+v breachedCheck=%t", rules, breached != nil)
+
+	return &Policy{rules: rules, breached: breached}, nil
+}
+
+// Validate возвращает *PolicyError со всеми нарушениями или nil, если пароль подходит.
+func (p *Policy) Validate(ctx context.Context, password, email string) error {
+	var violations []Violation
+	add := func(code string, params map[string]interface{}) {
+		key := MessageKey(code)
+		violations = append(violations, Violation{
+			Code:    code,
+			Key:     key,
+			Message: i18n.Message(i18n.Default, key, params),
+			Params:  params,
+		})
+	}
+
+	length := utf8.RuneCountInString(password)
+	if length < p.rules.MinLength {
+		add(CodeTooShort, map[string]interface{}{"min": p.rules.MinLength})
+	}
+	if length > p.rules.MaxLength {
+		add(CodeTooLong, map[string]interface{}{"max": p.rules.MaxLength})
+	}
+
+	present := classesOf(password)
+	for _, c := range p.rules.RequiredClasses {
+		if !present[c] {
+			add(classCodes[c], map[string]interface{}{"class": c})
+		}
+	}
+
+	if p.rules.RejectEmail && containsEmail(password, email) {
+		add(CodeContainsEmail, nil)
+	}
+
+	if p.breached != nil {
+		breached, err := IsBreached(ctx, p.breached, password)
+		if err != nil {
+			log.Printf("[password][ERROR] breached password check failed: %v", err)
+			return err
+		}
+		if breached {
+			add(CodeBreached, nil)
+		}
+	}
+
+	if len(violations) > 0 {
+		log.Printf("[password] password rejected by policy: %d violation(s)", len(violations))
+		return &PolicyError{Violations: violations}
+	}
+	return nil
+}
+
+func classesOf(password string) map[string]bool {
+	present := make(map[string]bool, len(classCodes))
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			present[ClassLower] = true
+			present[ClassLetter] = true
+		case unicode.IsUpper(r):
+			present[ClassUpper] = true
+			present[ClassLetter] = true
+		case unicode.IsLetter(r):
+			present[ClassLetter] = true
+		case unicode.IsDigit(r):
+			present[ClassDigit] = true
+		case !unicode.IsSpace(r):
+			present[ClassSymbol] = true
+		}
+	}
+	return present
+}
+
+func containsEmail(password, email string) bool {
+	if email == "" {
+		return false
+	}
+
+	lowered := strings.ToLower(password)
+	email = strings.ToLower(email)
+	if strings.Contains(lowered, email) {
+		return true
+	}
+
+	local, _, _ := strings.Cut(email, "@")
+	return utf8.RuneCountInString(local) >= minEmailPartLen && strings.Contains(lowered, local)
+}
@@ -19,6 +19,19 @@ func GetEnvSrt(key string) string {
 	return v
 }
 
+// GetEnvSrtOrDefault — для необязательных переменных: пустое значение заменяется def.
+func GetEnvSrtOrDefault(key, def string) string {
+	v := os.Getenv(key)
+
+	log.Printf("[settings] env %s: %s", key, v)
+
+	if v == "" {
+		return def
+	}
+
+	return v
+}
+
 func GetEnvInt32(key string) (int32, error) {
 	valueStr := GetEnvSrt(key)
 
@@ -0,0 +1,208 @@
+// Package bind разбирает тело и параметры запроса и возвращает ошибки разбора как ошибки валидации apperror.
+// Нарушения тегов binding перечисляются по всем полям сразу, с именами полей из тегов json и form.
+package bind
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/1URose/marketplace/internal/common/apperror"
+	"github.com/1URose/marketplace/internal/common/i18n"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+var (
+	ErrInvalidBody  = apperror.Validation("request.invalid_body")
+	ErrInvalidQuery = apperror.Validation("request.invalid_query")
+)
+
+func init() {
+	// без этого нарушения называли бы поля именами Go-структур, а не так, как их передаёт клиент
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterTagNameFunc(fieldName)
+	}
+}
+
+func JSON(ctx *gin.Context, obj interface{}) error {
+	if err := ctx.ShouldBindJSON(obj); err != nil {
+		return bindError(ErrInvalidBody, err)
+	}
+	return nil
+}
+
+func Query(ctx *gin.Context, obj interface{}) error {
+	if err := ctx.ShouldBindQuery(obj); err != nil {
+		return bindError(ErrInvalidQuery, err)
+	}
+	return nil
+}
+
+// Parsed сообщает, что запрос удалось разобрать, пусть и с нарушениями по полям. Тогда стоит выполнить
+// и собственные проверки, чтобы клиент сразу увидел все ошибки, а не только первую группу.
+func Parsed(err error) bool {
+	if err == nil {
+		return true
+	}
+	appErr, ok := apperror.From(err)
+	return ok && len(appErr.Violations) > 0
+}
+
+// Merge дополняет ошибку разбора err нарушениями из checkErr. Поле, уже отклонённое тегами binding,
+// второй раз в ответ не попадает.
+func Merge(err, checkErr error) error {
+	if checkErr == nil {
+		return err
+	}
+	if err == nil {
+		return checkErr
+	}
+
+	bindErr, ok := apperror.From(err)
+	if !ok {
+		return err
+	}
+	checkAppErr, ok := apperror.From(checkErr)
+	if !ok {
+		return checkErr
+	}
+
+	reported := make(map[string]bool, len(bindErr.Violations))
+	for _, v := range bindErr.Violations {
+		reported[v.Field] = true
+	}
+
+	extra := make([]apperror.Violation, 0, len(checkAppErr.Violations))
+	for _, v := range checkAppErr.Violations {
+		if !reported[v.Field] {
+			extra = append(extra, v)
+		}
+	}
+	return bindErr.WithViolations(extra...)
+}
+
+func bindError(base *apperror.Error, err error) error {
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		violations := make([]apperror.Violation, len(validationErrs))
+		for i, fe := range validationErrs {
+			violations[i] = newViolation(fe)
+		}
+		return base.WithViolations(violations...)
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		params := map[string]interface{}{"type": typeErr.Type.String()}
+		return base.WithViolations(apperror.Violation{
+			Field:   typeErr.Field,
+			Code:    "type",
+			Key:     "validation.type",
+			Message: i18n.Message(i18n.Default, "validation.type", params),
+			Params:  params,
+		})
+	}
+
+	// текст ошибок json и gin клиенту не отдаётся: он раскрывает внутреннее устройство и не переводится
+	switch {
+	case errors.Is(err, io.EOF):
+		return base.WithDetailKey("request.empty_body")
+	case errors.As(err, new(*json.SyntaxError)), errors.Is(err, io.ErrUnexpectedEOF):
+		return base.WithDetailKey("request.malformed_json")
+	case errors.As(err, &typeErr):
+		return base.WithDetailKey("request.invalid_json_type")
+	}
+
+	log.Printf("[bind][ERROR] unrecognized bind error: %v", err)
+	return base
+}
+
+func newViolation(fe validator.FieldError) apperror.Violation {
+	// Namespace начинается с имени структуры запроса: "CreateAdRequest.title"
+	field := fe.Namespace()
+	if i := strings.IndexByte(field, '.'); i >= 0 {
+		field = field[i+1:]
+	}
+
+	v := apperror.Violation{
+		Field: field,
+		Code:  fe.Tag(),
+		Key:   tagKey(fe),
+	}
+	if p := fe.Param(); p != "" {
+		v.Params = map[string]interface{}{paramName(fe.Tag()): paramValue(fe.Tag(), p)}
+	}
+	v.Message = i18n.Message(i18n.Default, v.Key, v.Params)
+	return v
+}
+
+// tagKey выбирает ключ сообщения по тегу; для ограничений длины строки и размера коллекции
+// нужны разные формулировки, поэтому к ключу добавляется суффикс.
+func tagKey(fe validator.FieldError) string {
+	suffix := ""
+	switch fe.Kind() {
+	case reflect.String:
+		suffix = "_string"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		suffix = "_items"
+	}
+
+	switch fe.Tag() {
+	case "required", "required_without", "oneof", "email", "url", "ip", "numeric":
+		return "validation." + fe.Tag()
+	case "min", "gte":
+		return "validation.min" + suffix
+	case "max", "lte":
+		return "validation.max" + suffix
+	case "len":
+		return "validation.len" + suffix
+	default:
+		return "validation.invalid"
+	}
+}
+
+func paramName(tag string) string {
+	switch tag {
+	case "min", "gte":
+		return "min"
+	case "max", "lte":
+		return "max"
+	case "len":
+		return "len"
+	case "oneof":
+		return "allowed"
+	case "required_without":
+		return "other"
+	default:
+		return "param"
+	}
+}
+
+// paramValue отдаёт числовые ограничения числом, как и проверки validator.AdAllowedValues,
+// а допустимые значения oneof — списком.
+func paramValue(tag, p string) interface{} {
+	if tag == "oneof" {
+		return strings.Fields(p)
+	}
+	if n, err := strconv.Atoi(p); err == nil {
+		return n
+	}
+	return p
+}
+
+// fieldName берёт имя поля из тега json, а для параметров запроса — из тега form.
+func fieldName(fld reflect.StructField) string {
+	for _, tag := range []string{"json", "form"} {
+		name := strings.SplitN(fld.Tag.Get(tag), ",", 2)[0]
+		if name != "" && name != "-" {
+			return name
+		}
+	}
+	return fld.Name
+}
@@ -1,6 +1,23 @@
 package dto
 
+// ErrorResponse — описание ошибки в формате RFC 7807, отдаётся с Content-Type application/problem+json.
 type ErrorResponse struct {
-	Error  string `json:"error"`
+	// Type — URI класса ошибки, например urn:marketplace:problem:validation.
+	Type   string `json:"type"`
+	Title  string `json:"title"`
+	Status int    `json:"status"`
 	Detail string `json:"detail,omitempty"`
+	// Instance — путь запроса, на который получена ошибка.
+	Instance string `json:"instance"`
+	// RequestID совпадает с заголовком X-Request-ID и записью в логе сервера.
+	RequestID string `json:"request_id"`
+	// Violations — причины отказа по отдельным полям запроса, которые фронтенд показывает рядом с полем.
+	Violations []FieldViolation `json:"violations,omitempty"`
+}
+
+type FieldViolation struct {
+	Field   string                 `json:"field"`
+	Code    string                 `json:"code"`
+	Message string                 `json:"message"`
+	Params  map[string]interface{} `json:"params,omitempty"`
 }
@@ -0,0 +1,96 @@
+package middleware
+
+import (
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/1URose/marketplace/internal/common/apperror"
+	"github.com/1URose/marketplace/internal/common/i18n"
+	dtoErr "github.com/1URose/marketplace/internal/common/transport/rest/dto"
+	"github.com/gin-gonic/gin"
+)
+
+var statusByKind = map[apperror.Kind]int{
+	apperror.KindValidation:   http.StatusBadRequest,
+	apperror.KindUnauthorized: http.StatusUnauthorized,
+	apperror.KindForbidden:    http.StatusForbidden,
+	apperror.KindNotFound:     http.StatusNotFound,
+	apperror.KindConflict:     http.StatusConflict,
+	apperror.KindRateLimited:  http.StatusTooManyRequests,
+	apperror.KindInternal:     http.StatusInternalServerError,
+}
+
+const (
+	problemContentType = "application/problem+json"
+	problemTypePrefix  = "urn:marketplace:problem:"
+)
+
+// ErrorHandler отвечает на последнюю ошибку, переданную обработчиком в ctx.Error, если ответ ещё не записан.
+// Ответ строится по RFC 7807 (application/problem+json). Статус выбирается по классу ошибки apperror;
+// любая другая ошибка считается внутренней, и клиент получает только общее сообщение, а сама ошибка
+// остаётся в логе вместе с идентификатором запроса. Тексты переводятся на язык из Accept-Language.
+func ErrorHandler() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.Next()
+
+		if len(ctx.Errors) == 0 || ctx.Writer.Written() {
+			return
+		}
+
+		err := ctx.Errors.Last().Err
+		appErr, ok := apperror.From(err)
+		if !ok {
+			appErr = apperror.Internal("request.internal")
+		}
+
+		status, ok := statusByKind[appErr.Kind]
+		if !ok {
+			status = http.StatusInternalServerError
+		}
+		if status >= http.StatusInternalServerError {
+			log.Printf("[http][ERROR] %s %s request_id=%s: %v", ctx.Request.Method, ctx.Request.URL.Path, GetRequestID(ctx), err)
+		}
+
+		if appErr.Kind == apperror.KindRateLimited {
+			ctx.Header("Retry-After", strconv.Itoa(int(math.Ceil(appErr.RetryAfter.Seconds()))))
+		}
+
+		lang := i18n.FromAcceptLanguage(ctx.GetHeader("Accept-Language"))
+		ctx.Header("Content-Language", string(lang))
+		ctx.Header("Vary", "Accept-Language")
+
+		// JSON-рендер gin не перезаписывает уже выставленный Content-Type
+		ctx.Header("Content-Type", problemContentType)
+		ctx.AbortWithStatusJSON(status, newProblem(ctx, status, appErr, lang))
+	}
+}
+
+func newProblem(ctx *gin.Context, status int, appErr *apperror.Error, lang i18n.Lang) dtoErr.ErrorResponse {
+	resp := dtoErr.ErrorResponse{
+		Type:      problemTypePrefix + string(appErr.Kind),
+		Title:     localize(lang, appErr.Key, nil, appErr.Message),
+		Status:    status,
+		Detail:    localize(lang, appErr.DetailKey, nil, appErr.Detail),
+		Instance:  ctx.Request.URL.Path,
+		RequestID: GetRequestID(ctx),
+	}
+	for _, v := range appErr.Violations {
+		resp.Violations = append(resp.Violations, dtoErr.FieldViolation{
+			Field:   v.Field,
+			Code:    v.Code,
+			Message: localize(lang, v.Key, v.Params, v.Message),
+			Params:  v.Params,
+		})
+	}
+	return resp
+}
+
+// localize переводит сообщение по ключу; сообщение без ключа отдаётся как есть.
+func localize(lang i18n.Lang, key string, params map[string]interface{}, fallback string) string {
+	if key == "" {
+		return fallback
+	}
+	return i18n.Message(lang, key, params)
+}
@@ -1,14 +1,14 @@
 package validator
 
 import (
-	"errors"
-	"fmt"
 	"log"
 	"net/http"
 	"strings"
 	"time"
 
+	entityAF "github.com/1URose/marketplace/internal/announcement/domain/ad_filter/entity"
 	"github.com/1URose/marketplace/internal/announcement/transport/rest/ad/dto"
+	"github.com/1URose/marketplace/internal/common/apperror"
 	"github.com/1URose/marketplace/internal/common/config/ad_limits"
 )
 
@@ -24,6 +24,8 @@ type AdAllowedValues struct {
 	MaxPrice          int
 	MaxImageFileSize  int64
 	AllowedImageTypes map[string]struct{}
+	AllowedCategories map[string]struct{}
+	AllowedFacets     map[string]struct{}
 }
 
 func NewAllowedValues(cfg *ad_limits.AdConfig) *AdAllowedValues {
@@ -40,6 +42,12 @@ func NewAllowedValues(cfg *ad_limits.AdConfig) *AdAllowedValues {
 		MaxPrice:          cfg.MaxPrice,
 		MaxImageFileSize:  cfg.MaxImageFileSize,
 		AllowedImageTypes: make(map[string]struct{}),
+		AllowedCategories: make(map[string]struct{}),
+		AllowedFacets: map[string]struct{}{
+			entityAF.FacetPrice:     {},
+			entityAF.FacetCategory:  {},
+			entityAF.FacetCreatedAt: {},
+		},
 	}
 
 	for _, f := range strings.Split(cfg.AllowedSortFields, ",") {
@@ -63,10 +71,13 @@ func NewAllowedValues(cfg *ad_limits.AdConfig) *AdAllowedValues {
 		mimeType := "image/" + clean
 		av.AllowedImageTypes[mimeType] = struct{}{}
 	}
+	for _, c := range cfg.Categories() {
+		av.AllowedCategories[c] = struct{}{}
+	}
 
 	log.Printf(
-		"[validator:ad] NewAllowedValues initialized: sortFields=%d sortOrders=%d imageTypes=%d titleLen=[%d,%d] descLen=[%d,%d] price=[%d,%d] maxImgSize=%d",
-		len(av.AllowedSortFields), len(av.AllowedSortOrders), len(av.AllowedImageTypes),
+		"[validator:ad] NewAllowedValues initialized: sortFields=%d sortOrders=%d imageTypes=%d categories=%d titleLen=[%d,%d] descLen=[%d,%d] price=[%d,%d] maxImgSize=%d",
+		len(av.AllowedSortFields), len(av.AllowedSortOrders), len(av.AllowedImageTypes), len(av.AllowedCategories),
 		av.MinTitleLen, av.MaxTitleLen, av.MinDescriptionLen, av.MaxDescriptionLen,
 		av.MinPrice, av.MaxPrice, av.MaxImageFileSize,
 	)
@@ -79,147 +90,182 @@ func (av *AdAllowedValues) ValidateGetAllAdsRequest(req *dto.GetAllAdsRequest) e
 		req.Page, req.SortBy, req.SortOrder, req.MinPrice, req.MaxPrice,
 	)
 
+	var vs violations
 	if req.Page < 1 {
-		err := fmt.Errorf("page must be ≥ 1, got %d", req.Page)
-		log.Printf("[validator:ad][ERROR] ValidateGetAllAdsRequest: %v", err)
-		return err
+		vs.add("page", "min", map[string]interface{}{"min": 1})
 	}
 	if _, ok := av.AllowedSortFields[req.SortBy]; !ok {
-		err := fmt.Errorf("unsupported sort_by: %q", req.SortBy)
-		log.Printf("[validator:ad][ERROR] ValidateGetAllAdsRequest: %v", err)
-		return err
+		vs.add("sort_by", "unsupported", map[string]interface{}{"value": req.SortBy})
 	}
 	if _, ok := av.AllowedSortOrders[req.SortOrder]; !ok {
-		err := fmt.Errorf("unsupported sort_order: %q", req.SortOrder)
-		log.Printf("[validator:ad][ERROR] ValidateGetAllAdsRequest: %v", err)
-		return err
+		vs.add("sort_order", "unsupported", map[string]interface{}{"value": req.SortOrder})
 	}
-	if req.MinPrice != nil {
-		if *req.MinPrice < 0 {
-			err := fmt.Errorf("min_price must be ≥ 0, got %d", *req.MinPrice)
-			log.Printf("[validator:ad][ERROR] ValidateGetAllAdsRequest: %v", err)
-			return err
-		}
+	if req.MinPrice != nil && *req.MinPrice < 0 {
+		vs.add("min_price", "min", map[string]interface{}{"min": 0})
 	}
-	if req.MaxPrice != nil {
-		if *req.MaxPrice < 0 {
-			err := fmt.Errorf("max_price must be ≥ 0, got %d", *req.MaxPrice)
-			log.Printf("[validator:ad][ERROR] ValidateGetAllAdsRequest: %v", err)
-			return err
-		}
+	if req.MaxPrice != nil && *req.MaxPrice < 0 {
+		vs.add("max_price", "min", map[string]interface{}{"min": 0})
+	}
+	if req.MinPrice != nil && req.MaxPrice != nil && *req.MinPrice > *req.MaxPrice {
+		vs.add("min_price", "greater_than_max", map[string]interface{}{"min_price": *req.MinPrice, "max_price": *req.MaxPrice})
 	}
-	if req.MinPrice != nil && req.MaxPrice != nil {
-		if *req.MinPrice > *req.MaxPrice {
-			err := fmt.Errorf("min_price (%d) cannot be greater than max_price (%d)", *req.MinPrice, *req.MaxPrice)
-			log.Printf("[validator:ad][ERROR] ValidateGetAllAdsRequest: %v", err)
-			return err
+	if req.Category != nil {
+		av.validateCategory(*req.Category, &vs)
+	}
+	for _, f := range req.FacetList() {
+		if _, ok := av.AllowedFacets[f]; !ok {
+			vs.add("facets", "unsupported", map[string]interface{}{"value": f})
 		}
 	}
 
+	if err := vs.err(); err != nil {
+		return err
+	}
+
 	log.Println("[validator:ad] ValidateGetAllAdsRequest succeeded")
 	return nil
 }
 
-func (av *AdAllowedValues) ValidateCreateAd(req dto.CreateAdRequest) error {
+// ValidateCreateAd проверяет объявление перед созданием; now — момент, с которым сравнивается publish_at.
+func (av *AdAllowedValues) ValidateCreateAd(req dto.CreateAdRequest, now time.Time) error {
 	log.Printf(
 		"[validator:ad] ValidateCreateAd called: titleLen=%d descriptionLen=%d price=%d imageURL=%q",
 		len(req.Title), len(req.Description), req.Price, req.ImageURL,
 	)
 
-	if err := av.validateTitle(req.Title); err != nil {
-		return err
+	var vs violations
+	av.validateTitle(req.Title, &vs)
+	av.validateDescription(req.Description, &vs)
+	av.validatePrice(req.Price, &vs)
+	av.validateImageURL(req.ImageURL, &vs)
+	av.validateSchedule(req.Draft, req.PublishAt, now, &vs)
+	if req.Category != "" {
+		av.validateCategory(req.Category, &vs)
 	}
-	if err := av.validateDescription(req.Description); err != nil {
+
+	if err := vs.err(); err != nil {
 		return err
 	}
-	if err := av.validatePrice(req.Price); err != nil {
-		return err
+
+	log.Println("[validator:ad] ValidateCreateAd succeeded")
+	return nil
+}
+
+// ErrNothingToUpdate — в запросе на изменение объявления нет ни одного поля.
+var ErrNothingToUpdate = apperror.Validation("ad.nothing_to_update")
+
+func (av *AdAllowedValues) ValidateUpdateAd(req dto.UpdateAdRequest) error {
+	log.Printf(
+		"[validator:ad] ValidateUpdateAd called: title=%t description=%t imageURL=%t price=%t",
+		req.Title != nil, req.Description != nil, req.ImageURL != nil, req.Price != nil,
+	)
+
+	if req.IsEmpty() {
+		log.Printf("[validator:ad][ERROR] ValidateUpdateAd: %v", ErrNothingToUpdate)
+		return ErrNothingToUpdate
+	}
+
+	var vs violations
+	if req.Title != nil {
+		av.validateTitle(*req.Title, &vs)
+	}
+	if req.Description != nil {
+		av.validateDescription(*req.Description, &vs)
+	}
+	if req.Price != nil {
+		av.validatePrice(*req.Price, &vs)
+	}
+	if req.ImageURL != nil {
+		av.validateImageURL(*req.ImageURL, &vs)
 	}
-	if err := av.validateImageURL(req.ImageURL); err != nil {
+
+	if err := vs.err(); err != nil {
 		return err
 	}
 
-	log.Println("[validator:ad] ValidateCreateAd succeeded")
+	log.Println("[validator:ad] ValidateUpdateAd succeeded")
 	return nil
 }
 
-func (av *AdAllowedValues) validateTitle(title string) error {
+func (av *AdAllowedValues) validateTitle(title string, vs *violations) {
 	ln := len(title)
 	log.Printf("[validator:ad] validateTitle: title=%q length=%d", title, ln)
 
 	if ln < av.MinTitleLen {
-		err := fmt.Errorf("title too short: %d < %d", ln, av.MinTitleLen)
-		log.Printf("[validator:ad][ERROR] validateTitle: %v", err)
-		return err
+		vs.add("title", "too_short", map[string]interface{}{"min": av.MinTitleLen, "actual": ln})
 	}
 	if ln > av.MaxTitleLen {
-		err := fmt.Errorf("title too long: %d > %d", ln, av.MaxTitleLen)
-		log.Printf("[validator:ad][ERROR] validateTitle: %v", err)
-		return err
+		vs.add("title", "too_long", map[string]interface{}{"max": av.MaxTitleLen, "actual": ln})
 	}
-
-	log.Println("[validator:ad] validateTitle succeeded")
-	return nil
 }
 
-func (av *AdAllowedValues) validateDescription(desc string) error {
+func (av *AdAllowedValues) validateDescription(desc string, vs *violations) {
 	ln := len(desc)
 	log.Printf("[validator:ad] validateDescription: length=%d", ln)
 
 	if ln < av.MinDescriptionLen {
-		err := fmt.Errorf("description too short: %d < %d", ln, av.MinDescriptionLen)
-		log.Printf("[validator:ad][ERROR] validateDescription: %v", err)
-		return err
+		vs.add("description", "too_short", map[string]interface{}{"min": av.MinDescriptionLen, "actual": ln})
 	}
 	if ln > av.MaxDescriptionLen {
-		err := fmt.Errorf("description too long: %d > %d", ln, av.MaxDescriptionLen)
-		log.Printf("[validator:ad][ERROR] validateDescription: %v", err)
-		return err
+		vs.add("description", "too_long", map[string]interface{}{"max": av.MaxDescriptionLen, "actual": ln})
 	}
-
-	log.Println("[validator:ad] validateDescription succeeded")
-	return nil
 }
 
-func (av *AdAllowedValues) validatePrice(price int) error {
+func (av *AdAllowedValues) validatePrice(price int, vs *violations) {
 	log.Printf("[validator:ad] validatePrice: price=%d", price)
 
 	if price < av.MinPrice {
-		err := fmt.Errorf("price must be at least %d", av.MinPrice)
-		log.Printf("[validator:ad][ERROR] validatePrice: %v", err)
-		return err
+		vs.add("price", "min", map[string]interface{}{"min": av.MinPrice})
 	}
 	if price > av.MaxPrice {
-		err := fmt.Errorf("price must be at most %d", av.MaxPrice)
-		log.Printf("[validator:ad][ERROR] validatePrice: %v", err)
-		return err
+		vs.add("price", "max", map[string]interface{}{"max": av.MaxPrice})
 	}
+}
 
-	log.Println("[validator:ad] validatePrice succeeded")
-	return nil
+func (av *AdAllowedValues) validateSchedule(draft bool, publishAt *time.Time, now time.Time, vs *violations) {
+	log.Printf("[validator:ad] validateSchedule: draft=%t publishAt=%v", draft, publishAt)
+
+	if publishAt == nil {
+		return
+	}
+	if draft {
+		vs.add("publish_at", "draft_scheduled", nil)
+		return
+	}
+	if !publishAt.After(now) {
+		vs.add("publish_at", "not_in_future", map[string]interface{}{"value": publishAt.Format(time.RFC3339)})
+	}
+}
+
+func (av *AdAllowedValues) validateCategory(category string, vs *violations) {
+	log.Printf("[validator:ad] validateCategory: category=%q", category)
+
+	if _, ok := av.AllowedCategories[category]; !ok {
+		vs.add("category", "unsupported", map[string]interface{}{"value": category})
+	}
 }
 
-func (av *AdAllowedValues) validateImageURL(url string) error {
+// validateImageURL останавливается на первом нарушении: следующие проверки зависят от ответа на HEAD-запрос.
+func (av *AdAllowedValues) validateImageURL(url string, vs *violations) {
 	log.Printf("[validator:ad] validateImageURL called: url=%q", url)
 
 	if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
-		err := errors.New("image url must start with http:// or https://")
-		log.Printf("[validator:ad][ERROR] validateImageURL: %v", err)
-		return err
+		vs.add("image_url", "invalid_scheme", nil)
+		return
 	}
 
 	client := http.Client{Timeout: 5 * time.Second}
 	resp, err := client.Head(url)
 	if err != nil {
 		log.Printf("[validator:ad][ERROR] validateImageURL HEAD failed: %v", err)
-		return fmt.Errorf("cannot HEAD image url: %w", err)
+		vs.add("image_url", "unreachable", nil)
+		return
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		err := fmt.Errorf("unexpected status code: %d", resp.StatusCode)
-		log.Printf("[validator:ad][ERROR] validateImageURL: %v", err)
-		return err
+		vs.add("image_url", "unexpected_status", map[string]interface{}{"status": resp.StatusCode})
+		return
 	}
 
 	ct := resp.Header.Get("Content-Type")
@@ -227,23 +273,19 @@ func (av *AdAllowedValues) validateImageURL(url string) error {
 		ct = ct[:idx]
 	}
 	if _, ok := av.AllowedImageTypes[ct]; !ok {
-		err := fmt.Errorf("unsupported content type: %s", ct)
-		log.Printf("[validator:ad][ERROR] validateImageURL: %v", err)
-		return err
+		vs.add("image_url", "unsupported_type", map[string]interface{}{"value": ct})
+		return
 	}
 
 	size := resp.ContentLength
 	if size <= 0 {
-		err := errors.New("content length is missing or zero")
-		log.Printf("[validator:ad][ERROR] validateImageURL: %v", err)
-		return err
+		vs.add("image_url", "missing_length", nil)
+		return
 	}
 	if size > av.MaxImageFileSize {
-		err := fmt.Errorf("image too large: %d > %d", size, av.MaxImageFileSize)
-		log.Printf("[validator:ad][ERROR] validateImageURL: %v", err)
-		return err
+		vs.add("image_url", "too_large", map[string]interface{}{"max": av.MaxImageFileSize, "actual": size})
+		return
 	}
 
 	log.Printf("[validator:ad] validateImageURL succeeded: contentType=%s size=%d", ct, size)
-	return nil
 }
@@ -0,0 +1,29 @@
+package validator
+
+import (
+	"log"
+
+	"github.com/1URose/marketplace/internal/common/apperror"
+	"github.com/1URose/marketplace/internal/common/i18n"
+)
+
+// ErrValidation — запрос нарушает ограничения объявлений; нарушения по полям перечислены в Violations.
+var ErrValidation = apperror.Validation("request.validation_failed")
+
+// violations собирает нарушения по всем полям запроса, чтобы клиент получил их разом, а не по одному.
+type violations []apperror.Violation
+
+// add записывает нарушение с ключом сообщения ad.<поле>.<код>; текст подставляется из params.
+func (vs *violations) add(field, code string, params map[string]interface{}) {
+	key := "ad." + field + "." + code
+	message := i18n.Message(i18n.Default, key, params)
+	log.Printf("[validator:ad][ERROR] %s: %s", field, message)
+	*vs = append(*vs, apperror.Violation{Field: field, Code: code, Key: key, Message: message, Params: params})
+}
+
+func (vs violations) err() error {
+	if len(vs) == 0 {
+		return nil
+	}
+	return ErrValidation.WithViolations(vs...)
+}
@@ -1,17 +1,66 @@
 package entity
 
-import "time"
+import (
+	"github.com/1URose/marketplace/internal/common/apperror"
+	"time"
+)
+
+// ErrEmailTaken — адрес уже занят другим пользователем.
+var ErrEmailTaken = apperror.Conflict("user.email_taken")
+
+const (
+	RoleUser = "user"
+	// RoleModerator разбирает жалобы и видит историю правок чужих объявлений.
+	RoleModerator = "moderator"
+	// RoleAdmin дополнительно управляет пользователями, ролями, токенами и продвижением.
+	RoleAdmin = "admin"
+)
 
 type User struct {
-	ID           int       `json:"id"`
-	Email        string    `json:"email,omitempty"`
-	PasswordHash string    `json:"password_hash,omitempty"`
-	CreatedAt    time.Time `json:"created_at,omitempty"`
+	ID    int    `json:"id"`
+	Email string `json:"email,omitempty"`
+	// PasswordHash никогда не отдаётся наружу; у пользователей, вошедших через внешнего провайдера, он пустой.
+	PasswordHash string     `json:"-"`
+	Role         string     `json:"role"`
+	CreatedAt    time.Time  `json:"created_at,omitempty"`
+	SuspendedAt  *time.Time `json:"suspended_at,omitempty"`
+	// EmailVerifiedAt — когда пользователь подтвердил владение адресом; nil, пока не подтвердил.
+	EmailVerifiedAt *time.Time `json:"email_verified_at,omitempty"`
+}
+
+func (u *User) IsSuspended() bool {
+	return u.SuspendedAt != nil
+}
+
+func (u *User) IsEmailVerified() bool {
+	return u.EmailVerifiedAt != nil
 }
 
 func NewUser(email, passwordHash string) *User {
 	return &User{
 		Email:        email,
 		PasswordHash: passwordHash,
+		Role:         RoleUser,
+	}
+}
+
+func IsValidRole(role string) bool {
+	return role == RoleUser || role == RoleModerator || role == RoleAdmin
+}
+
+// IsModeratorRole сообщает, даёт ли роль права модератора; у администратора они тоже есть.
+func IsModeratorRole(role string) bool {
+	return role == RoleModerator || role == RoleAdmin
+}
+
+// RoleRank упорядочивает роли по объёму прав: понижение роли требует отозвать уже выданные токены.
+func RoleRank(role string) int {
+	switch role {
+	case RoleAdmin:
+		return 2
+	case RoleModerator:
+		return 1
+	default:
+		return 0
 	}
 }
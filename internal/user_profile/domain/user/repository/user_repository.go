@@ -3,10 +3,18 @@ package repository
 import (
 	"context"
 	"github.com/1URose/marketplace/internal/user_profile/domain/user/entity"
+	"time"
 )
 
 type UserRepository interface {
 	CreateUser(ctx context.Context, user *entity.User) (*entity.User, error)
 	GetUserByEmail(ctx context.Context, email string) (*entity.User, error)
+	GetUserByID(ctx context.Context, id int) (*entity.User, error)
 	GetAllUsers(ctx context.Context) ([]entity.User, error)
+	SetRole(ctx context.Context, id int, role string) error
+	UpdatePassword(ctx context.Context, id int, passwordHash string) error
+	// MarkEmailVerified подтверждает email, если он всё ещё совпадает с email; false — адрес сменился или уже подтверждён.
+	MarkEmailVerified(ctx context.Context, id int, email string, at time.Time) (bool, error)
+	// UpdateEmail переключает пользователя на уже подтверждённый адрес; entity.ErrEmailTaken — адрес занят.
+	UpdateEmail(ctx context.Context, id int, email string, verifiedAt time.Time) error
 }
@@ -7,10 +7,13 @@ import (
 	"github.com/1URose/marketplace/internal/common/db/postgresql"
 	"github.com/1URose/marketplace/internal/user_profile/domain/user/entity"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"log"
 	"time"
 )
 
+const pgUniqueViolation = "23505"
+
 type UserRepository struct {
 	Connection *postgresql.Client
 }
@@ -28,16 +31,29 @@ func (ur *UserRepository) CreateUser(ctx context.Context, user *entity.User) (*e
 	log.Printf("[postgresql:user_repo] CreateUser called: email=%q", user.Email)
 
 	query := `
-        INSERT INTO users (email, password_hash)
-        VALUES ($1, $2)
+        INSERT INTO users (email, password_hash, role)
+        VALUES ($1, $2, $3)
         RETURNING id, created_at
     `
 	var userID int
 
 	var createdAt time.Time
 
-	if err := ur.Connection.GetPool().QueryRow(ctx, query, user.Email, user.PasswordHash).
-		Scan(&userID, &createdAt); err != nil {
+	if user.Role == "" {
+		user.Role = entity.RoleUser
+	}
+
+	err := ur.Connection.GetPool().QueryRow(ctx, query, user.Email, user.PasswordHash, user.Role).
+		Scan(&userID, &createdAt)
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == pgUniqueViolation {
+
+		log.Printf("[postgresql:user_repo][ERROR] CreateUser: email=%q already taken", user.Email)
+
+		return nil, entity.ErrEmailTaken
+	}
+	if err != nil {
 
 		log.Printf("[postgresql:user_repo][ERROR] insert query failed: %v", err)
 
@@ -66,8 +82,9 @@ func (ur *UserRepository) GetAllUsers(ctx context.Context) ([]entity.User, error
 	log.Println("[postgresql:user_repo] GetAllUsers called")
 
 	query := `
-        SELECT id, email, password_hash, created_at
+        SELECT id, email, password_hash, role, created_at, suspended_at, email_verified_at
         FROM users
+        ORDER BY id
     `
 	rows, err := ur.Connection.GetPool().Query(ctx, query)
 
@@ -86,7 +103,7 @@ func (ur *UserRepository) GetAllUsers(ctx context.Context) ([]entity.User, error
 
 		var u entity.User
 
-		if err = rows.Scan(&u.ID, &u.Email, &u.PasswordHash, &u.CreatedAt); err != nil {
+		if err = rows.Scan(&u.ID, &u.Email, &u.PasswordHash, &u.Role, &u.CreatedAt, &u.SuspendedAt, &u.EmailVerifiedAt); err != nil {
 
 			log.Printf("[postgresql:user_repo][ERROR] scan failed: %v", err)
 
@@ -101,14 +118,77 @@ func (ur *UserRepository) GetAllUsers(ctx context.Context) ([]entity.User, error
 	return users, nil
 }
 
-func (ur *UserRepository) UpdateEmail(ctx context.Context, id int, email string) error {
+func (ur *UserRepository) SetRole(ctx context.Context, id int, role string) error {
+	log.Printf("[postgresql:user_repo] SetRole called: id=%d role=%s", id, role)
+
+	const query = `
+        UPDATE users
+        SET role = $1
+        WHERE id = $2
+    `
+	tag, err := ur.Connection.GetPool().Exec(ctx, query, role, id)
+	if err != nil {
+		log.Printf("[postgresql:user_repo][ERROR] SetRole failed: %v", err)
+		return fmt.Errorf("set role failed: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("no user to update with id=%d", id)
+	}
+	return nil
+}
+
+func (ur *UserRepository) UpdatePassword(ctx context.Context, id int, passwordHash string) error {
+	log.Printf("[postgresql:user_repo] UpdatePassword called: id=%d", id)
+
 	const query = `
         UPDATE users
-        SET email = $1
+        SET password_hash = $1
         WHERE id = $2
     `
-	tag, err := ur.Connection.GetPool().Exec(ctx, query, email, id)
+	tag, err := ur.Connection.GetPool().Exec(ctx, query, passwordHash, id)
+	if err != nil {
+		log.Printf("[postgresql:user_repo][ERROR] UpdatePassword failed: %v", err)
+		return fmt.Errorf("update password failed: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("no user to update with id=%d", id)
+	}
+	return nil
+}
+
+func (ur *UserRepository) MarkEmailVerified(ctx context.Context, id int, email string, at time.Time) (bool, error) {
+	log.Printf("[postgresql:user_repo] MarkEmailVerified called: id=%d", id)
+
+	// адрес сверяется, чтобы ссылка, отправленная на прежний email, не подтвердила новый
+	const query = `
+        UPDATE users
+        SET email_verified_at = $3
+        WHERE id = $1 AND email = $2 AND email_verified_at IS NULL
+    `
+	tag, err := ur.Connection.GetPool().Exec(ctx, query, id, email, at)
+	if err != nil {
+		log.Printf("[postgresql:user_repo][ERROR] MarkEmailVerified failed: %v", err)
+		return false, fmt.Errorf("mark email verified failed: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+func (ur *UserRepository) UpdateEmail(ctx context.Context, id int, email string, verifiedAt time.Time) error {
+	log.Printf("[postgresql:user_repo] UpdateEmail called: id=%d", id)
+
+	const query = `
+        UPDATE users
+        SET email = $1, email_verified_at = $2
+        WHERE id = $3
+    `
+	tag, err := ur.Connection.GetPool().Exec(ctx, query, email, verifiedAt, id)
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == pgUniqueViolation {
+		log.Printf("[postgresql:user_repo][ERROR] UpdateEmail: email already taken")
+		return entity.ErrEmailTaken
+	}
 	if err != nil {
+		log.Printf("[postgresql:user_repo][ERROR] UpdateEmail failed: %v", err)
 		return fmt.Errorf("update email failed: %w", err)
 	}
 	if tag.RowsAffected() == 0 {
@@ -119,7 +199,7 @@ func (ur *UserRepository) UpdateEmail(ctx context.Context, id int, email string)
 
 func (ur *UserRepository) fetchOne(ctx context.Context, where string, args ...interface{}) (*entity.User, error) {
 	const baseQuery = `
-        SELECT id, email, password_hash, created_at
+        SELECT id, email, password_hash, role, created_at, suspended_at, email_verified_at
         FROM users
         WHERE %s
     `
@@ -128,7 +208,7 @@ func (ur *UserRepository) fetchOne(ctx context.Context, where string, args ...in
 	var u entity.User
 	err := ur.Connection.GetPool().
 		QueryRow(ctx, query, args...).
-		Scan(&u.ID, &u.Email, &u.PasswordHash, &u.CreatedAt)
+		Scan(&u.ID, &u.Email, &u.PasswordHash, &u.Role, &u.CreatedAt, &u.SuspendedAt, &u.EmailVerifiedAt)
 
 	if errors.Is(err, pgx.ErrNoRows) {
 		log.Printf("[postgresql:user_repo] fetchOne: no rows for %q", where)
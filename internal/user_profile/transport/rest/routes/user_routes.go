@@ -2,6 +2,7 @@ package routes
 
 import (
 	"context"
+	"github.com/1URose/marketplace/internal/auth_signup/transport/rest/auth"
 	"github.com/1URose/marketplace/internal/common/app"
 	pgConfig "github.com/1URose/marketplace/internal/common/db/postgresql"
 	pgRepo "github.com/1URose/marketplace/internal/user_profile/infrastructure/repository/postgresql"
@@ -13,16 +14,18 @@ import (
 )
 
 type UserRoute struct {
-	PGClient *pgConfig.Client
-	engine   *gin.Engine
-	ctx      context.Context
+	PGClient       *pgConfig.Client
+	engine         *gin.Engine
+	ctx            context.Context
+	authMiddleware *auth.Middleware
 }
 
 func NewUserRoute(deps *app.Deps) *UserRoute {
 	return &UserRoute{
-		PGClient: deps.DB.PostgresConn,
-		engine:   deps.Engine,
-		ctx:      deps.Ctx,
+		PGClient:       deps.DB.PostgresConn,
+		engine:         deps.Engine,
+		ctx:            deps.Ctx,
+		authMiddleware: deps.AuthMiddleware,
 	}
 }
 
@@ -42,7 +45,7 @@ func (ur *UserRoute) RegisterRoutes() {
 
 	log.Println("[routers:user] registering /user endpoints")
 
-	api := ur.engine.Group("/user")
+	api := ur.engine.Group("/user").Use(ur.authMiddleware.RequireAdmin())
 
 	service := initUserService(ur.PGClient)
 
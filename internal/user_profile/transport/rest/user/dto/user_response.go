@@ -6,17 +6,25 @@ import (
 )
 
 type UserResponse struct {
-	ID           int    `json:"id"`
-	Email        string `json:"email"`
-	PasswordHash string `json:"password_hash,omitempty"`
-	CreatedAt    string `json:"created_at"`
+	ID            int    `json:"id"`
+	Email         string `json:"email"`
+	Role          string `json:"role"`
+	EmailVerified bool   `json:"email_verified"`
+	CreatedAt     string `json:"created_at"`
+	SuspendedAt   string `json:"suspended_at,omitempty"`
 }
 
 func NewUserResponse(user *entity.User) *UserResponse {
 	createdAt := user.CreatedAt.Format(time.RFC3339)
-	return &UserResponse{
-		ID:        user.ID,
-		Email:     user.Email,
-		CreatedAt: createdAt,
+	resp := &UserResponse{
+		ID:            user.ID,
+		Email:         user.Email,
+		Role:          user.Role,
+		EmailVerified: user.IsEmailVerified(),
+		CreatedAt:     createdAt,
 	}
+	if user.SuspendedAt != nil {
+		resp.SuspendedAt = user.SuspendedAt.Format(time.RFC3339)
+	}
+	return resp
 }
@@ -1,7 +1,6 @@
 package user
 
 import (
-	dtoErr "github.com/1URose/marketplace/internal/common/transport/rest/dto"
 	"github.com/1URose/marketplace/internal/user_profile/transport/rest/user/dto"
 	"github.com/1URose/marketplace/internal/user_profile/use_cases"
 	"github.com/gin-gonic/gin"
@@ -23,10 +22,13 @@ func NewUserHandler(userService *use_cases.UserService) *Handler {
 
 // GetAllUsers godoc
 // @Summary Получение всех пользователей
-// @Description Возвращает список всех пользователей вместе с их данными(Хэш пароль в частности - для тестирования)
+// @Description Возвращает список всех пользователей с ролями. Только для администраторов
 // @Tags user
 // @Produce json
-// @Success 200 {object} dto.UserResponse
+// @Param Authorization header string true "JWT Access token администратора"
+// @Success 200 {array} dto.UserResponse
+// @Failure 401 {object} dto.ErrorResponse "Неавторизован"
+// @Failure 403 {object} dto.ErrorResponse "Нет роли администратора"
 // @Failure 500 {object} dto.ErrorResponse
 // @Router /user [get]
 func (u *Handler) GetAllUsers(ctx *gin.Context) {
@@ -38,10 +40,7 @@ func (u *Handler) GetAllUsers(ctx *gin.Context) {
 
 		log.Printf("[handler:user][ERROR] service.GetAllUsers: %v", err)
 
-		ctx.JSON(http.StatusInternalServerError, dtoErr.ErrorResponse{
-			Error:  "Failed to get all users",
-			Detail: err.Error(),
-		})
+		ctx.Error(err)
 
 		return
 	}
@@ -49,10 +48,7 @@ func (u *Handler) GetAllUsers(ctx *gin.Context) {
 
 	usersResponse := make([]*dto.UserResponse, 0, len(users))
 	for _, user := range users {
-		currentUser := dto.NewUserResponse(&user)
-		currentUser.PasswordHash = user.PasswordHash
-
-		usersResponse = append(usersResponse, currentUser)
+		usersResponse = append(usersResponse, dto.NewUserResponse(&user))
 	}
 
 	ctx.JSON(http.StatusOK, usersResponse)
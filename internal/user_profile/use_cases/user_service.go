@@ -3,11 +3,15 @@ package use_cases
 import (
 	"context"
 	"fmt"
+	"github.com/1URose/marketplace/internal/common/password"
 	"github.com/1URose/marketplace/internal/user_profile/domain/user/entity"
 	"github.com/1URose/marketplace/internal/user_profile/domain/user/repository"
 	"log"
 )
 
+// minAdminPasswordLen совпадает с ограничением формы регистрации.
+const minAdminPasswordLen = 8
+
 type UserService struct {
 	Repo repository.UserRepository
 }
@@ -37,3 +41,67 @@ func (us *UserService) GetAllUsers(ctx context.Context) ([]entity.User, error) {
 
 	return users, nil
 }
+
+// EnsureAdmin делает пользователя с этим email администратором. Если пользователя нет, он создаётся
+// с паролем, который запрашивается через readPassword только в этом случае.
+func (us *UserService) EnsureAdmin(ctx context.Context, email string, readPassword func() (string, error)) (*entity.User, bool, error) {
+
+	log.Printf("[usecase:user] EnsureAdmin called: email=%q", email)
+
+	user, err := us.Repo.GetUserByEmail(ctx, email)
+
+	if err != nil {
+
+		log.Printf("[usecase:user][ERROR] EnsureAdmin: %v", err)
+
+		return nil, false, fmt.Errorf("get user by email: %w", err)
+	}
+
+	if user != nil {
+
+		if user.Role != entity.RoleAdmin {
+
+			if err := us.Repo.SetRole(ctx, user.ID, entity.RoleAdmin); err != nil {
+				return nil, false, fmt.Errorf("set role: %w", err)
+			}
+
+			user.Role = entity.RoleAdmin
+		}
+
+		log.Printf("[usecase:user] EnsureAdmin: existing user id=%d is admin", user.ID)
+
+		return user, false, nil
+	}
+
+	plain, err := readPassword()
+
+	if err != nil {
+		return nil, false, fmt.Errorf("read password: %w", err)
+	}
+
+	if len(plain) < minAdminPasswordLen {
+		return nil, false, fmt.Errorf("password must be at least %d characters", minAdminPasswordLen)
+	}
+
+	hash, err := password.HashPassword(plain)
+
+	if err != nil {
+		return nil, false, fmt.Errorf("hash password: %w", err)
+	}
+
+	admin := entity.NewUser(email, hash)
+	admin.Role = entity.RoleAdmin
+
+	created, err := us.Repo.CreateUser(ctx, admin)
+
+	if err != nil {
+
+		log.Printf("[usecase:user][ERROR] EnsureAdmin: %v", err)
+
+		return nil, false, fmt.Errorf("create user: %w", err)
+	}
+
+	log.Printf("[usecase:user] EnsureAdmin: created admin id=%d", created.ID)
+
+	return created, true, nil
+}